@@ -19,32 +19,92 @@ package abm
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 
 	"github.com/go-json-experiment/json"
+	"golang.org/x/oauth2"
 )
 
+// FetchOrgDevicePartNumbers returns all org-device part numbers for the
+// organization, using a client for [DefaultAPIBaseURL] built from httpClient
+// and tokenSource. Results and errors are those of [NewClient] followed by
+// [Client.FetchOrgDevicePartNumbers].
+//
+// Deprecated: Create a [Client] once with [NewClient] and call
+// [Client.FetchOrgDevicePartNumbers], which reuses the client's connections
+// and token cache across calls.
+func FetchOrgDevicePartNumbers(ctx context.Context, httpClient *http.Client, tokenSource oauth2.TokenSource) ([]string, error) {
+	client, err := NewClient(httpClient, tokenSource)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.FetchOrgDevicePartNumbers(ctx)
+}
+
 // FetchOrgDevicePartNumbers returns all org-device part numbers for the organization,
 // automatically following pagination until all pages are consumed.
-func (c *Client) FetchOrgDevicePartNumbers(ctx context.Context) ([]string, error) {
+//
+// When the crawl is bounded with [WithMaxItems] and more part numbers are
+// available, the collected part numbers are returned together with [ErrMaxItemsReached].
+func (c *Client) FetchOrgDevicePartNumbers(ctx context.Context, opts ...CrawlOption) ([]string, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
 
-	baseURL, err := c.buildURL(orgDevicesPath, nil)
+	query, err := buildFieldsAndLimitQuery("fields[orgDevices]", nil, c.crawlPageSize)
 	if err != nil {
 		return nil, err
 	}
 
-	partNumbers := make([]string, 0, 64)
+	crawl := newCrawlOptions(opts)
+	if crawl.tokenScanner {
+		return collectPartNumbers(ctx, c, query, crawl.maxItems, opts,
+			func(p *partNumberPage) string { return p.Next },
+			func(p *partNumberPage) []string { return p.PartNumbers })
+	}
+
+	return collectPartNumbers(ctx, c, query, crawl.maxItems, opts,
+		func(r *OrgDevicesResponse) string { return r.Links.Next },
+		func(r *OrgDevicesResponse) []string {
+			partNumbers := make([]string, len(r.Data))
+			for i := range r.Data {
+				partNumbers[i] = r.Data[i].PartNumber()
+			}
+			return partNumbers
+		})
+}
 
-	for pagePartNumbers, err := range PageIterator(ctx, c.httpClient, decodeOrgDevices, baseURL) {
+// collectPartNumbers crawls the org devices with pages decoded as T and
+// collects the part numbers partNumbers returns for each page, up to
+// maxItems.
+func collectPartNumbers[T any](ctx context.Context, c *Client, query url.Values, maxItems int, opts []CrawlOption, next func(*T) string, partNumbers func(*T) []string) ([]string, error) {
+	all := make([]string, 0, 64)
+	for page, err := range crawlPages(ctx, c, orgDevicesPath, query, next, opts...) {
 		if err != nil {
 			return nil, err
 		}
-		partNumbers = append(partNumbers, pagePartNumbers...)
+		all, err = appendPage(all, partNumbers(page), maxItems)
+		if err != nil {
+			return all, err
+		}
 	}
 
-	return partNumbers, nil
+	return all, nil
+}
+
+// partNumberPage is an org devices page decoded with [scanOrgDevices], see
+// [WithTokenScanner].
+type partNumberPage struct {
+	PartNumbers []string
+	Next        string
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (p *partNumberPage) UnmarshalJSON(payload []byte) (err error) {
+	p.PartNumbers, p.Next, err = scanOrgDevices(payload)
+	return err
 }
 
 func decodeOrgDevices(payload []byte) ([]string, string, error) {
@@ -54,10 +114,8 @@ func decodeOrgDevices(payload []byte) ([]string, string, error) {
 	}
 
 	partNumbers := make([]string, len(response.Data))
-	for i, device := range response.Data {
-		if device.Attributes != nil {
-			partNumbers[i] = device.Attributes.PartNumber
-		}
+	for i := range response.Data {
+		partNumbers[i] = response.Data[i].PartNumber()
 	}
 
 	return partNumbers, response.Links.Next, nil
@@ -17,9 +17,12 @@
 package abm
 
 import (
+	"encoding/csv"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
 	"strconv"
 	"strings"
 	"testing"
@@ -68,6 +71,47 @@ func BenchmarkDecodeOrgDevices(b *testing.B) {
 	}
 }
 
+func BenchmarkScanOrgDevices(b *testing.B) {
+	ctx := b.Context()
+	if err := ctx.Err(); err != nil {
+		b.Fatalf("context error: %v", err)
+	}
+
+	payloadSizes := map[string]int{
+		"small_25":   25,
+		"medium_200": 200,
+		"large_1000": 1000,
+	}
+
+	for name, deviceCount := range payloadSizes {
+		b.Run(name, func(b *testing.B) {
+			ctx := b.Context()
+			if err := ctx.Err(); err != nil {
+				b.Fatalf("context error: %v", err)
+			}
+
+			payload := buildOrgDevicesPayload(deviceCount, "/v1/orgDevices?page=next")
+			wantCount := deviceCount
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for b.Loop() {
+				partNumbers, next, err := scanOrgDevices(payload)
+				if err != nil {
+					b.Fatalf("scanOrgDevices returned error: %v", err)
+				}
+				if got := len(partNumbers); got != wantCount {
+					b.Fatalf("part numbers length mismatch: got=%d want=%d", got, wantCount)
+				}
+				if next != "/v1/orgDevices?page=next" {
+					b.Fatalf("next link mismatch: got=%q want=%q", next, "/v1/orgDevices?page=next")
+				}
+			}
+		})
+	}
+}
+
 func BenchmarkClientFetchOrgDevicePartNumbers(b *testing.B) {
 	ctx := b.Context()
 	if err := ctx.Err(); err != nil {
@@ -136,6 +180,90 @@ func BenchmarkClientFetchOrgDevicePartNumbers(b *testing.B) {
 	}
 }
 
+func BenchmarkClientExportOrgDevicesCSV(b *testing.B) {
+	ctx := b.Context()
+	if err := ctx.Err(); err != nil {
+		b.Fatalf("context error: %v", err)
+	}
+
+	const (
+		pageSize  = 100
+		pageCount = 10
+	)
+	wantRows := pageSize * pageCount
+
+	server := newOrgDevicesFleetServer(b, pageCount, pageSize)
+	tokenSource := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "bench-token"})
+	client, err := NewClientWithBaseURL(server.Client(), tokenSource, server.URL)
+	if err != nil {
+		b.Fatalf("NewClientWithBaseURL returned error: %v", err)
+	}
+
+	// collectThenWrite is the baseline the streaming export replaces: every
+	// device is held in memory before the first row is written.
+	collectThenWrite := func(w io.Writer) (int, error) {
+		var devices []OrgDevice
+		for page, err := range crawlPages(ctx, client, orgDevicesPath, nil, func(r *OrgDevicesResponse) string { return r.Links.Next }) {
+			if err != nil {
+				return 0, err
+			}
+			devices = append(devices, page.Data...)
+		}
+
+		cw := csv.NewWriter(w)
+		cw.Write(append([]string{"id"}, orgDeviceExportFields...))
+		record := make([]string, len(orgDeviceExportFields)+1)
+		for _, device := range devices {
+			record[0] = device.ID
+			for i, field := range orgDeviceExportFields {
+				record[i+1] = orgDeviceExportColumns[field](device.Attributes)
+			}
+			cw.Write(record)
+		}
+		cw.Flush()
+
+		return len(devices), cw.Error()
+	}
+
+	exporters := map[string]func(w io.Writer) (int, error){
+		"streaming": func(w io.Writer) (int, error) {
+			return client.ExportOrgDevicesCSV(ctx, w, nil)
+		},
+		"collect_then_write": collectThenWrite,
+	}
+
+	for name, export := range exporters {
+		b.Run(name, func(b *testing.B) {
+			ctx := b.Context()
+			if err := ctx.Err(); err != nil {
+				b.Fatalf("context error: %v", err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			// Allocations include the in-process fake server, which is the
+			// same for both exporters.
+			var before, after runtime.MemStats
+			runtime.ReadMemStats(&before)
+			for b.Loop() {
+				rows, err := export(io.Discard)
+				if err != nil {
+					b.Fatalf("export returned error: %v", err)
+				}
+				if rows != wantRows {
+					b.Fatalf("row count mismatch: got=%d want=%d", rows, wantRows)
+				}
+			}
+			runtime.ReadMemStats(&after)
+
+			devices := float64(b.N * wantRows)
+			b.ReportMetric(float64(after.Mallocs-before.Mallocs)/devices, "allocs/device")
+			b.ReportMetric(float64(after.TotalAlloc-before.TotalAlloc)/devices, "B/device")
+		})
+	}
+}
+
 func buildOrgDevicesPayload(deviceCount int, nextLink string) []byte {
 	return buildOrgDevicesPageJSON(1, deviceCount, nextLink)
 }
@@ -150,7 +278,7 @@ func buildOrgDevicesPageJSON(pageNumber, pageSize int, nextLink string) []byte {
 			builder.WriteByte(',')
 		}
 		partNumber := fmt.Sprintf("PART-%04d-%05d", pageNumber, i+1)
-		fmt.Fprintf(&builder, `{"id":"device-%d-%d","type":"orgDevices","attributes":{"partNumber":"%s","status":"ASSIGNED","productFamily":"iPhone","deviceModel":"iPhone 15 Pro","orderNumber":"ORDER-%04d","orderDateTime":"2026-01-02T03:04:05Z","updatedDateTime":"2026-01-03T04:05:06Z","addedToOrgDateTime":"2026-01-04T05:06:07Z","serialNumber":"SER-%d-%d","productType":"iPhone16,2","deviceCapacity":"256GB","purchaseSourceType":"APPLE","purchaseSourceId":"PS-%04d","imei":["123456789012345","123456789012346"],"meid":["A1000000000001"],"wifiMacAddress":"00:11:22:33:44:55","bluetoothMacAddress":"66:77:88:99:AA:BB","ethernetMacAddress":["CC:DD:EE:FF:00:11"]},"links":{"self":"/v1/orgDevices/%d"},"relationships":{"assignedServer":{"links":{"related":"/v1/servers/1"}}}}`, pageNumber, i+1, partNumber, pageNumber, pageNumber, i+1, pageNumber, pageNumber*10000+i+1)
+		fmt.Fprintf(&builder, `{"id":"device-%d-%d","type":"orgDevices","attributes":{"partNumber":"%s","status":"ASSIGNED","productFamily":"iPhone","deviceModel":"iPhone 15 Pro","orderNumber":"ORDER-%04d","orderDateTime":"2026-01-02T03:04:05Z","updatedDateTime":"2026-01-03T04:05:06Z","addedToOrgDateTime":"2026-01-04T05:06:07Z","serialNumber":"SER-%d-%d","productType":"iPhone16,2","deviceCapacity":"256GB","purchaseSourceType":"APPLE","purchaseSourceId":"PS-%04d","imei":["123456789012345","123456789012346"],"meid":["A1000000000001"],"wifiMacAddress":["00:11:22:33:44:55"],"bluetoothMacAddress":["66:77:88:99:AA:BB"],"ethernetMacAddress":["CC:DD:EE:FF:00:11"]},"links":{"self":"/v1/orgDevices/%d"},"relationships":{"assignedServer":{"links":{"related":"/v1/servers/1"}}}}`, pageNumber, i+1, partNumber, pageNumber, pageNumber, i+1, pageNumber, pageNumber*10000+i+1)
 	}
 	builder.WriteString(`],"links":{"next":"`)
 	builder.WriteString(nextLink)
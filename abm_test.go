@@ -100,6 +100,7 @@ func TestClient_FetchOrgDevicePartNumbersSuccess(t *testing.T) {
 	}
 
 	tests := map[string]struct {
+		opts         []CrawlOption
 		want         []string
 		wantRequests int32
 	}{
@@ -107,6 +108,11 @@ func TestClient_FetchOrgDevicePartNumbersSuccess(t *testing.T) {
 			want:         []string{"PART-001", "PART-002"},
 			wantRequests: 2,
 		},
+		"success: two pages with token scanner": {
+			opts:         []CrawlOption{WithTokenScanner()},
+			want:         []string{"PART-001", "PART-002"},
+			wantRequests: 2,
+		},
 	}
 
 	for name, tt := range tests {
@@ -127,7 +133,7 @@ func TestClient_FetchOrgDevicePartNumbersSuccess(t *testing.T) {
 				}
 
 				switch r.URL.RawQuery {
-				case "":
+				case "limit=100":
 					w.Header().Set("Content-Type", "application/json")
 					fmt.Fprint(w, `{"data":[{"attributes":{"partNumber":"PART-001"}}],"links":{"next":"/v1/orgDevices?page=2"}}`)
 				case "page=2":
@@ -151,7 +157,7 @@ func TestClient_FetchOrgDevicePartNumbersSuccess(t *testing.T) {
 				t.Fatalf("NewClientWithBaseURL returned error: %v", err)
 			}
 
-			got, err := client.FetchOrgDevicePartNumbers(ctx)
+			got, err := client.FetchOrgDevicePartNumbers(ctx, tt.opts...)
 			if err != nil {
 				t.Fatalf("FetchOrgDevicePartNumbers returned error: %v", err)
 			}
@@ -164,3 +170,165 @@ func TestClient_FetchOrgDevicePartNumbersSuccess(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_FetchOrgDevicePartNumbersMaxItems(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		maxItems     int
+		want         []string
+		wantRequests int32
+		wantErr      error
+	}{
+		"success: unbounded": {
+			want:         []string{"PART-001", "PART-002", "PART-003", "PART-004", "PART-005"},
+			wantRequests: 2,
+		},
+		"success: bound equals total": {
+			maxItems:     5,
+			want:         []string{"PART-001", "PART-002", "PART-003", "PART-004", "PART-005"},
+			wantRequests: 2,
+		},
+		"error: bound reached mid-page": {
+			maxItems:     4,
+			want:         []string{"PART-001", "PART-002", "PART-003", "PART-004"},
+			wantRequests: 2,
+			wantErr:      ErrMaxItemsReached,
+		},
+		"error: bound reached on first page": {
+			maxItems:     2,
+			want:         []string{"PART-001", "PART-002"},
+			wantRequests: 1,
+			wantErr:      ErrMaxItemsReached,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			var requestCount int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requestCount, 1)
+
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.RawQuery {
+				case "limit=100":
+					fmt.Fprint(w, `{"data":[{"attributes":{"partNumber":"PART-001"}},{"attributes":{"partNumber":"PART-002"}},{"attributes":{"partNumber":"PART-003"}}],"links":{"next":"/v1/orgDevices?page=2"}}`)
+				case "page=2":
+					fmt.Fprint(w, `{"data":[{"attributes":{"partNumber":"PART-004"}},{"attributes":{"partNumber":"PART-005"}}],"links":{}}`)
+				default:
+					w.WriteHeader(http.StatusBadRequest)
+				}
+			}))
+			t.Cleanup(server.Close)
+
+			client := testClientForServer(t, server)
+			got, err := client.FetchOrgDevicePartNumbers(ctx, WithMaxItems(tt.maxItems))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("FetchOrgDevicePartNumbers error mismatch: err=%v want=%v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("FetchOrgDevicePartNumbers returned error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("part numbers mismatch (-want +got):\n%s", diff)
+			}
+			if count := atomic.LoadInt32(&requestCount); count != tt.wantRequests {
+				t.Fatalf("unexpected request count: got=%d want=%d", count, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestFetchOrgDevicePartNumbersLegacyParity(t *testing.T) {
+	tests := map[string]struct {
+		tokenSource oauth2.TokenSource
+		cancel      bool
+		status      int
+		want        []string
+		wantErr     error
+	}{
+		"success: two pages": {
+			tokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token"}),
+			want:        []string{"PART-001", "PART-002"},
+		},
+		"error: nil token source": {
+			tokenSource: nil,
+		},
+		"error: canceled context": {
+			tokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token"}),
+			cancel:      true,
+			wantErr:     context.Canceled,
+		},
+		"error: server error": {
+			tokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token"}),
+			status:      http.StatusInternalServerError,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+					fmt.Fprint(w, `{"errors":[{"status":"500","code":"INTERNAL_ERROR","title":"boom","detail":"boom"}]}`)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if r.URL.Query().Get("page") == "2" {
+					fmt.Fprint(w, `{"data":[{"attributes":{"partNumber":"PART-002"}}],"links":{}}`)
+					return
+				}
+				fmt.Fprint(w, `{"data":[{"attributes":{"partNumber":"PART-001"}}],"links":{"next":"/v1/orgDevices?page=2"}}`)
+			}))
+			t.Cleanup(server.Close)
+
+			httpClient, err := newTLSServerHTTPClient(server)
+			if err != nil {
+				t.Fatalf("newTLSServerHTTPClient returned error: %v", err)
+			}
+			if tt.cancel {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithCancel(ctx)
+				cancel()
+			}
+
+			legacy, legacyErr := FetchOrgDevicePartNumbers(ctx, httpClient, tt.tokenSource)
+
+			var current []string
+			client, currentErr := NewClient(httpClient, tt.tokenSource)
+			if currentErr == nil {
+				current, currentErr = client.FetchOrgDevicePartNumbers(ctx)
+			}
+
+			if diff := cmp.Diff(current, legacy); diff != "" {
+				t.Fatalf("part numbers differ between entry points (-current +legacy):\n%s", diff)
+			}
+			if (currentErr == nil) != (legacyErr == nil) || (currentErr != nil && currentErr.Error() != legacyErr.Error()) {
+				t.Fatalf("errors differ between entry points: current=%v legacy=%v", currentErr, legacyErr)
+			}
+			if tt.wantErr != nil && !errors.Is(legacyErr, tt.wantErr) {
+				t.Fatalf("legacy error = %v, want %v", legacyErr, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, legacy); diff != "" {
+				t.Fatalf("part numbers mismatch (-want +got):\n%s", diff)
+			}
+			if tt.want == nil && legacyErr == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
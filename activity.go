@@ -0,0 +1,652 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Resource type names used in JSON:API resource identifiers.
+const (
+	orgDevicesResourceType          = "orgDevices"
+	mdmServersResourceType          = "mdmServers"
+	orgDeviceActivitiesResourceType = "orgDeviceActivities"
+)
+
+// MaxDevicesPerActivity is the maximum number of devices a single org-device
+// activity accepts. The bulk helpers create one activity per batch of at
+// most this many devices.
+const MaxDevicesPerActivity = 1000
+
+// ErrTooManyDevices is returned when an org-device activity names more
+// devices than Apple accepts, whether detected by
+// [OrgDeviceActivityCreateRequest.Validate] before sending or reported by the
+// API. Use errors.As with [*TooManyDevicesError] for the counts.
+var ErrTooManyDevices = errors.New("too many devices for one activity")
+
+// TooManyDevicesError reports an org-device activity with more devices than
+// allowed.
+type TooManyDevicesError struct {
+	// Count is the number of devices in the request.
+	Count int
+
+	// Max is the maximum number of devices per activity. It is
+	// [MaxDevicesPerActivity] unless the API reported another limit in the
+	// meta of its error.
+	Max int
+
+	// Err is the API's error when the API rejected the request, or nil when
+	// the request was not sent.
+	Err error
+}
+
+func (e *TooManyDevicesError) Error() string {
+	msg := fmt.Sprintf("%v: %d devices, at most %d devices are allowed per activity", ErrTooManyDevices, e.Count, e.Max)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+
+	return msg
+}
+
+// Is reports whether target is [ErrTooManyDevices].
+func (e *TooManyDevicesError) Is(target error) bool {
+	return target == ErrTooManyDevices
+}
+
+func (e *TooManyDevicesError) Unwrap() error {
+	return e.Err
+}
+
+// devicesPointers are the JSON pointers to the device list of an activity
+// creation request, as opposed to a single device in it.
+var devicesPointers = []string{"/data/relationships/devices", "/data/relationships/devices/data"}
+
+// deviceLimitMetaKeys are the error meta keys that may carry the API's device
+// limit.
+var deviceLimitMetaKeys = []string{"max", "maximum", "limit"}
+
+// asTooManyDevices returns a [*TooManyDevicesError] wrapping err when err is
+// the API rejecting a request with count devices because of their number: a
+// client error with an [ErrorCodeEntityInvalid] code whose source points at
+// the device list as a whole, for a request with more devices than the limit.
+// The limit is [MaxDevicesPerActivity] unless the error's meta carries one.
+// The wording of the title and detail is not relied on.
+func asTooManyDevices(err error, count int) (*TooManyDevicesError, bool) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode < http.StatusBadRequest || apiErr.StatusCode >= http.StatusInternalServerError {
+		return nil, false
+	}
+
+	for _, e := range apiErr.Response.Errors {
+		if ErrorCode(e.Code) != ErrorCodeEntityInvalid || e.Source == nil || !slices.Contains(devicesPointers, e.Source.Pointer) {
+			continue
+		}
+
+		limit, ok := metaDeviceLimit(e.Meta)
+		if !ok {
+			limit = MaxDevicesPerActivity
+		}
+		if count <= limit {
+			continue
+		}
+		return &TooManyDevicesError{Count: count, Max: limit, Err: apiErr}, true
+	}
+
+	return nil, false
+}
+
+// metaDeviceLimit returns the positive whole number under one of
+// [deviceLimitMetaKeys] in meta.
+func metaDeviceLimit(meta map[string]any) (int, bool) {
+	for _, key := range deviceLimitMetaKeys {
+		n, ok := meta[key].(float64)
+		if ok && n > 0 && n == math.Trunc(n) {
+			return int(n), true
+		}
+	}
+
+	return 0, false
+}
+
+// preflightPerDeviceMax is the largest device count for which the pre-flight
+// assignment check looks up each device's assigned server individually. Larger
+// device sets are checked against the MDM server's device linkage list, which
+// costs one request per 1000 devices assigned to the server.
+const preflightPerDeviceMax = 10
+
+// UnassignDevicesOptions contains optional parameters for [Client.UnassignDevices].
+type UnassignDevicesOptions struct {
+	// VerifyCurrentAssignment checks, before creating any activity, that every
+	// device is currently assigned to the MDM server. Apple accepts unassigning
+	// a device from a server it is not assigned to but does nothing, so the
+	// check returns a [*PreflightError] listing such devices instead.
+	VerifyCurrentAssignment bool
+
+	// Force skips the pre-flight check even when VerifyCurrentAssignment is set,
+	// letting a caller proceed explicitly after inspecting a [*PreflightError].
+	Force bool
+}
+
+// AssignmentMismatch describes a device that is not assigned to the expected MDM server.
+type AssignmentMismatch struct {
+	OrgDeviceID string
+
+	// AssignedServerID is the MDM server the device is currently assigned to.
+	// It is empty when the device is unassigned, or when the check used the
+	// server's device linkage list, which does not reveal other assignments.
+	AssignedServerID string
+}
+
+// PreflightError is returned by [Client.UnassignDevices] when the pre-flight
+// check finds devices that are not currently assigned to the MDM server.
+// No activity has been created when it is returned.
+type PreflightError struct {
+	MDMServerID string
+	Mismatches  []AssignmentMismatch
+}
+
+func (e *PreflightError) Error() string {
+	const maxListed = 5
+
+	ids := make([]string, 0, min(len(e.Mismatches), maxListed))
+	for _, mismatch := range e.Mismatches[:min(len(e.Mismatches), maxListed)] {
+		ids = append(ids, mismatch.OrgDeviceID)
+	}
+	list := strings.Join(ids, ", ")
+	if len(e.Mismatches) > maxListed {
+		list += fmt.Sprintf(", and %d more", len(e.Mismatches)-maxListed)
+	}
+
+	return fmt.Sprintf("preflight: %d devices not assigned to MDM server %q: %s", len(e.Mismatches), e.MDMServerID, list)
+}
+
+// UnassignDevices unassigns organization devices from the MDM server,
+// creating one [OrgDeviceActivityTypeUnassignDevices] activity per batch of
+// at most 1000 devices. It returns the created activities in batch order.
+//
+// Device IDs must be non-empty and unique. When an activity creation fails,
+// the activities created for earlier batches are returned together with the error.
+func (c *Client) UnassignDevices(ctx context.Context, mdmServerID string, orgDeviceIDs []string, options *UnassignDevicesOptions) ([]*OrgDeviceActivityResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if _, err := validateAndEscapeID("mdm server ID", mdmServerID); err != nil {
+		return nil, err
+	}
+	if err := validateActivityDeviceIDs(orgDeviceIDs); err != nil {
+		return nil, err
+	}
+	if options == nil {
+		options = &UnassignDevicesOptions{}
+	}
+
+	if options.VerifyCurrentAssignment && !options.Force {
+		mismatches, err := c.verifyCurrentAssignment(ctx, mdmServerID, orgDeviceIDs)
+		if err != nil {
+			return nil, fmt.Errorf("preflight: %w", err)
+		}
+		if len(mismatches) > 0 {
+			return nil, &PreflightError{
+				MDMServerID: mdmServerID,
+				Mismatches:  mismatches,
+			}
+		}
+	}
+
+	return c.createDeviceActivities(ctx, OrgDeviceActivityTypeUnassignDevices, mdmServerID, orgDeviceIDs)
+}
+
+// AssignDevicesOptions contains optional parameters for [Client.AssignDevices].
+type AssignDevicesOptions struct {
+	// SkipAlreadyAssigned checks, before creating any activity, which devices
+	// are already assigned to the MDM server and leaves them out, so that no
+	// no-op activity is recorded for them. Devices assigned to a different
+	// server are still assigned, and devices unknown to the organization are
+	// reported in [AssignDevicesResult.NotFound] instead of being sent.
+	SkipAlreadyAssigned bool
+
+	// DryRun reports which devices would be assigned and skipped without
+	// creating any activity.
+	DryRun bool
+}
+
+// AssignDevicesResult is the outcome of [Client.AssignDevices]. Device ID
+// lists keep the input order.
+type AssignDevicesResult struct {
+	// Activities are the created activities in batch order. It is empty for a dry run.
+	Activities []*OrgDeviceActivityResponse
+
+	// Assigned lists the devices sent, or for a dry run to be sent, in the activities.
+	Assigned []string
+
+	// Skipped lists the devices already assigned to the MDM server.
+	Skipped []string
+
+	// NotFound lists the devices the organization does not know.
+	NotFound []string
+}
+
+// AssignDevices assigns organization devices to the MDM server, creating one
+// [OrgDeviceActivityTypeAssignDevices] activity per batch of at most 1000
+// devices.
+//
+// Device IDs must be non-empty and unique. With SkipAlreadyAssigned, small
+// device sets are checked per device and larger ones against the server's
+// device linkage list, as for [UnassignDevicesOptions.VerifyCurrentAssignment].
+// When an activity creation fails, the result holds the activities created
+// for earlier batches and is returned together with the error.
+func (c *Client) AssignDevices(ctx context.Context, mdmServerID string, orgDeviceIDs []string, options *AssignDevicesOptions) (*AssignDevicesResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if _, err := validateAndEscapeID("mdm server ID", mdmServerID); err != nil {
+		return nil, err
+	}
+	if err := validateActivityDeviceIDs(orgDeviceIDs); err != nil {
+		return nil, err
+	}
+	if options == nil {
+		options = &AssignDevicesOptions{}
+	}
+
+	result := &AssignDevicesResult{Assigned: orgDeviceIDs}
+	if options.SkipAlreadyAssigned {
+		var err error
+		result.Assigned, result.Skipped, result.NotFound, err = c.classifyAssignment(ctx, mdmServerID, orgDeviceIDs)
+		if err != nil {
+			return nil, fmt.Errorf("preflight: %w", err)
+		}
+	}
+	if options.DryRun || len(result.Assigned) == 0 {
+		return result, nil
+	}
+
+	activities, err := c.createDeviceActivities(ctx, OrgDeviceActivityTypeAssignDevices, mdmServerID, result.Assigned)
+	result.Activities = activities
+
+	return result, err
+}
+
+// createDeviceActivities creates one activity per batch of at most MaxDevicesPerActivity devices.
+func (c *Client) createDeviceActivities(ctx context.Context, activityType OrgDeviceActivityType, mdmServerID string, orgDeviceIDs []string) ([]*OrgDeviceActivityResponse, error) {
+	activities := make([]*OrgDeviceActivityResponse, 0, (len(orgDeviceIDs)+MaxDevicesPerActivity-1)/MaxDevicesPerActivity)
+	for start := 0; start < len(orgDeviceIDs); start += MaxDevicesPerActivity {
+		batch := orgDeviceIDs[start:min(start+MaxDevicesPerActivity, len(orgDeviceIDs))]
+
+		activity, err := c.Activities().Create(ctx, newOrgDeviceActivityCreateRequest(activityType, mdmServerID, batch...))
+		if err != nil {
+			return activities, fmt.Errorf("create activity for devices %d-%d: %w", start, start+len(batch)-1, err)
+		}
+		activities = append(activities, activity)
+	}
+
+	return activities, nil
+}
+
+// verifyCurrentAssignment returns the devices of orgDeviceIDs that are not currently
+// assigned to mdmServerID, in input order. Small device sets are checked per
+// device; larger ones against the server's device linkage list.
+func (c *Client) verifyCurrentAssignment(ctx context.Context, mdmServerID string, orgDeviceIDs []string) ([]AssignmentMismatch, error) {
+	var mismatches []AssignmentMismatch
+
+	if len(orgDeviceIDs) <= preflightPerDeviceMax {
+		for _, id := range orgDeviceIDs {
+			linkage, err := c.OrgDevices().AssignedServerLinkage(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if linkage.Data.ID != mdmServerID {
+				mismatches = append(mismatches, AssignmentMismatch{
+					OrgDeviceID:      id,
+					AssignedServerID: linkage.Data.ID,
+				})
+			}
+		}
+
+		return mismatches, nil
+	}
+
+	assigned, err := c.mdmServerDeviceIDs(ctx, mdmServerID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range orgDeviceIDs {
+		if _, ok := assigned[id]; !ok {
+			mismatches = append(mismatches, AssignmentMismatch{OrgDeviceID: id})
+		}
+	}
+
+	return mismatches, nil
+}
+
+// classifyAssignment splits orgDeviceIDs, in input order, into the devices
+// that need assigning to mdmServerID, those already assigned to it, and those
+// unknown to the organization. Small device sets are checked per device;
+// larger ones against the server's device linkage list and, for devices not
+// on it, the organization's device list.
+func (c *Client) classifyAssignment(ctx context.Context, mdmServerID string, orgDeviceIDs []string) (assign, skipped, notFound []string, err error) {
+	if len(orgDeviceIDs) <= preflightPerDeviceMax {
+		for _, id := range orgDeviceIDs {
+			linkage, err := c.OrgDevices().AssignedServerLinkage(ctx, id)
+			var apiErr *APIError
+			switch {
+			case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound:
+				notFound = append(notFound, id)
+			case err != nil:
+				return nil, nil, nil, err
+			case linkage.Data.ID == mdmServerID:
+				skipped = append(skipped, id)
+			default:
+				assign = append(assign, id)
+			}
+		}
+
+		return assign, skipped, notFound, nil
+	}
+
+	assigned, err := c.mdmServerDeviceIDs(ctx, mdmServerID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var known map[string]struct{}
+	for _, id := range orgDeviceIDs {
+		if _, ok := assigned[id]; ok {
+			skipped = append(skipped, id)
+			continue
+		}
+
+		if known == nil {
+			if known, err = c.orgDeviceIDs(ctx); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		if _, ok := known[id]; ok {
+			assign = append(assign, id)
+		} else {
+			notFound = append(notFound, id)
+		}
+	}
+
+	return assign, skipped, notFound, nil
+}
+
+// orgDeviceIDs returns the set of device IDs in the organization, crawling
+// the device list with the largest page size and a single small field.
+func (c *Client) orgDeviceIDs(ctx context.Context) (map[string]struct{}, error) {
+	query, err := buildFieldsAndLimitQuery("fields[orgDevices]", []string{"status"}, maxPageLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]struct{})
+	for page, err := range crawlPages(ctx, c, orgDevicesPath, query, func(r *OrgDevicesResponse) string { return r.Links.Next }) {
+		if err != nil {
+			return nil, err
+		}
+		for _, device := range page.Data {
+			ids[device.ID] = struct{}{}
+		}
+	}
+
+	return ids, nil
+}
+
+// mdmServerDeviceIDs returns the set of device IDs currently assigned to the
+// MDM server, crawling its device linkages with the largest page size.
+func (c *Client) mdmServerDeviceIDs(ctx context.Context, mdmServerID string) (map[string]struct{}, error) {
+	ids := make(map[string]struct{})
+	for linkage, err := range c.MDMServers().Devices(ctx, mdmServerID) {
+		if err != nil {
+			return nil, err
+		}
+		ids[linkage.ID] = struct{}{}
+	}
+
+	return ids, nil
+}
+
+// validateActivityDeviceIDs reports an error for an empty device list, or an empty or duplicate device ID.
+func validateActivityDeviceIDs(orgDeviceIDs []string) error {
+	if len(orgDeviceIDs) == 0 {
+		return fmt.Errorf("at least one org device ID is required")
+	}
+
+	seen := make(map[string]struct{}, len(orgDeviceIDs))
+	for i, id := range orgDeviceIDs {
+		if strings.TrimSpace(id) == "" {
+			return fmt.Errorf("org device ID at index %d is required", i)
+		}
+		if _, ok := seen[id]; ok {
+			return fmt.Errorf("duplicate org device ID %q", id)
+		}
+		seen[id] = struct{}{}
+	}
+
+	return nil
+}
+
+// defaultAssignedServersConcurrency is the number of concurrent lookups
+// [Client.AssignedServers] uses when given a non-positive concurrency.
+const defaultAssignedServersConcurrency = 4
+
+// AssignedServers returns, for each device in orgDeviceIDs, the ID of the MDM
+// server it is currently assigned to, or "" when it is unassigned.
+//
+// The devices are looked up with [OrgDevicesService.AssignedServerLinkage],
+// at most concurrency at a time; zero or negative means 4. The first failed
+// lookup cancels the remaining ones and its error is returned.
+func (c *Client) AssignedServers(ctx context.Context, orgDeviceIDs []string, concurrency int) (map[string]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for i, id := range orgDeviceIDs {
+		if strings.TrimSpace(id) == "" {
+			return nil, fmt.Errorf("org device ID at index %d is required", i)
+		}
+	}
+	if concurrency <= 0 {
+		concurrency = defaultAssignedServersConcurrency
+	}
+
+	serverIDs := make([]string, len(orgDeviceIDs))
+	err := forEachConcurrent(ctx, len(orgDeviceIDs), concurrency, func(ctx context.Context, i int) error {
+		linkage, err := c.OrgDevices().AssignedServerLinkage(ctx, orgDeviceIDs[i])
+		if err != nil {
+			return fmt.Errorf("org device %q: %w", orgDeviceIDs[i], err)
+		}
+		serverIDs[i] = linkage.Data.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	servers := make(map[string]string, len(orgDeviceIDs))
+	for i, id := range orgDeviceIDs {
+		servers[id] = serverIDs[i]
+	}
+
+	return servers, nil
+}
+
+// forEachConcurrent calls fn for every index in [0, n), at most concurrency
+// calls at a time. The first error cancels the context passed to the other
+// calls, stops new calls from starting, and is returned; so is the cause of
+// ctx being done.
+func forEachConcurrent(ctx context.Context, n, concurrency int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var wg sync.WaitGroup
+	indexes := make(chan int)
+	for range min(concurrency, n) {
+		wg.Go(func() {
+			for i := range indexes {
+				if err := fn(ctx, i); err != nil {
+					cancel(err)
+				}
+			}
+		})
+	}
+
+feed:
+	for i := range n {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	return context.Cause(ctx)
+}
+
+// DeleteOrgDeviceAssignment removes an organization device from the device
+// management service it is currently assigned to.
+//
+// It looks up the assigned server and creates an [OrgDeviceActivityTypeUnassignDevices]
+// activity for it. If the device has no assigned server, it returns nil without error.
+func (c *Client) DeleteOrgDeviceAssignment(ctx context.Context, orgDeviceID string) (*OrgDeviceActivityResponse, error) {
+	linkage, err := c.OrgDevices().AssignedServerLinkage(ctx, orgDeviceID)
+	if err != nil {
+		return nil, err
+	}
+	if linkage.Data.ID == "" {
+		return nil, nil
+	}
+
+	request := newOrgDeviceActivityCreateRequest(OrgDeviceActivityTypeUnassignDevices, linkage.Data.ID, orgDeviceID)
+	return c.Activities().Create(ctx, request)
+}
+
+// NewAssignDevicesRequest returns a validated request that assigns the devices
+// to the MDM server.
+func NewAssignDevicesRequest(mdmServerID string, orgDeviceIDs ...string) (OrgDeviceActivityCreateRequest, error) {
+	request := newOrgDeviceActivityCreateRequest(OrgDeviceActivityTypeAssignDevices, mdmServerID, orgDeviceIDs...)
+	if err := request.Validate(); err != nil {
+		return OrgDeviceActivityCreateRequest{}, err
+	}
+
+	return request, nil
+}
+
+// NewUnassignDevicesRequest returns a validated request that unassigns the
+// devices from the MDM server they are currently assigned to.
+func NewUnassignDevicesRequest(mdmServerID string, orgDeviceIDs ...string) (OrgDeviceActivityCreateRequest, error) {
+	request := newOrgDeviceActivityCreateRequest(OrgDeviceActivityTypeUnassignDevices, mdmServerID, orgDeviceIDs...)
+	if err := request.Validate(); err != nil {
+		return OrgDeviceActivityCreateRequest{}, err
+	}
+
+	return request, nil
+}
+
+// Validate reports whether the request is well formed. [ActivitiesService.Create]
+// calls it before sending, so a malformed request fails locally with a
+// descriptive error instead of a server-side 400 or 409.
+//
+// The activity type must be one of the OrgDeviceActivityType constants, and
+// both assign and unassign activities need the MDM server: an assign activity
+// targets it, and Apple only unassigns devices from the server named in the
+// request. Every device must be a distinct orgDevices linkage, at most
+// [MaxDevicesPerActivity] of them unless AllowOversized is set; more devices
+// fail with a [*TooManyDevicesError].
+func (r OrgDeviceActivityCreateRequest) Validate() error {
+	data := r.Data
+	if data.Type != orgDeviceActivitiesResourceType {
+		return fmt.Errorf("activity resource type must be %q, got %q", orgDeviceActivitiesResourceType, data.Type)
+	}
+
+	activityType := data.Attributes.ActivityType
+	switch activityType {
+	case OrgDeviceActivityTypeAssignDevices, OrgDeviceActivityTypeUnassignDevices:
+	case "":
+		return fmt.Errorf("activity type is required")
+	default:
+		return fmt.Errorf("unknown activity type %q: must be %q or %q", activityType, OrgDeviceActivityTypeAssignDevices, OrgDeviceActivityTypeUnassignDevices)
+	}
+
+	server := data.Relationships.MDMServer.Data
+	if strings.TrimSpace(server.ID) == "" {
+		if activityType == OrgDeviceActivityTypeUnassignDevices {
+			return fmt.Errorf("%s activity requires the MDM server the devices are unassigned from", activityType)
+		}
+		return fmt.Errorf("%s activity requires the MDM server the devices are assigned to", activityType)
+	}
+	if server.Type != mdmServersResourceType {
+		return fmt.Errorf("MDM server resource type must be %q, got %q", mdmServersResourceType, server.Type)
+	}
+
+	devices := data.Relationships.Devices.Data
+	if len(devices) > MaxDevicesPerActivity && !r.AllowOversized {
+		return fmt.Errorf("%s activity: %w", activityType, &TooManyDevicesError{Count: len(devices), Max: MaxDevicesPerActivity})
+	}
+	ids := make([]string, len(devices))
+	for i, device := range devices {
+		if device.Type != orgDevicesResourceType {
+			return fmt.Errorf("device at index %d: resource type must be %q, got %q", i, orgDevicesResourceType, device.Type)
+		}
+		ids[i] = device.ID
+	}
+	if err := validateActivityDeviceIDs(ids); err != nil {
+		return fmt.Errorf("%s activity: %w", activityType, err)
+	}
+
+	return nil
+}
+
+func newOrgDeviceActivityCreateRequest(activityType OrgDeviceActivityType, mdmServerID string, orgDeviceIDs ...string) OrgDeviceActivityCreateRequest {
+	devices := make([]OrgDeviceActivityCreateRequestDataRelationshipsDevicesData, len(orgDeviceIDs))
+	for i, id := range orgDeviceIDs {
+		devices[i] = OrgDeviceActivityCreateRequestDataRelationshipsDevicesData{
+			ID:   id,
+			Type: orgDevicesResourceType,
+		}
+	}
+
+	return OrgDeviceActivityCreateRequest{
+		Data: OrgDeviceActivityCreateRequestData{
+			Attributes: OrgDeviceActivityCreateRequestDataAttributes{
+				ActivityType: activityType,
+			},
+			Relationships: OrgDeviceActivityCreateRequestDataRelationships{
+				Devices: OrgDeviceActivityCreateRequestDataRelationshipsDevices{
+					Data: devices,
+				},
+				MDMServer: OrgDeviceActivityCreateRequestDataRelationshipsMDMServer{
+					Data: OrgDeviceActivityCreateRequestDataRelationshipsMDMServerData{
+						ID:   mdmServerID,
+						Type: mdmServersResourceType,
+					},
+				},
+			},
+			Type: orgDeviceActivitiesResourceType,
+		},
+	}
+}
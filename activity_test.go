@@ -0,0 +1,990 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-json-experiment/json"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestClient_DeleteOrgDeviceAssignment(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		linkageStatus int
+		linkageBody   string
+		wantActivity  *OrgDeviceActivityCreateRequest
+		wantID        string
+		wantNil       bool
+		wantPosts     int32
+		wantErr       bool
+	}{
+		"success: assigned device": {
+			linkageStatus: http.StatusOK,
+			linkageBody:   `{"data":{"id":"mdm-1","type":"mdmServers"},"links":{"self":"https://api-business.apple.com/v1/orgDevices/device-1/relationships/assignedServer"}}`,
+			wantActivity: &OrgDeviceActivityCreateRequest{
+				Data: OrgDeviceActivityCreateRequestData{
+					Attributes: OrgDeviceActivityCreateRequestDataAttributes{
+						ActivityType: OrgDeviceActivityTypeUnassignDevices,
+					},
+					Relationships: OrgDeviceActivityCreateRequestDataRelationships{
+						Devices: OrgDeviceActivityCreateRequestDataRelationshipsDevices{
+							Data: []OrgDeviceActivityCreateRequestDataRelationshipsDevicesData{
+								{
+									ID:   "device-1",
+									Type: "orgDevices",
+								},
+							},
+						},
+						MDMServer: OrgDeviceActivityCreateRequestDataRelationshipsMDMServer{
+							Data: OrgDeviceActivityCreateRequestDataRelationshipsMDMServerData{
+								ID:   "mdm-1",
+								Type: "mdmServers",
+							},
+						},
+					},
+					Type: "orgDeviceActivities",
+				},
+			},
+			wantID:    "activity-1",
+			wantPosts: 1,
+		},
+		"success: unassigned device": {
+			linkageStatus: http.StatusOK,
+			linkageBody:   `{"data":null,"links":{"self":"https://api-business.apple.com/v1/orgDevices/device-1/relationships/assignedServer"}}`,
+			wantNil:       true,
+		},
+		"error: linkage fetch fails": {
+			linkageStatus: http.StatusInternalServerError,
+			linkageBody:   `{"errors":[{"code":"INTERNAL_ERROR","detail":"boom","status":"500","title":"Internal Error"}]}`,
+			wantErr:       true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			var posts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/v1/orgDevices/device-1/relationships/assignedServer":
+					w.WriteHeader(tt.linkageStatus)
+					fmt.Fprint(w, tt.linkageBody)
+				case r.Method == http.MethodPost && r.URL.Path == "/v1/orgDeviceActivities":
+					posts.Add(1)
+					payload, err := io.ReadAll(r.Body)
+					if err != nil {
+						t.Errorf("read request body: %v", err)
+					}
+					var got OrgDeviceActivityCreateRequest
+					if err := json.Unmarshal(payload, &got); err != nil {
+						t.Errorf("unmarshal request body: %v", err)
+					}
+					if diff := cmp.Diff(tt.wantActivity, &got); diff != "" {
+						t.Errorf("activity request mismatch (-want +got):\n%s", diff)
+					}
+					w.WriteHeader(http.StatusCreated)
+					fmt.Fprint(w, `{"data":{"id":"activity-1","type":"orgDeviceActivities"},"links":{"self":"https://api-business.apple.com/v1/orgDeviceActivities/activity-1"}}`)
+				default:
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			t.Cleanup(server.Close)
+
+			client := testClientForServer(t, server)
+			resp, err := client.DeleteOrgDeviceAssignment(ctx, "device-1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DeleteOrgDeviceAssignment error mismatch: err=%v wantErr=%v", err, tt.wantErr)
+			}
+			if got := posts.Load(); got != tt.wantPosts {
+				t.Fatalf("unexpected activity requests: got=%d want=%d", got, tt.wantPosts)
+			}
+			if tt.wantErr {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) {
+					t.Fatalf("expected APIError, got: %T", err)
+				}
+				return
+			}
+			if tt.wantNil {
+				if resp != nil {
+					t.Fatalf("expected nil response, got: %#v", resp)
+				}
+				return
+			}
+			if diff := cmp.Diff(tt.wantID, resp.Data.ID); diff != "" {
+				t.Fatalf("activity id mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// newAssignmentServer serves assigned-server linkages and MDM server device
+// linkages for assignments, a map of device ID to MDM server ID, and accepts
+// activity creations. The linkage list is served in pages of two devices.
+// The organization device list holds the devices in assignments, and the
+// assigned-server linkage of an unknown device is not found.
+func newAssignmentServer(t *testing.T, assignments map[string]string, unknown ...string) (server *httptest.Server, perDevice, listPages *atomic.Int32, posted *[][]string) {
+	t.Helper()
+
+	perDevice, listPages = new(atomic.Int32), new(atomic.Int32)
+	posted = new([][]string)
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/relationships/assignedServer"):
+			perDevice.Add(1)
+			deviceID := strings.Split(r.URL.Path, "/")[3]
+			if slices.Contains(unknown, deviceID) {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"errors":[{"status":"404","code":"NOT_FOUND"}]}`)
+				return
+			}
+			if serverID := assignments[deviceID]; serverID != "" {
+				fmt.Fprintf(w, `{"data":{"id":%q,"type":"mdmServers"},"links":{"self":"/"}}`, serverID)
+				return
+			}
+			fmt.Fprint(w, `{"data":null,"links":{"self":"/"}}`)
+
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/relationships/devices"):
+			listPages.Add(1)
+			serverID := strings.Split(r.URL.Path, "/")[3]
+			var ids []string
+			for deviceID, assigned := range assignments {
+				if assigned == serverID {
+					ids = append(ids, deviceID)
+				}
+			}
+			slices.Sort(ids)
+
+			offset, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+			end := min(offset+2, len(ids))
+			data := make([]MDMServerDevicesLinkageData, 0, end-offset)
+			for _, id := range ids[offset:end] {
+				data = append(data, MDMServerDevicesLinkageData{ID: id, Type: "orgDevices"})
+			}
+			response := MDMServerDevicesLinkagesResponse{Data: data, Links: PagedDocumentLinks{Self: r.URL.String()}}
+			if end < len(ids) {
+				response.Links.Next = fmt.Sprintf("%s?cursor=%d", r.URL.Path, end)
+			}
+			json.MarshalWrite(w, response)
+
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/orgDevices":
+			ids := slices.Sorted(maps.Keys(assignments))
+			data := make([]OrgDevice, len(ids))
+			for i, id := range ids {
+				data[i] = OrgDevice{ID: id, Type: "orgDevices"}
+			}
+			json.MarshalWrite(w, OrgDevicesResponse{Data: data, Links: PagedDocumentLinks{Self: r.URL.String()}})
+
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/orgDeviceActivities":
+			var request OrgDeviceActivityCreateRequest
+			if err := json.UnmarshalRead(r.Body, &request); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			ids := make([]string, len(request.Data.Relationships.Devices.Data))
+			for i, device := range request.Data.Relationships.Devices.Data {
+				ids[i] = device.ID
+			}
+			*posted = append(*posted, ids)
+
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"data":{"id":"activity-%d","type":"orgDeviceActivities"},"links":{"self":"/"}}`, len(*posted))
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, perDevice, listPages, posted
+}
+
+func TestClient_UnassignDevices(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	deviceIDs := func(n int) []string {
+		ids := make([]string, n)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("device-%02d", i+1)
+		}
+		return ids
+	}
+	assignedTo := func(serverID string, ids []string) map[string]string {
+		assignments := make(map[string]string, len(ids))
+		for _, id := range ids {
+			assignments[id] = serverID
+		}
+		return assignments
+	}
+	batchIDs := make([]string, 2*MaxDevicesPerActivity+1)
+	for i := range batchIDs {
+		batchIDs[i] = fmt.Sprintf("device-%05d", i)
+	}
+
+	tests := map[string]struct {
+		assignments    map[string]string
+		deviceIDs      []string
+		options        *UnassignDevicesOptions
+		wantMismatches []AssignmentMismatch
+		wantPerDevice  int32
+		wantListPages  int32
+		wantPosted     [][]string
+		wantErr        string
+	}{
+		"success: verified per device": {
+			assignments:   assignedTo("mdm-1", deviceIDs(3)),
+			deviceIDs:     deviceIDs(3),
+			options:       &UnassignDevicesOptions{VerifyCurrentAssignment: true},
+			wantPerDevice: 3,
+			wantPosted:    [][]string{deviceIDs(3)},
+		},
+		"success: per device at strategy boundary": {
+			assignments:   assignedTo("mdm-1", deviceIDs(preflightPerDeviceMax)),
+			deviceIDs:     deviceIDs(preflightPerDeviceMax),
+			options:       &UnassignDevicesOptions{VerifyCurrentAssignment: true},
+			wantPerDevice: preflightPerDeviceMax,
+			wantPosted:    [][]string{deviceIDs(preflightPerDeviceMax)},
+		},
+		"success: linkage list above strategy boundary": {
+			assignments:   assignedTo("mdm-1", deviceIDs(preflightPerDeviceMax+1)),
+			deviceIDs:     deviceIDs(preflightPerDeviceMax + 1),
+			options:       &UnassignDevicesOptions{VerifyCurrentAssignment: true},
+			wantListPages: (preflightPerDeviceMax + 2) / 2,
+			wantPosted:    [][]string{deviceIDs(preflightPerDeviceMax + 1)},
+		},
+		"success: force skips verification": {
+			assignments: map[string]string{"device-01": "mdm-2"},
+			deviceIDs:   deviceIDs(2),
+			options:     &UnassignDevicesOptions{VerifyCurrentAssignment: true, Force: true},
+			wantPosted:  [][]string{deviceIDs(2)},
+		},
+		"success: batches of max devices": {
+			deviceIDs: batchIDs,
+			wantPosted: [][]string{
+				batchIDs[:MaxDevicesPerActivity],
+				batchIDs[MaxDevicesPerActivity : 2*MaxDevicesPerActivity],
+				batchIDs[2*MaxDevicesPerActivity:],
+			},
+		},
+		"error: per device mismatches": {
+			assignments: map[string]string{
+				"device-01": "mdm-1",
+				"device-02": "mdm-2",
+			},
+			deviceIDs: deviceIDs(3),
+			options:   &UnassignDevicesOptions{VerifyCurrentAssignment: true},
+			wantMismatches: []AssignmentMismatch{
+				{OrgDeviceID: "device-02", AssignedServerID: "mdm-2"},
+				{OrgDeviceID: "device-03"},
+			},
+			wantPerDevice: 3,
+		},
+		"error: linkage list mismatches": {
+			assignments: assignedTo("mdm-1", deviceIDs(10)),
+			deviceIDs:   deviceIDs(12),
+			options:     &UnassignDevicesOptions{VerifyCurrentAssignment: true},
+			wantMismatches: []AssignmentMismatch{
+				{OrgDeviceID: "device-11"},
+				{OrgDeviceID: "device-12"},
+			},
+			wantListPages: 5,
+		},
+		"error: empty device list": {
+			wantErr: "at least one org device ID is required",
+		},
+		"error: duplicate device ID": {
+			deviceIDs: []string{"device-01", "device-02", "device-01"},
+			wantErr:   `duplicate org device ID "device-01"`,
+		},
+		"error: empty device ID": {
+			deviceIDs: []string{"device-01", " "},
+			wantErr:   "org device ID at index 1 is required",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server, perDevice, listPages, posted := newAssignmentServer(t, tt.assignments)
+			client := testClientForServer(t, server)
+
+			activities, err := client.UnassignDevices(ctx, "mdm-1", tt.deviceIDs, tt.options)
+			switch {
+			case tt.wantErr != "":
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error mismatch: got=%v want=%q", err, tt.wantErr)
+				}
+			case tt.wantMismatches != nil:
+				var preflightErr *PreflightError
+				if !errors.As(err, &preflightErr) {
+					t.Fatalf("expected *PreflightError, got %v", err)
+				}
+				if diff := cmp.Diff(tt.wantMismatches, preflightErr.Mismatches); diff != "" {
+					t.Fatalf("mismatches mismatch (-want +got):\n%s", diff)
+				}
+			case err != nil:
+				t.Fatalf("UnassignDevices returned error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.wantPerDevice, perDevice.Load()); diff != "" {
+				t.Fatalf("per-device lookup count mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantListPages, listPages.Load()); diff != "" {
+				t.Fatalf("linkage list page count mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantPosted, *posted); diff != "" {
+				t.Fatalf("posted devices mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(len(tt.wantPosted), len(activities)); diff != "" {
+				t.Fatalf("activity count mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_AssignDevices(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	deviceIDs := func(from, to int) []string {
+		ids := make([]string, 0, to-from+1)
+		for i := from; i <= to; i++ {
+			ids = append(ids, fmt.Sprintf("device-%02d", i))
+		}
+		return ids
+	}
+	perDeviceAssignments := map[string]string{
+		"device-01": "mdm-1",
+		"device-02": "mdm-2",
+		"device-03": "",
+	}
+	listAssignments := make(map[string]string)
+	for _, id := range deviceIDs(1, 5) {
+		listAssignments[id] = "mdm-1"
+	}
+	listAssignments["device-06"] = "mdm-2"
+	for _, id := range deviceIDs(7, 11) {
+		listAssignments[id] = ""
+	}
+
+	tests := map[string]struct {
+		mdmServerID   string
+		assignments   map[string]string
+		unknown       []string
+		deviceIDs     []string
+		options       *AssignDevicesOptions
+		want          *AssignDevicesResult
+		wantPerDevice int32
+		wantListPages int32
+		wantPosted    [][]string
+		wantErr       string
+	}{
+		"success: assign without check": {
+			assignments: perDeviceAssignments,
+			deviceIDs:   deviceIDs(1, 3),
+			want:        &AssignDevicesResult{Assigned: deviceIDs(1, 3)},
+			wantPosted:  [][]string{deviceIDs(1, 3)},
+		},
+		"success: skip per device": {
+			assignments:   perDeviceAssignments,
+			unknown:       []string{"device-04"},
+			deviceIDs:     deviceIDs(1, 4),
+			options:       &AssignDevicesOptions{SkipAlreadyAssigned: true},
+			want:          &AssignDevicesResult{Assigned: deviceIDs(2, 3), Skipped: deviceIDs(1, 1), NotFound: deviceIDs(4, 4)},
+			wantPerDevice: 4,
+			wantPosted:    [][]string{deviceIDs(2, 3)},
+		},
+		"success: skip with linkage list above strategy boundary": {
+			assignments:   listAssignments,
+			deviceIDs:     deviceIDs(1, 12),
+			options:       &AssignDevicesOptions{SkipAlreadyAssigned: true},
+			want:          &AssignDevicesResult{Assigned: deviceIDs(6, 11), Skipped: deviceIDs(1, 5), NotFound: deviceIDs(12, 12)},
+			wantListPages: 3,
+			wantPosted:    [][]string{deviceIDs(6, 11)},
+		},
+		"success: all devices already assigned": {
+			assignments:   perDeviceAssignments,
+			deviceIDs:     deviceIDs(1, 1),
+			options:       &AssignDevicesOptions{SkipAlreadyAssigned: true},
+			want:          &AssignDevicesResult{Skipped: deviceIDs(1, 1)},
+			wantPerDevice: 1,
+		},
+		"success: dry run with skip": {
+			assignments:   perDeviceAssignments,
+			unknown:       []string{"device-04"},
+			deviceIDs:     deviceIDs(1, 4),
+			options:       &AssignDevicesOptions{SkipAlreadyAssigned: true, DryRun: true},
+			want:          &AssignDevicesResult{Assigned: deviceIDs(2, 3), Skipped: deviceIDs(1, 1), NotFound: deviceIDs(4, 4)},
+			wantPerDevice: 4,
+		},
+		"success: dry run without check": {
+			assignments: perDeviceAssignments,
+			deviceIDs:   deviceIDs(1, 3),
+			options:     &AssignDevicesOptions{DryRun: true},
+			want:        &AssignDevicesResult{Assigned: deviceIDs(1, 3)},
+		},
+		"error: empty device list": {
+			wantErr: "at least one org device ID is required",
+		},
+		"error: empty mdm server ID": {
+			mdmServerID: " ",
+			deviceIDs:   deviceIDs(1, 1),
+			wantErr:     "mdm server ID is required",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server, perDevice, listPages, posted := newAssignmentServer(t, tt.assignments, tt.unknown...)
+			client := testClientForServer(t, server)
+
+			mdmServerID := tt.mdmServerID
+			if mdmServerID == "" {
+				mdmServerID = "mdm-1"
+			}
+			got, err := client.AssignDevices(ctx, mdmServerID, tt.deviceIDs, tt.options)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error mismatch: got=%v want=%q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AssignDevices returned error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(AssignDevicesResult{}, "Activities")); diff != "" {
+				t.Fatalf("result mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(len(tt.wantPosted), len(got.Activities)); diff != "" {
+				t.Fatalf("activity count mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantPerDevice, perDevice.Load()); diff != "" {
+				t.Fatalf("per-device lookup count mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantListPages, listPages.Load()); diff != "" {
+				t.Fatalf("linkage list page count mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantPosted, *posted); diff != "" {
+				t.Fatalf("posted devices mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_AssignedServers(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	deviceIDs := make([]string, 20)
+	assignments := make(map[string]string, len(deviceIDs))
+	for i := range deviceIDs {
+		deviceIDs[i] = fmt.Sprintf("device-%02d", i+1)
+		if i%3 != 0 {
+			assignments[deviceIDs[i]] = fmt.Sprintf("mdm-%d", i%3)
+		}
+	}
+	want := make(map[string]string, len(deviceIDs))
+	for _, id := range deviceIDs {
+		want[id] = assignments[id]
+	}
+
+	tests := map[string]struct {
+		deviceIDs       []string
+		concurrency     int
+		unknown         string
+		want            map[string]string
+		wantMaxInFlight int32
+		wantErr         bool
+	}{
+		"success: bounded concurrency": {
+			deviceIDs:       deviceIDs,
+			concurrency:     3,
+			want:            want,
+			wantMaxInFlight: 3,
+		},
+		"success: default concurrency": {
+			deviceIDs:       deviceIDs,
+			want:            want,
+			wantMaxInFlight: defaultAssignedServersConcurrency,
+		},
+		"success: no devices": {
+			deviceIDs:   nil,
+			concurrency: 2,
+			want:        map[string]string{},
+		},
+		"error: lookup failure": {
+			deviceIDs:   deviceIDs,
+			concurrency: 3,
+			unknown:     "device-05",
+			wantErr:     true,
+		},
+		"error: empty device ID": {
+			deviceIDs: []string{"device-01", ""},
+			wantErr:   true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			var inFlight, maxInFlight atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := inFlight.Add(1)
+				defer inFlight.Add(-1)
+				for {
+					seen := maxInFlight.Load()
+					if n <= seen || maxInFlight.CompareAndSwap(seen, n) {
+						break
+					}
+				}
+				// Hold the request so concurrent lookups overlap.
+				time.Sleep(5 * time.Millisecond)
+
+				w.Header().Set("Content-Type", "application/json")
+				deviceID := strings.Split(r.URL.Path, "/")[3]
+				if deviceID == tt.unknown {
+					w.WriteHeader(http.StatusNotFound)
+					fmt.Fprint(w, `{"errors":[{"status":"404","code":"NOT_FOUND"}]}`)
+					return
+				}
+				if serverID := assignments[deviceID]; serverID != "" {
+					fmt.Fprintf(w, `{"data":{"id":%q,"type":"mdmServers"},"links":{"self":"/"}}`, serverID)
+					return
+				}
+				fmt.Fprint(w, `{"data":null,"links":{"self":"/"}}`)
+			}))
+			t.Cleanup(server.Close)
+			client := testClientForServer(t, server)
+
+			got, err := client.AssignedServers(ctx, tt.deviceIDs, tt.concurrency)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AssignedServers error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if tt.unknown != "" {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+					t.Fatalf("expected not found APIError, got %v", err)
+				}
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("assigned servers mismatch (-want +got):\n%s", diff)
+			}
+			if got := maxInFlight.Load(); got > tt.wantMaxInFlight {
+				t.Fatalf("max in-flight lookups = %d, want <= %d", got, tt.wantMaxInFlight)
+			}
+		})
+	}
+}
+
+func TestPreflightError_Error(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		mismatches []AssignmentMismatch
+		want       string
+	}{
+		"success: few mismatches": {
+			mismatches: []AssignmentMismatch{{OrgDeviceID: "d1"}, {OrgDeviceID: "d2", AssignedServerID: "mdm-2"}},
+			want:       `preflight: 2 devices not assigned to MDM server "mdm-1": d1, d2`,
+		},
+		"success: truncated list": {
+			mismatches: []AssignmentMismatch{{OrgDeviceID: "d1"}, {OrgDeviceID: "d2"}, {OrgDeviceID: "d3"}, {OrgDeviceID: "d4"}, {OrgDeviceID: "d5"}, {OrgDeviceID: "d6"}, {OrgDeviceID: "d7"}},
+			want:       `preflight: 7 devices not assigned to MDM server "mdm-1": d1, d2, d3, d4, d5, and 2 more`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			err := &PreflightError{MDMServerID: "mdm-1", Mismatches: tt.mismatches}
+			if diff := cmp.Diff(tt.want, err.Error()); diff != "" {
+				t.Fatalf("Error mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOrgDeviceActivityCreateRequest_Validate(t *testing.T) {
+	valid := func(activityType OrgDeviceActivityType) OrgDeviceActivityCreateRequest {
+		return newOrgDeviceActivityCreateRequest(activityType, "server-1", "device-1", "device-2")
+	}
+
+	tests := map[string]struct {
+		request     func() OrgDeviceActivityCreateRequest
+		wantErrText string
+	}{
+		"success: assign": {
+			request: func() OrgDeviceActivityCreateRequest { return valid(OrgDeviceActivityTypeAssignDevices) },
+		},
+		"success: unassign": {
+			request: func() OrgDeviceActivityCreateRequest { return valid(OrgDeviceActivityTypeUnassignDevices) },
+		},
+		"error: missing activity type": {
+			request:     func() OrgDeviceActivityCreateRequest { return valid("") },
+			wantErrText: "activity type is required",
+		},
+		"error: unknown activity type": {
+			request:     func() OrgDeviceActivityCreateRequest { return valid("REASSIGN_DEVICES") },
+			wantErrText: `unknown activity type "REASSIGN_DEVICES"`,
+		},
+		"error: lower-case activity type": {
+			request:     func() OrgDeviceActivityCreateRequest { return valid("unassign_devices") },
+			wantErrText: `unknown activity type "unassign_devices"`,
+		},
+		"error: wrong activity resource type": {
+			request: func() OrgDeviceActivityCreateRequest {
+				r := valid(OrgDeviceActivityTypeAssignDevices)
+				r.Data.Type = "orgDevices"
+				return r
+			},
+			wantErrText: `activity resource type must be "orgDeviceActivities"`,
+		},
+		"error: assign without mdm server": {
+			request: func() OrgDeviceActivityCreateRequest {
+				return newOrgDeviceActivityCreateRequest(OrgDeviceActivityTypeAssignDevices, "", "device-1")
+			},
+			wantErrText: "ASSIGN_DEVICES activity requires the MDM server the devices are assigned to",
+		},
+		"error: unassign without mdm server": {
+			request: func() OrgDeviceActivityCreateRequest {
+				return newOrgDeviceActivityCreateRequest(OrgDeviceActivityTypeUnassignDevices, " ", "device-1")
+			},
+			wantErrText: "UNASSIGN_DEVICES activity requires the MDM server the devices are unassigned from",
+		},
+		"error: wrong mdm server resource type": {
+			request: func() OrgDeviceActivityCreateRequest {
+				r := valid(OrgDeviceActivityTypeUnassignDevices)
+				r.Data.Relationships.MDMServer.Data.Type = "orgDevices"
+				return r
+			},
+			wantErrText: `MDM server resource type must be "mdmServers"`,
+		},
+		"error: no devices": {
+			request: func() OrgDeviceActivityCreateRequest {
+				return newOrgDeviceActivityCreateRequest(OrgDeviceActivityTypeAssignDevices, "server-1")
+			},
+			wantErrText: "ASSIGN_DEVICES activity: at least one org device ID is required",
+		},
+		"error: blank device id": {
+			request: func() OrgDeviceActivityCreateRequest {
+				return newOrgDeviceActivityCreateRequest(OrgDeviceActivityTypeUnassignDevices, "server-1", "device-1", "")
+			},
+			wantErrText: "org device ID at index 1 is required",
+		},
+		"error: duplicate device id": {
+			request: func() OrgDeviceActivityCreateRequest {
+				return newOrgDeviceActivityCreateRequest(OrgDeviceActivityTypeAssignDevices, "server-1", "device-1", "device-1")
+			},
+			wantErrText: `duplicate org device ID "device-1"`,
+		},
+		"error: wrong device resource type": {
+			request: func() OrgDeviceActivityCreateRequest {
+				r := valid(OrgDeviceActivityTypeAssignDevices)
+				r.Data.Relationships.Devices.Data[1].Type = "mdmServers"
+				return r
+			},
+			wantErrText: `device at index 1: resource type must be "orgDevices"`,
+		},
+		"error: too many devices": {
+			request: func() OrgDeviceActivityCreateRequest {
+				ids := make([]string, MaxDevicesPerActivity+1)
+				for i := range ids {
+					ids[i] = "device-" + strconv.Itoa(i)
+				}
+				return newOrgDeviceActivityCreateRequest(OrgDeviceActivityTypeAssignDevices, "server-1", ids...)
+			},
+			wantErrText: "at most 1000 devices are allowed per activity",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			err := tt.request().Validate()
+			if tt.wantErrText == "" {
+				if err != nil {
+					t.Fatalf("Validate returned error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErrText) {
+				t.Fatalf("Validate error = %v, want containing %q", err, tt.wantErrText)
+			}
+		})
+	}
+}
+
+func TestNewAssignAndUnassignDevicesRequest(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	assign, err := NewAssignDevicesRequest("server-1", "device-1")
+	if err != nil {
+		t.Fatalf("NewAssignDevicesRequest returned error: %v", err)
+	}
+	if diff := cmp.Diff(newOrgDeviceActivityCreateRequest(OrgDeviceActivityTypeAssignDevices, "server-1", "device-1"), assign); diff != "" {
+		t.Fatalf("assign request mismatch (-want +got):\n%s", diff)
+	}
+
+	unassign, err := NewUnassignDevicesRequest("server-1", "device-1")
+	if err != nil {
+		t.Fatalf("NewUnassignDevicesRequest returned error: %v", err)
+	}
+	if diff := cmp.Diff(OrgDeviceActivityTypeUnassignDevices, unassign.Data.Attributes.ActivityType); diff != "" {
+		t.Fatalf("unassign activity type mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := NewUnassignDevicesRequest("", "device-1"); err == nil {
+		t.Fatal("NewUnassignDevicesRequest with no MDM server returned nil error")
+	}
+}
+
+func TestClient_CreateOrgDeviceActivityValidates(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(server.Close)
+	client := testClientForServer(t, server)
+
+	_, err := client.CreateOrgDeviceActivity(ctx, newOrgDeviceActivityCreateRequest("ASSIGN", "server-1", "device-1"))
+	if err == nil || !strings.Contains(err.Error(), `invalid org device activity request: unknown activity type "ASSIGN"`) {
+		t.Fatalf("CreateOrgDeviceActivity error = %v, want invalid request error", err)
+	}
+	if got := requests.Load(); got != 0 {
+		t.Fatalf("server received %d requests, want 0", got)
+	}
+}
+
+func TestClient_CreateOrgDeviceActivityTooManyDevices(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	const (
+		createdJSON     = `{"data":{"id":"activity-1","type":"orgDeviceActivities","attributes":{"status":"IN_PROGRESS"}}}`
+		overLimitJSON   = `{"errors":[{"status":"400","code":"ENTITY_ERROR.ATTRIBUTE.INVALID","title":"An attribute value is invalid.","detail":"The request contains 1200 devices, which exceeds the maximum of 1000 devices per activity.","source":{"pointer":"/data/relationships/devices/data"}}]}`
+		invalidIDJSON   = `{"errors":[{"status":"400","code":"ENTITY_ERROR.ATTRIBUTE.INVALID","title":"An attribute value is invalid.","detail":"Device 'device-7' is not valid.","source":{"pointer":"/data/relationships/devices/data/7"}}]}`
+		unrelatedJSON   = `{"errors":[{"status":"400","code":"PARAMETER_ERROR.INVALID","title":"Invalid parameter.","detail":"The limit is too large.","source":{"parameter":"limit"}}]}`
+		rewordedJSON    = `{"errors":[{"status":"400","code":"ENTITY_ERROR.ATTRIBUTE.INVALID","title":"An attribute value is invalid.","detail":"Split the 1200 devices of request 42 into smaller activities.","source":{"pointer":"/data/relationships/devices"}}]}`
+		metaLimitJSON   = `{"errors":[{"status":"400","code":"ENTITY_ERROR.ATTRIBUTE.INVALID","title":"An attribute value is invalid.","detail":"Too many devices.","source":{"pointer":"/data/relationships/devices/data"},"meta":{"maximum":500}}]}`
+		duplicatesJSON  = `{"errors":[{"status":"400","code":"ENTITY_ERROR.ATTRIBUTE.INVALID","title":"An attribute value is invalid.","detail":"The devices must be unique; at most 1 entry per device.","source":{"pointer":"/data/relationships/devices/data"}}]}`
+		deviceCountOver = MaxDevicesPerActivity + 200
+	)
+
+	request := func(count int, allowOversized bool) OrgDeviceActivityCreateRequest {
+		ids := make([]string, count)
+		for i := range ids {
+			ids[i] = "device-" + strconv.Itoa(i)
+		}
+		r := newOrgDeviceActivityCreateRequest(OrgDeviceActivityTypeAssignDevices, "server-1", ids...)
+		r.AllowOversized = allowOversized
+		return r
+	}
+
+	tests := map[string]struct {
+		request      OrgDeviceActivityCreateRequest
+		status       int
+		body         string
+		wantRequests int32
+		wantTooMany  *TooManyDevicesError
+		wantAPIError bool
+	}{
+		"success: exactly the maximum": {
+			request:      request(MaxDevicesPerActivity, false),
+			status:       http.StatusCreated,
+			body:         createdJSON,
+			wantRequests: 1,
+		},
+		"success: oversized request allowed": {
+			request:      request(deviceCountOver, true),
+			status:       http.StatusCreated,
+			body:         createdJSON,
+			wantRequests: 1,
+		},
+		"error: one over the maximum is not sent": {
+			request:      request(MaxDevicesPerActivity+1, false),
+			wantRequests: 0,
+			wantTooMany:  &TooManyDevicesError{Count: MaxDevicesPerActivity + 1, Max: MaxDevicesPerActivity},
+		},
+		"error: oversized request rejected by the API": {
+			request:      request(deviceCountOver, true),
+			status:       http.StatusBadRequest,
+			body:         overLimitJSON,
+			wantRequests: 1,
+			wantTooMany:  &TooManyDevicesError{Count: deviceCountOver, Max: MaxDevicesPerActivity},
+			wantAPIError: true,
+		},
+		"error: reworded rejection keyed on code and pointer": {
+			request:      request(deviceCountOver, true),
+			status:       http.StatusBadRequest,
+			body:         rewordedJSON,
+			wantRequests: 1,
+			wantTooMany:  &TooManyDevicesError{Count: deviceCountOver, Max: MaxDevicesPerActivity},
+			wantAPIError: true,
+		},
+		"error: limit from error meta": {
+			request:      request(600, false),
+			status:       http.StatusBadRequest,
+			body:         metaLimitJSON,
+			wantRequests: 1,
+			wantTooMany:  &TooManyDevicesError{Count: 600, Max: 500},
+			wantAPIError: true,
+		},
+		"error: device list error within the limit is not a device limit": {
+			request:      request(10, false),
+			status:       http.StatusBadRequest,
+			body:         duplicatesJSON,
+			wantRequests: 1,
+			wantAPIError: true,
+		},
+		"error: invalid device is not a device limit": {
+			request:      request(10, false),
+			status:       http.StatusBadRequest,
+			body:         invalidIDJSON,
+			wantRequests: 1,
+			wantAPIError: true,
+		},
+		"error: unrelated limit is not a device limit": {
+			request:      request(10, false),
+			status:       http.StatusBadRequest,
+			body:         unrelatedJSON,
+			wantRequests: 1,
+			wantAPIError: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			t.Cleanup(server.Close)
+			client := testClientForServer(t, server)
+
+			_, err := client.Activities().Create(ctx, tt.request)
+			if diff := cmp.Diff(tt.wantRequests, requests.Load()); diff != "" {
+				t.Fatalf("request count mismatch (-want +got):\n%s", diff)
+			}
+
+			if tt.wantTooMany == nil && !tt.wantAPIError {
+				if err != nil {
+					t.Fatalf("Create returned error: %v", err)
+				}
+				return
+			}
+
+			var tooMany *TooManyDevicesError
+			if got := errors.As(err, &tooMany); got != (tt.wantTooMany != nil) {
+				t.Fatalf("Create error = %v, want *TooManyDevicesError: %t", err, tt.wantTooMany != nil)
+			}
+			if tt.wantTooMany != nil {
+				if !errors.Is(err, ErrTooManyDevices) {
+					t.Fatalf("Create error = %v, want ErrTooManyDevices", err)
+				}
+				if diff := cmp.Diff(tt.wantTooMany, tooMany, cmpopts.IgnoreFields(TooManyDevicesError{}, "Err")); diff != "" {
+					t.Fatalf("TooManyDevicesError mismatch (-want +got):\n%s", diff)
+				}
+			}
+			var apiErr *APIError
+			if diff := cmp.Diff(tt.wantAPIError, errors.As(err, &apiErr)); diff != "" {
+				t.Fatalf("APIError presence mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
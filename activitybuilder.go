@@ -0,0 +1,124 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ActivityBuilder builds an [OrgDeviceActivityCreateRequest] step by step, for
+// user interfaces where an operator picks the MDM server and adds and removes
+// devices over time before submitting.
+//
+// An ActivityBuilder is a value: every method returns an updated builder and
+// leaves the receiver unchanged, so keeping earlier builders gives undo, and
+// a copy never shares state with the original. A step that rejects its input
+// returns the receiver unchanged together with an error naming the input, so
+// [ActivityBuilder.Validate] and [ActivityBuilder.Build] only report the
+// selection as it stands.
+type ActivityBuilder struct {
+	activityType OrgDeviceActivityType
+	mdmServerID  string
+
+	// devices are the distinct device IDs in the order they were added.
+	devices []string
+}
+
+// NewActivityBuilder returns a builder for an activity of activityType with no
+// MDM server and no devices.
+func NewActivityBuilder(activityType OrgDeviceActivityType) ActivityBuilder {
+	return ActivityBuilder{activityType: activityType}
+}
+
+// MDMServer sets the MDM server the devices are assigned to or unassigned
+// from, replacing any earlier one. A blank ID is rejected and b is returned
+// unchanged.
+func (b ActivityBuilder) MDMServer(mdmServerID string) (ActivityBuilder, error) {
+	if strings.TrimSpace(mdmServerID) == "" {
+		return b, fmt.Errorf("MDMServer: MDM server ID %q is blank", mdmServerID)
+	}
+
+	b.mdmServerID = mdmServerID
+	return b, nil
+}
+
+// AddDevices adds the devices that are not in the builder yet, keeping their
+// order. If any ID is blank, none of the IDs are added and b is returned
+// unchanged.
+func (b ActivityBuilder) AddDevices(orgDeviceIDs ...string) (ActivityBuilder, error) {
+	for i, id := range orgDeviceIDs {
+		if strings.TrimSpace(id) == "" {
+			return b, fmt.Errorf("AddDevices: org device ID at index %d is blank", i)
+		}
+	}
+
+	devices := slices.Clone(b.devices)
+	for _, id := range orgDeviceIDs {
+		if !slices.Contains(devices, id) {
+			devices = append(devices, id)
+		}
+	}
+
+	b.devices = devices
+	return b, nil
+}
+
+// RemoveDevice removes the device. Removing a device that was not added is a
+// no-op.
+func (b ActivityBuilder) RemoveDevice(orgDeviceID string) ActivityBuilder {
+	i := slices.Index(b.devices, orgDeviceID)
+	if i < 0 {
+		return b
+	}
+
+	b.devices = slices.Delete(slices.Clone(b.devices), i, i+1)
+	return b
+}
+
+// Count returns the number of distinct devices added, for comparison with
+// [MaxDevicesPerActivity].
+func (b ActivityBuilder) Count() int {
+	return len(b.devices)
+}
+
+// Devices returns the device IDs in the order they were added.
+func (b ActivityBuilder) Devices() []string {
+	return slices.Clone(b.devices)
+}
+
+// Validate returns the problems of the request as it stands, such as a
+// missing MDM server, no devices, or more than [MaxDevicesPerActivity]
+// devices, which are reported like [OrgDeviceActivityCreateRequest.Validate]
+// does. It returns nil when [ActivityBuilder.Build] would succeed.
+func (b ActivityBuilder) Validate() error {
+	return b.request().Validate()
+}
+
+// Build returns the request, or the error of [ActivityBuilder.Validate].
+func (b ActivityBuilder) Build() (OrgDeviceActivityCreateRequest, error) {
+	if err := b.Validate(); err != nil {
+		return OrgDeviceActivityCreateRequest{}, err
+	}
+
+	return b.request(), nil
+}
+
+func (b ActivityBuilder) request() OrgDeviceActivityCreateRequest {
+	return newOrgDeviceActivityCreateRequest(b.activityType, b.mdmServerID, b.devices...)
+}
@@ -0,0 +1,264 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestActivityBuilder(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		build       func(t *testing.T) ActivityBuilder
+		wantDevices []string
+		wantErrText []string
+	}{
+		"success: add, remove, and dedupe": {
+			build: func(t *testing.T) ActivityBuilder {
+				must := mustActivityStep(t)
+				b := must(NewActivityBuilder(OrgDeviceActivityTypeAssignDevices).MDMServer("server-1"))
+				b = must(b.AddDevices("device-1", "device-2", "device-1"))
+				b = must(b.AddDevices("device-3", "device-2"))
+				return must(b.RemoveDevice("device-1").AddDevices("device-1"))
+			},
+			wantDevices: []string{"device-2", "device-3", "device-1"},
+		},
+		"success: later MDM server replaces an earlier one": {
+			build: func(t *testing.T) ActivityBuilder {
+				must := mustActivityStep(t)
+				b := must(NewActivityBuilder(OrgDeviceActivityTypeUnassignDevices).MDMServer("server-1"))
+				b = must(b.MDMServer("server-2"))
+				return must(b.AddDevices("device-1"))
+			},
+			wantDevices: []string{"device-1"},
+		},
+		"success: removing a device that was not added is a no-op": {
+			build: func(t *testing.T) ActivityBuilder {
+				must := mustActivityStep(t)
+				b := must(NewActivityBuilder(OrgDeviceActivityTypeAssignDevices).MDMServer("server-1"))
+				return must(b.AddDevices("device-1")).RemoveDevice("device-9")
+			},
+			wantDevices: []string{"device-1"},
+		},
+		"error: empty device set": {
+			build: func(t *testing.T) ActivityBuilder {
+				must := mustActivityStep(t)
+				b := must(NewActivityBuilder(OrgDeviceActivityTypeAssignDevices).MDMServer("server-1"))
+				return must(b.AddDevices("device-1")).RemoveDevice("device-1")
+			},
+			wantDevices: []string{},
+			wantErrText: []string{"at least one org device ID is required"},
+		},
+		"error: missing MDM server": {
+			build: func(t *testing.T) ActivityBuilder {
+				return mustActivityStep(t)(NewActivityBuilder(OrgDeviceActivityTypeAssignDevices).AddDevices("device-1"))
+			},
+			wantDevices: []string{"device-1"},
+			wantErrText: []string{"requires the MDM server the devices are assigned to"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			b := tt.build(t)
+			if diff := cmp.Diff(len(tt.wantDevices), b.Count()); diff != "" {
+				t.Fatalf("Count mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantDevices, b.Devices()); diff != "" {
+				t.Fatalf("devices mismatch (-want +got):\n%s", diff)
+			}
+
+			request, err := b.Build()
+			if len(tt.wantErrText) > 0 {
+				if err == nil {
+					t.Fatal("Build returned nil error")
+				}
+				for _, text := range tt.wantErrText {
+					if !strings.Contains(err.Error(), text) {
+						t.Fatalf("Build error %q does not contain %q", err, text)
+					}
+				}
+				if diff := cmp.Diff(err.Error(), b.Validate().Error()); diff != "" {
+					t.Fatalf("Validate and Build errors differ (-build +validate):\n%s", diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Build returned error: %v", err)
+			}
+
+			var gotDevices []string
+			for _, device := range request.Data.Relationships.Devices.Data {
+				gotDevices = append(gotDevices, device.ID)
+			}
+			if diff := cmp.Diff(tt.wantDevices, gotDevices); diff != "" {
+				t.Fatalf("request devices mismatch (-want +got):\n%s", diff)
+			}
+			if err := request.Validate(); err != nil {
+				t.Fatalf("built request does not validate: %v", err)
+			}
+		})
+	}
+}
+
+func TestActivityBuilder_RejectedSteps(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	base, err := NewActivityBuilder(OrgDeviceActivityTypeAssignDevices).AddDevices("device-1")
+	if err != nil {
+		t.Fatalf("AddDevices returned error: %v", err)
+	}
+
+	tests := map[string]struct {
+		step        func(b ActivityBuilder) (ActivityBuilder, error)
+		wantErrText string
+	}{
+		"error: blank MDM server": {
+			step:        func(b ActivityBuilder) (ActivityBuilder, error) { return b.MDMServer(" ") },
+			wantErrText: `MDMServer: MDM server ID " " is blank`,
+		},
+		"error: blank device among valid ones": {
+			step:        func(b ActivityBuilder) (ActivityBuilder, error) { return b.AddDevices("device-2", "", "device-3") },
+			wantErrText: "AddDevices: org device ID at index 1 is blank",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			got, err := tt.step(base)
+			if err == nil {
+				t.Fatal("step returned nil error")
+			}
+			if diff := cmp.Diff(tt.wantErrText, err.Error()); diff != "" {
+				t.Fatalf("step error mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(base.Devices(), got.Devices()); diff != "" {
+				t.Fatalf("rejected step changed devices (-want +got):\n%s", diff)
+			}
+
+			// A rejected step must not keep the builder from building once
+			// the selection is fixed.
+			fixed, err := got.MDMServer("server-1")
+			if err != nil {
+				t.Fatalf("MDMServer returned error: %v", err)
+			}
+			if _, err := fixed.Build(); err != nil {
+				t.Fatalf("Build after a rejected step returned error: %v", err)
+			}
+		})
+	}
+}
+
+func TestActivityBuilder_TooManyDevices(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	ids := make([]string, MaxDevicesPerActivity+1)
+	for i := range ids {
+		ids[i] = "device-" + strconv.Itoa(i)
+	}
+
+	must := mustActivityStep(t)
+	b := must(NewActivityBuilder(OrgDeviceActivityTypeAssignDevices).MDMServer("server-1"))
+	b = must(b.AddDevices(ids[:MaxDevicesPerActivity]...))
+	if err := b.Validate(); err != nil {
+		t.Fatalf("Validate at the maximum returned error: %v", err)
+	}
+
+	b = must(b.AddDevices(ids[MaxDevicesPerActivity]))
+	if diff := cmp.Diff(MaxDevicesPerActivity+1, b.Count()); diff != "" {
+		t.Fatalf("Count mismatch (-want +got):\n%s", diff)
+	}
+	_, err := b.Build()
+	var tooMany *TooManyDevicesError
+	if !errors.As(err, &tooMany) {
+		t.Fatalf("Build error = %v, want *TooManyDevicesError", err)
+	}
+	if diff := cmp.Diff(&TooManyDevicesError{Count: MaxDevicesPerActivity + 1, Max: MaxDevicesPerActivity}, tooMany); diff != "" {
+		t.Fatalf("TooManyDevicesError mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestActivityBuilder_CopyIndependence(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	must := mustActivityStep(t)
+	base := must(NewActivityBuilder(OrgDeviceActivityTypeAssignDevices).MDMServer("server-1"))
+	base = must(base.AddDevices("device-1", "device-2"))
+	undo := base
+
+	left := must(base.AddDevices("device-3")).RemoveDevice("device-1")
+	right := must(base.AddDevices("device-4")).RemoveDevice("device-2")
+
+	for name, tc := range map[string]struct {
+		b           ActivityBuilder
+		wantDevices []string
+	}{
+		"base":  {b: base, wantDevices: []string{"device-1", "device-2"}},
+		"undo":  {b: undo, wantDevices: []string{"device-1", "device-2"}},
+		"left":  {b: left, wantDevices: []string{"device-2", "device-3"}},
+		"right": {b: right, wantDevices: []string{"device-1", "device-4"}},
+	} {
+		if diff := cmp.Diff(tc.wantDevices, tc.b.Devices()); diff != "" {
+			t.Fatalf("%s devices mismatch (-want +got):\n%s", name, diff)
+		}
+		if err := tc.b.Validate(); err != nil {
+			t.Fatalf("%s Validate returned error: %v", name, err)
+		}
+	}
+}
+
+// mustActivityStep returns a function that unwraps the result of an
+// [ActivityBuilder] step, failing t if the step returned an error.
+func mustActivityStep(t *testing.T) func(ActivityBuilder, error) ActivityBuilder {
+	t.Helper()
+
+	return func(b ActivityBuilder, err error) ActivityBuilder {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("ActivityBuilder step returned error: %v", err)
+		}
+		return b
+	}
+}
@@ -20,12 +20,20 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
+	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
+	"slices"
+	"sync"
+	"syscall"
 	"time"
 
 	jwt "github.com/golang-jwt/jwt/v5"
@@ -48,12 +56,126 @@ const (
 	ScopeBusinessAPI = "business.api"
 )
 
+// reservedAssertionClaims are the registered claims [NewAssertion] always sets itself.
+var reservedAssertionClaims = []string{"iss", "sub", "aud", "exp", "iat", "jti"}
+
+// recentAssertionIDsSize is the number of recently issued assertion IDs
+// [NewAssertion] remembers.
+const recentAssertionIDsSize = 1024
+
+// assertionIDs records the jti claims of recently minted assertions, so that
+// [NewAssertion] never reuses one, which Apple may treat as a replay.
+var assertionIDs = newAssertionIDCache(recentAssertionIDsSize)
+
+// assertionIDCache is a bounded set of issued assertion IDs that forgets the
+// oldest ID once full.
+type assertionIDCache struct {
+	mu     sync.Mutex
+	seen   map[string]struct{}
+	recent []string // ring buffer of the IDs in seen, in issue order
+	next   int      // index in recent of the next ID to replace
+	last   string
+}
+
+func newAssertionIDCache(size int) *assertionIDCache {
+	return &assertionIDCache{
+		seen:   make(map[string]struct{}, size),
+		recent: make([]string, 0, size),
+	}
+}
+
+// issue returns a new ID from newID that is not among the recently issued
+// ones, and records it.
+func (c *assertionIDCache) issue(newID func() string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id := newID()
+	for c.contains(id) {
+		id = newID()
+	}
+
+	if len(c.recent) < cap(c.recent) {
+		c.recent = append(c.recent, id)
+	} else {
+		delete(c.seen, c.recent[c.next])
+		c.recent[c.next] = id
+		c.next = (c.next + 1) % len(c.recent)
+	}
+	c.seen[id] = struct{}{}
+	c.last = id
+
+	return id
+}
+
+// contains reports whether id was issued recently. c.mu must be held.
+func (c *assertionIDCache) contains(id string) bool {
+	_, ok := c.seen[id]
+	return ok
+}
+
+// LastAssertionID returns the jti claim of the assertion most recently minted
+// by [NewAssertion] in this process, or "" if none was. It is meant for
+// debugging token exchanges.
+func LastAssertionID() string {
+	assertionIDs.mu.Lock()
+	defer assertionIDs.mu.Unlock()
+
+	return assertionIDs.last
+}
+
+// AssertionOption configures the client assertion created by [NewAssertion].
+type AssertionOption func(*assertionOptions)
+
+type assertionOptions struct {
+	audience    string
+	extraClaims map[string]any
+}
+
+// WithAudience overrides the "aud" claim of the client assertion, which
+// defaults to [Audience]. It is intended for non-production Apple environments.
+func WithAudience(audience string) AssertionOption {
+	return func(o *assertionOptions) {
+		o.audience = audience
+	}
+}
+
+// WithExtraClaims adds custom claims to the client assertion.
+// Claims that would override iss, sub, aud, exp, iat, or jti are rejected by [NewAssertion].
+func WithExtraClaims(claims map[string]any) AssertionOption {
+	return func(o *assertionOptions) {
+		if o.extraClaims == nil {
+			o.extraClaims = make(map[string]any, len(claims))
+		}
+		maps.Copy(o.extraClaims, claims)
+	}
+}
+
 // NewAssertion creates a signed client assertion for Apple Business Manager (ABM).
-func NewAssertion(ctx context.Context, clientID, keyID, privateKey string) (string, error) {
+// Its jti claim is distinct from those of the last 1024 assertions minted in
+// the process, see [LastAssertionID].
+func NewAssertion(ctx context.Context, clientID, keyID, privateKey string, opts ...AssertionOption) (string, error) {
 	if err := ctx.Err(); err != nil {
 		return "", err
 	}
 
+	options := assertionOptions{
+		audience: Audience,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	if options.audience == "" {
+		return "", fmt.Errorf("assertion audience must not be empty")
+	}
+	for name := range options.extraClaims {
+		if slices.Contains(reservedAssertionClaims, name) {
+			return "", fmt.Errorf("extra claim %q overrides a registered claim", name)
+		}
+	}
+
 	var pkey []byte
 	if _, err := os.Stat(privateKey); err == nil {
 		pkey, err = os.ReadFile(privateKey)
@@ -71,13 +193,27 @@ func NewAssertion(ctx context.Context, clientID, keyID, privateKey string) (stri
 
 	issuedAt := time.Now().UTC()
 	expiresAt := issuedAt.Add(180 * 24 * time.Hour) // 180 days
-	claims := jwt.RegisteredClaims{
+	registered := jwt.RegisteredClaims{
 		Issuer:    clientID,
 		Subject:   clientID,
-		Audience:  jwt.ClaimStrings{Audience},
+		Audience:  jwt.ClaimStrings{options.audience},
 		ExpiresAt: jwt.NewNumericDate(expiresAt),
 		IssuedAt:  jwt.NewNumericDate(issuedAt),
-		ID:        uuid.NewString(),
+		ID:        assertionIDs.issue(uuid.NewString),
+	}
+
+	var claims jwt.Claims = registered
+	if len(options.extraClaims) > 0 {
+		mapClaims := jwt.MapClaims{
+			"iss": registered.Issuer,
+			"sub": registered.Subject,
+			"aud": registered.Audience,
+			"exp": registered.ExpiresAt,
+			"iat": registered.IssuedAt,
+			"jti": registered.ID,
+		}
+		maps.Copy(mapClaims, options.extraClaims)
+		claims = mapClaims
 	}
 	token := &jwt.Token{
 		Header: map[string]any{
@@ -131,15 +267,74 @@ func parseECDSAPrivateKeyFromPEM(pemBytes []byte) (*ecdsa.PrivateKey, error) {
 	}
 }
 
+const (
+	defaultTokenMaxRetries     = 3
+	defaultTokenRetryBudget    = 30 * time.Second
+	defaultTokenRetryBaseDelay = 500 * time.Millisecond
+	defaultTokenRetryMaxDelay  = 8 * time.Second
+)
+
+// TokenSourceOption configures the token source returned by [NewTokenSource].
+type TokenSourceOption func(*tokenSourceOptions)
+
+type tokenSourceOptions struct {
+	tokenURL   string
+	maxRetries int
+	budget     time.Duration
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+}
+
+// WithTokenURL overrides the OAuth2 token endpoint, which defaults to [TokenURL].
+// Use it together with [WithAudience] for non-production Apple environments.
+func WithTokenURL(tokenURL string) TokenSourceOption {
+	return func(o *tokenSourceOptions) {
+		o.tokenURL = tokenURL
+	}
+}
+
+// WithTokenMaxRetries sets how many times a failed token request is retried.
+// Only server errors (5xx), timeouts, and connection failures are retried;
+// client errors such as invalid_client and TLS certificate errors are
+// returned immediately. Zero disables retries.
+func WithTokenMaxRetries(n int) TokenSourceOption {
+	return func(o *tokenSourceOptions) {
+		o.maxRetries = max(n, 0)
+	}
+}
+
+// WithTokenRetryBudget bounds the total time spent obtaining a token,
+// including all retries and backoff delays. Zero removes the bound.
+func WithTokenRetryBudget(d time.Duration) TokenSourceOption {
+	return func(o *tokenSourceOptions) {
+		o.budget = max(d, 0)
+	}
+}
+
+// withTokenRetryDelay overrides the backoff delays; used by tests.
+func withTokenRetryDelay(base, maxDelay time.Duration) TokenSourceOption {
+	return func(o *tokenSourceOptions) {
+		o.baseDelay = base
+		o.maxDelay = maxDelay
+	}
+}
+
 type clientCredentialsTokenSource struct {
 	ctx    context.Context
 	config clientcredentials.Config
+	opts   tokenSourceOptions
 }
 
 var _ oauth2.TokenSource = (*clientCredentialsTokenSource)(nil)
 
 // NewTokenSource returns a token source for Apple Business Manager using a JWT client assertion.
-func NewTokenSource(ctx context.Context, httpClient *http.Client, clientID, clientAssertion, scope string) (oauth2.TokenSource, error) {
+//
+// Failed token requests are retried with jittered exponential backoff, see
+// [WithTokenMaxRetries] and [WithTokenRetryBudget]. The returned token source
+// serializes token fetches, so concurrent API requests during an
+// authorization outage wait for a single retry sequence instead of each
+// retrying independently.
+func NewTokenSource(ctx context.Context, httpClient *http.Client, clientID, clientAssertion, scope string, opts ...TokenSourceOption) (oauth2.TokenSource, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
@@ -159,6 +354,22 @@ func NewTokenSource(ctx context.Context, httpClient *http.Client, clientID, clie
 		}
 	}
 
+	options := tokenSourceOptions{
+		tokenURL:   TokenURL,
+		maxRetries: defaultTokenMaxRetries,
+		budget:     defaultTokenRetryBudget,
+		baseDelay:  defaultTokenRetryBaseDelay,
+		maxDelay:   defaultTokenRetryMaxDelay,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	if options.tokenURL == "" {
+		return nil, fmt.Errorf("token URL must not be empty")
+	}
+
 	tokenCtx := context.WithValue(ctx, oauth2.HTTPClient, httpClient)
 
 	params := url.Values{}
@@ -167,7 +378,7 @@ func NewTokenSource(ctx context.Context, httpClient *http.Client, clientID, clie
 
 	config := clientcredentials.Config{
 		ClientID:       clientID,
-		TokenURL:       TokenURL,
+		TokenURL:       options.tokenURL,
 		Scopes:         []string{scope},
 		EndpointParams: params,
 		AuthStyle:      oauth2.AuthStyleInParams,
@@ -175,6 +386,7 @@ func NewTokenSource(ctx context.Context, httpClient *http.Client, clientID, clie
 	src := &clientCredentialsTokenSource{
 		ctx:    tokenCtx,
 		config: config,
+		opts:   options,
 	}
 
 	return oauth2.ReuseTokenSource(nil, src), nil
@@ -186,10 +398,88 @@ func (ts *clientCredentialsTokenSource) Token() (*oauth2.Token, error) {
 		return nil, err
 	}
 
-	token, err := ts.config.Token(ts.ctx)
-	if err != nil {
-		return nil, fmt.Errorf("token request: %w", err)
+	ctx := ts.ctx
+	if ts.opts.budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ts.opts.budget)
+		defer cancel()
+	}
+
+	for attempt := 0; ; attempt++ {
+		token, err := ts.config.Token(ctx)
+		if err == nil {
+			return token, nil
+		}
+		err = fmt.Errorf("token request: %w", err)
+
+		if ctx.Err() != nil && ts.ctx.Err() == nil {
+			return nil, ts.budgetExceeded(attempt+1, err)
+		}
+		if attempt >= ts.opts.maxRetries || !isRetryableTokenError(err) {
+			return nil, err
+		}
+
+		delay := ts.backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return nil, ts.budgetExceeded(attempt+1, err)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if ts.ctx.Err() == nil {
+				return nil, ts.budgetExceeded(attempt+1, err)
+			}
+			return nil, errors.Join(ts.ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
+
+func (ts *clientCredentialsTokenSource) budgetExceeded(attempts int, err error) error {
+	return fmt.Errorf("token retry budget %s exceeded after %d attempts: %w", ts.opts.budget, attempts, err)
+}
+
+// backoff returns the jittered delay before retry attempt+1.
+func (ts *clientCredentialsTokenSource) backoff(attempt int) time.Duration {
+	return jitteredBackoff(attempt, ts.opts.baseDelay, ts.opts.maxDelay)
+}
+
+// isRetryableTokenError reports whether a token request failure is transient:
+// a 5xx response from the token endpoint, a timeout, a refused, reset, or
+// prematurely closed connection, or a temporary DNS failure. Errors that
+// never go away on retry, such as a failed TLS certificate verification or an
+// unsupported URL scheme, are not retried, even though *url.Error implements
+// [net.Error].
+func isRetryableTokenError(err error) bool {
+	if isContextError(err) {
+		return false
+	}
+
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) {
+		return retrieveErr.Response != nil && retrieveErr.Response.StatusCode >= http.StatusInternalServerError
+	}
+
+	var (
+		certErr   *tls.CertificateVerificationError
+		alertErr  tls.AlertError
+		recordErr tls.RecordHeaderError
+	)
+	if errors.As(err, &certErr) || errors.As(err, &alertErr) || errors.As(err, &recordErr) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTemporary || dnsErr.IsTimeout
 	}
 
-	return token, nil
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, io.ErrUnexpectedEOF)
 }
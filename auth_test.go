@@ -22,11 +22,14 @@ import (
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
 	"errors"
 	"fmt"
 	"io"
+	"log"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -34,11 +37,13 @@ import (
 	"path/filepath"
 	"strings"
 	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 
 	jwt "github.com/golang-jwt/jwt/v5"
 	"github.com/google/go-cmp/cmp"
+	"golang.org/x/oauth2"
 )
 
 func TestParseECDSAPrivateKeyFromPEM(t *testing.T) {
@@ -230,6 +235,86 @@ func TestNewAssertion(t *testing.T) {
 	}
 }
 
+func TestNewAssertion_UniqueJTI(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate P-256 key: %v", err)
+	}
+	p256PKCS8, err := x509.MarshalPKCS8PrivateKey(p256Key)
+	if err != nil {
+		t.Fatalf("marshal P-256 PKCS8 key: %v", err)
+	}
+	p256PEM := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: p256PKCS8}))
+
+	seen := make(map[string]bool)
+	for i := range 5 {
+		tokenString, err := NewAssertion(ctx, "client-id", "key-id", p256PEM)
+		if err != nil {
+			t.Fatalf("NewAssertion %d returned error: %v", i, err)
+		}
+
+		claims := &jwt.RegisteredClaims{}
+		if _, err := jwt.ParseWithClaims(tokenString, claims, func(*jwt.Token) (any, error) {
+			return &p256Key.PublicKey, nil
+		}); err != nil {
+			t.Fatalf("parse token %d: %v", i, err)
+		}
+		if seen[claims.ID] {
+			t.Fatalf("jti %q reused by assertion %d", claims.ID, i)
+		}
+		seen[claims.ID] = true
+
+		assertionIDs.mu.Lock()
+		recorded := assertionIDs.contains(claims.ID)
+		assertionIDs.mu.Unlock()
+		if !recorded {
+			t.Fatalf("jti %q of assertion %d is not recorded", claims.ID, i)
+		}
+		if diff := cmp.Diff(claims.ID, LastAssertionID()); diff != "" {
+			t.Fatalf("LastAssertionID mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestAssertionIDCache(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	// ids returns a generator yielding values in order.
+	ids := func(values ...string) func() string {
+		return func() string {
+			id := values[0]
+			values = values[1:]
+			return id
+		}
+	}
+
+	cache := newAssertionIDCache(2)
+	var got []string
+	got = append(got, cache.issue(ids("a")))
+	got = append(got, cache.issue(ids("a", "a", "b"))) // collisions are regenerated
+	got = append(got, cache.issue(ids("c")))           // evicts "a"
+	got = append(got, cache.issue(ids("b", "a")))      // "b" is still recent
+	if diff := cmp.Diff([]string{"a", "b", "c", "a"}, got); diff != "" {
+		t.Fatalf("issued IDs mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff("a", cache.last); diff != "" {
+		t.Fatalf("last ID mismatch (-want +got):\n%s", diff)
+	}
+	for id, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if diff := cmp.Diff(want, cache.contains(id)); diff != "" {
+			t.Fatalf("contains(%q) mismatch (-want +got):\n%s", id, diff)
+		}
+	}
+}
+
 func TestNewAssertionErrors(t *testing.T) {
 	ctx := t.Context()
 	if err := ctx.Err(); err != nil {
@@ -307,6 +392,120 @@ func TestNewAssertionCanceledContext(t *testing.T) {
 	}
 }
 
+func TestNewAssertionOptions(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	clientID := "BUSINESSAPI.9703f56c-10ce-4876-8f59-e78e5e23a152"
+	keyID := "d136aa66-0c3b-4bd4-9892-c20e8db024ab"
+
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate P-256 key: %v", err)
+	}
+	p256PKCS8, err := x509.MarshalPKCS8PrivateKey(p256Key)
+	if err != nil {
+		t.Fatalf("marshal P-256 PKCS8 key: %v", err)
+	}
+	p256PEM := string(pem.EncodeToMemory(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: p256PKCS8,
+	}))
+
+	tests := map[string]struct {
+		opts         []AssertionOption
+		wantAudience string
+		wantClaims   map[string]any
+		wantErr      string
+	}{
+		"success: default audience": {
+			wantAudience: Audience,
+		},
+		"success: audience override": {
+			opts:         []AssertionOption{WithAudience("https://account.example.test/auth/oauth2/v2/token")},
+			wantAudience: "https://account.example.test/auth/oauth2/v2/token",
+		},
+		"success: extra claims": {
+			opts: []AssertionOption{
+				WithAudience("https://account.example.test/auth/oauth2/v2/token"),
+				WithExtraClaims(map[string]any{"env": "uat"}),
+				WithExtraClaims(map[string]any{"tenant": "qa"}),
+			},
+			wantAudience: "https://account.example.test/auth/oauth2/v2/token",
+			wantClaims: map[string]any{
+				"env":    "uat",
+				"tenant": "qa",
+			},
+		},
+		"error: empty audience": {
+			opts:    []AssertionOption{WithAudience("")},
+			wantErr: "assertion audience must not be empty",
+		},
+		"error: extra claim overrides aud": {
+			opts:    []AssertionOption{WithExtraClaims(map[string]any{"aud": "other"})},
+			wantErr: `extra claim "aud" overrides a registered claim`,
+		},
+		"error: extra claim overrides jti": {
+			opts:    []AssertionOption{WithExtraClaims(map[string]any{"jti": "fixed"})},
+			wantErr: `extra claim "jti" overrides a registered claim`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			tokenString, err := NewAssertion(ctx, clientID, keyID, p256PEM, tt.opts...)
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				if diff := cmp.Diff(tt.wantErr, err.Error()); diff != "" {
+					t.Fatalf("error mismatch (-want +got):\n%s", diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewAssertion returned error: %v", err)
+			}
+
+			claims := jwt.MapClaims{}
+			if _, err := jwt.ParseWithClaims(tokenString, claims, func(*jwt.Token) (any, error) {
+				return &p256Key.PublicKey, nil
+			}, jwt.WithAudience(tt.wantAudience)); err != nil {
+				t.Fatalf("parse token: %v", err)
+			}
+
+			if diff := cmp.Diff([]any{tt.wantAudience}, claims["aud"]); diff != "" {
+				t.Fatalf("audience mismatch (-want +got):\n%s", diff)
+			}
+			for _, name := range []string{"iss", "sub"} {
+				if diff := cmp.Diff(clientID, claims[name]); diff != "" {
+					t.Fatalf("%s mismatch (-want +got):\n%s", name, diff)
+				}
+			}
+			for _, name := range []string{"exp", "iat", "jti"} {
+				if _, ok := claims[name]; !ok {
+					t.Fatalf("missing %s claim", name)
+				}
+			}
+			for name, want := range tt.wantClaims {
+				if diff := cmp.Diff(want, claims[name]); diff != "" {
+					t.Fatalf("claim %s mismatch (-want +got):\n%s", name, diff)
+				}
+			}
+			if diff := cmp.Diff(6+len(tt.wantClaims), len(claims)); diff != "" {
+				t.Fatalf("claim count mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestNewClientCredentialsTokenSource(t *testing.T) {
 	ctx := t.Context()
 	if err := ctx.Err(); err != nil {
@@ -482,6 +681,41 @@ func TestClientCredentialsTokenSourceFormBody(t *testing.T) {
 	}
 }
 
+func TestNewTokenSourceWithTokenURL(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	pathCh := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pathCh <- r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"abc123","token_type":"Bearer","expires_in":3600}`)
+	}))
+	t.Cleanup(server.Close)
+
+	source, err := NewTokenSource(ctx, server.Client(), "client-id", "assertion", "business.api", WithTokenURL(server.URL+"/uat/oauth2/token"))
+	if err != nil {
+		t.Fatalf("NewTokenSource returned error: %v", err)
+	}
+
+	token, err := source.Token()
+	if err != nil {
+		t.Fatalf("Token returned error: %v", err)
+	}
+	if diff := cmp.Diff("abc123", token.AccessToken); diff != "" {
+		t.Fatalf("access token mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff("/uat/oauth2/token", <-pathCh); diff != "" {
+		t.Fatalf("token path mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := NewTokenSource(ctx, server.Client(), "client-id", "assertion", "business.api", WithTokenURL("")); err == nil {
+		t.Fatal("expected error for empty token URL")
+	}
+}
+
 func TestDecodeOrgDevices(t *testing.T) {
 	ctx := t.Context()
 	if err := ctx.Err(); err != nil {
@@ -604,3 +838,253 @@ func TestOrgDevicePartNumberPagesPagination(t *testing.T) {
 		})
 	}
 }
+
+func TestClientCredentialsTokenSourceRetry(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		statuses     []int
+		opts         []TokenSourceOption
+		wantRequests int32
+		wantErr      bool
+		wantErrText  string
+	}{
+		"success: fails twice then succeeds": {
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			wantRequests: 3,
+		},
+		"success: no failures": {
+			statuses:     []int{http.StatusOK},
+			wantRequests: 1,
+		},
+		"error: invalid client is not retried": {
+			statuses:     []int{http.StatusBadRequest, http.StatusOK},
+			wantRequests: 1,
+			wantErr:      true,
+		},
+		"error: unauthorized is not retried": {
+			statuses:     []int{http.StatusUnauthorized, http.StatusOK},
+			wantRequests: 1,
+			wantErr:      true,
+		},
+		"error: max retries exhausted": {
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK},
+			opts:         []TokenSourceOption{WithTokenMaxRetries(1)},
+			wantRequests: 2,
+			wantErr:      true,
+		},
+		"error: retries disabled": {
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusOK},
+			opts:         []TokenSourceOption{WithTokenMaxRetries(0)},
+			wantRequests: 1,
+			wantErr:      true,
+		},
+		"error: budget exceeded": {
+			statuses: []int{http.StatusServiceUnavailable},
+			opts: []TokenSourceOption{
+				WithTokenMaxRetries(100),
+				WithTokenRetryBudget(100 * time.Millisecond),
+				withTokenRetryDelay(40*time.Millisecond, 40*time.Millisecond),
+			},
+			wantErr:     true,
+			wantErrText: "token retry budget 100ms exceeded",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			var requestCount atomic.Int32
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(requestCount.Add(1))
+				status := tt.statuses[min(n, len(tt.statuses))-1]
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(status)
+				switch {
+				case status == http.StatusOK:
+					fmt.Fprint(w, `{"access_token":"abc123","token_type":"Bearer","expires_in":3600}`)
+				case status < http.StatusInternalServerError:
+					fmt.Fprint(w, `{"error":"invalid_client"}`)
+				default:
+					fmt.Fprint(w, `{"error":"temporarily_unavailable"}`)
+				}
+			}))
+			t.Cleanup(server.Close)
+
+			httpClient, err := newTLSServerHTTPClient(server)
+			if err != nil {
+				t.Fatalf("newTLSServerHTTPClient returned error: %v", err)
+			}
+
+			opts := append([]TokenSourceOption{withTokenRetryDelay(time.Millisecond, 2*time.Millisecond)}, tt.opts...)
+			source, err := NewTokenSource(ctx, httpClient, "client-id", "assertion", "", opts...)
+			if err != nil {
+				t.Fatalf("NewTokenSource returned error: %v", err)
+			}
+
+			token, err := source.Token()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Token error mismatch: err=%v wantErr=%v", err, tt.wantErr)
+			}
+			if tt.wantErrText != "" && !strings.Contains(err.Error(), tt.wantErrText) {
+				t.Fatalf("Token error %q does not contain %q", err, tt.wantErrText)
+			}
+			if tt.wantRequests > 0 {
+				if got := requestCount.Load(); got != tt.wantRequests {
+					t.Fatalf("unexpected token request count: got=%d want=%d", got, tt.wantRequests)
+				}
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff("abc123", token.AccessToken); diff != "" {
+				t.Fatalf("access token mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClientCredentialsTokenSourceTLSVerificationNotRetried(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	var conns atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("token request reached the server despite an untrusted certificate")
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	// The default client does not trust the test server's certificate.
+	httpClient := &http.Client{Transport: http.DefaultTransport.(*http.Transport).Clone()}
+	source, err := NewTokenSource(ctx, httpClient, "client-id", "assertion", "", WithTokenURL(server.URL), withTokenRetryDelay(time.Millisecond, 2*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewTokenSource returned error: %v", err)
+	}
+
+	_, err = source.Token()
+	var certErr *tls.CertificateVerificationError
+	if !errors.As(err, &certErr) {
+		t.Fatalf("expected *tls.CertificateVerificationError, got %v", err)
+	}
+	if diff := cmp.Diff(int32(1), conns.Load()); diff != "" {
+		t.Fatalf("connection count mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestIsRetryableTokenError(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		err  error
+		want bool
+	}{
+		"success: server error": {
+			err: &oauth2.RetrieveError{
+				Response: &http.Response{StatusCode: http.StatusServiceUnavailable},
+			},
+			want: true,
+		},
+		"success: connection refused": {
+			err: &url.Error{
+				Op:  "Post",
+				URL: TokenURL,
+				Err: &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)},
+			},
+			want: true,
+		},
+		"success: connection reset": {
+			err: &url.Error{
+				Op:  "Post",
+				URL: TokenURL,
+				Err: &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)},
+			},
+			want: true,
+		},
+		"success: timeout": {
+			err: &url.Error{
+				Op:  "Post",
+				URL: TokenURL,
+				Err: &net.OpError{Op: "dial", Err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}},
+			},
+			want: true,
+		},
+		"success: unknown host": {
+			err: &url.Error{
+				Op:  "Post",
+				URL: TokenURL,
+				Err: &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", IsNotFound: true}},
+			},
+		},
+		"success: certificate verification failure": {
+			err: &url.Error{
+				Op:  "Post",
+				URL: TokenURL,
+				Err: &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}},
+			},
+		},
+		"success: unsupported scheme": {
+			err: &url.Error{
+				Op:  "Post",
+				URL: "ftp://example.com/token",
+				Err: errors.New(`unsupported protocol scheme "ftp"`),
+			},
+		},
+		"success: invalid client": {
+			err: &oauth2.RetrieveError{
+				Response:  &http.Response{StatusCode: http.StatusBadRequest},
+				ErrorCode: "invalid_client",
+			},
+		},
+		"success: unauthorized": {
+			err: &oauth2.RetrieveError{
+				Response: &http.Response{StatusCode: http.StatusUnauthorized},
+			},
+		},
+		"success: canceled context": {
+			err: fmt.Errorf("token request: %w", context.Canceled),
+		},
+		"success: deadline exceeded": {
+			err: &url.Error{
+				Op:  "Post",
+				URL: TokenURL,
+				Err: context.DeadlineExceeded,
+			},
+		},
+		"success: unrelated error": {
+			err: errors.New("oauth2: cannot parse json"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, isRetryableTokenError(tt.err)); diff != "" {
+				t.Fatalf("isRetryableTokenError mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"net/http"
+	"time"
+)
+
+// CallOption configures a single call of a [Client] method, such as
+// [OrgDevicesService.Get], without changing the client.
+type CallOption func(*exchange)
+
+// WithHeader sets the request header key to value for the call, replacing any
+// value the client would send, such as Accept-Language.
+func WithHeader(key, value string) CallOption {
+	return func(ex *exchange) {
+		if ex.header == nil {
+			ex.header = http.Header{}
+		}
+		ex.header.Set(key, value)
+	}
+}
+
+// WithCallTimeout limits each HTTP attempt of the call, including the read of
+// its response body, to d, in place of the Timeout of the [http.Client] the
+// client was created with. A value <= 0 keeps the client's timeout.
+func WithCallTimeout(d time.Duration) CallOption {
+	return func(ex *exchange) {
+		ex.timeout = d
+	}
+}
+
+// NoRetry disables the retries of the client's [RetryPolicy] for the call.
+func NoRetry() CallOption {
+	return func(ex *exchange) {
+		ex.noRetry = true
+	}
+}
+
+// newCallExchange returns an exchange with opts applied.
+func newCallExchange(opts []CallOption) *exchange {
+	ex := &exchange{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(ex)
+		}
+	}
+
+	return ex
+}
@@ -0,0 +1,227 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/oauth2"
+)
+
+func TestCallOptions_Header(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		clientOpts   []ClientOption
+		callOpts     []CallOption
+		wantTrace    string
+		wantLanguage string
+	}{
+		"success: per-call header": {
+			callOpts:  []CallOption{WithHeader("X-Trace-Id", "trace-1")},
+			wantTrace: "trace-1",
+		},
+		"success: later header replaces earlier": {
+			callOpts:  []CallOption{WithHeader("x-trace-id", "trace-1"), WithHeader("X-Trace-Id", "trace-2")},
+			wantTrace: "trace-2",
+		},
+		"success: per-call header overrides client Accept-Language": {
+			clientOpts:   []ClientOption{WithAcceptLanguage("en-US")},
+			callOpts:     []CallOption{WithHeader("Accept-Language", "ja-JP")},
+			wantLanguage: "ja-JP",
+		},
+		"success: client Accept-Language without call options": {
+			clientOpts:   []ClientOption{WithAcceptLanguage("en-US")},
+			wantLanguage: "en-US",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			var (
+				mu     sync.Mutex
+				header http.Header
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				header = r.Header.Clone()
+				mu.Unlock()
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"data":{"id":"device-1","type":"orgDevices"}}`)
+			}))
+			t.Cleanup(server.Close)
+			client := testClientForServer(t, server, tt.clientOpts...)
+
+			if _, err := client.GetOrgDevice(ctx, "device-1", nil, tt.callOpts...); err != nil {
+				t.Fatalf("GetOrgDevice returned error: %v", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if diff := cmp.Diff(tt.wantTrace, header.Get("X-Trace-Id")); diff != "" {
+				t.Fatalf("X-Trace-Id mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantLanguage, header.Get("Accept-Language")); diff != "" {
+				t.Fatalf("Accept-Language mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff("Bearer test-token", header.Get("Authorization")); diff != "" {
+				t.Fatalf("Authorization mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCallOptions_Timeout(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	const serverDelay = 200 * time.Millisecond
+
+	tests := map[string]struct {
+		clientTimeout time.Duration
+		callOpts      []CallOption
+		wantErr       bool
+	}{
+		"success: call timeout longer than client default": {
+			clientTimeout: 20 * time.Millisecond,
+			callOpts:      []CallOption{WithCallTimeout(5 * time.Second)},
+		},
+		"success: non-positive call timeout keeps client default": {
+			callOpts: []CallOption{WithCallTimeout(0)},
+		},
+		"error: client default applies without call options": {
+			clientTimeout: 20 * time.Millisecond,
+			wantErr:       true,
+		},
+		"error: call timeout shorter than client default": {
+			callOpts: []CallOption{WithCallTimeout(20 * time.Millisecond)},
+			wantErr:  true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-time.After(serverDelay):
+				case <-r.Context().Done():
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"data":{"id":"device-1","type":"orgDevices"}}`)
+			}))
+			t.Cleanup(server.Close)
+
+			httpClient := server.Client()
+			httpClient.Timeout = tt.clientTimeout
+			tokenSource := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token"})
+			client, err := NewClientWithBaseURL(httpClient, tokenSource, server.URL)
+			if err != nil {
+				t.Fatalf("NewClientWithBaseURL returned error: %v", err)
+			}
+
+			// oauth2.Transport logs a deprecation warning when a timeout is
+			// enforced through its CancelRequest method.
+			var logs bytes.Buffer
+			log.SetOutput(&logs)
+			t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+			_, err = client.GetOrgDevice(ctx, "device-1", nil, tt.callOpts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetOrgDevice error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+			}
+			if strings.Contains(logs.String(), "CancelRequest") {
+				t.Fatalf("unexpected CancelRequest log: %s", logs.String())
+			}
+		})
+	}
+}
+
+func TestCallOptions_NoRetry(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		callOpts     []CallOption
+		wantRequests int32
+	}{
+		"success: client retry policy applies by default": {
+			wantRequests: 3,
+		},
+		"success: no retry": {
+			callOpts:     []CallOption{NoRetry()},
+			wantRequests: 1,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			t.Cleanup(server.Close)
+			client := testClientForServer(t, server, WithRetryPolicy(RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}))
+
+			if _, err := client.GetMDMServers(ctx, nil, tt.callOpts...); err == nil {
+				t.Fatal("GetMDMServers returned nil error")
+			}
+			if diff := cmp.Diff(tt.wantRequests, requests.Load()); diff != "" {
+				t.Fatalf("request count mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
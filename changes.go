@@ -0,0 +1,340 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-json-experiment/json"
+)
+
+// DeviceState is the part of an organization device watched by a
+// [DeviceChangeDetector].
+type DeviceState struct {
+	Status                  OrgDeviceAttributesStatus `json:"status,omitzero"`
+	AssignedServerID        string                    `json:"assignedServerId,omitzero"`
+	ReleasedFromOrgDateTime time.Time                 `json:"releasedFromOrgDateTime,omitzero"`
+}
+
+// changedFields returns the names of the fields that differ between s and t.
+// Times are compared as instants, so a different time zone is not a change.
+func (s DeviceState) changedFields(t DeviceState) []string {
+	var fields []string
+	if s.Status != t.Status {
+		fields = append(fields, "status")
+	}
+	if s.AssignedServerID != t.AssignedServerID {
+		fields = append(fields, "assignedServer")
+	}
+	if !s.ReleasedFromOrgDateTime.Equal(t.ReleasedFromOrgDateTime) {
+		fields = append(fields, "releasedFromOrgDateTime")
+	}
+
+	return fields
+}
+
+// DeviceSnapshot maps organization device IDs to their watched state.
+type DeviceSnapshot map[string]DeviceState
+
+// ChangeKind is the kind of a [ChangeEvent].
+type ChangeKind int
+
+const (
+	// DeviceAdded reports a device that is new to the organization.
+	DeviceAdded ChangeKind = iota + 1
+
+	// DeviceRemoved reports a device that is no longer in the organization.
+	DeviceRemoved
+
+	// DeviceModified reports a device whose watched state changed.
+	DeviceModified
+)
+
+// String implements [fmt.Stringer].
+func (k ChangeKind) String() string {
+	switch k {
+	case DeviceAdded:
+		return "added"
+	case DeviceRemoved:
+		return "removed"
+	case DeviceModified:
+		return "modified"
+	default:
+		return "unknown"
+	}
+}
+
+// ChangeEvent is a change to a device between two snapshots.
+type ChangeEvent struct {
+	Kind     ChangeKind
+	DeviceID string
+
+	// Before and After are the device state in the older and newer snapshot.
+	// Before is zero for an added device and After is zero for a removed one.
+	Before DeviceState
+	After  DeviceState
+
+	// Fields names the changed fields of a modified device: "status",
+	// "assignedServer", or "releasedFromOrgDateTime".
+	Fields []string
+}
+
+// DiffDeviceSnapshots returns the changes that turn before into after,
+// ordered by device ID. Devices whose state is unchanged produce no event.
+func DiffDeviceSnapshots(before, after DeviceSnapshot) []ChangeEvent {
+	ids := slices.AppendSeq(slices.Collect(maps.Keys(before)), maps.Keys(after))
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	var events []ChangeEvent
+	for _, id := range ids {
+		previous, inBefore := before[id]
+		current, inAfter := after[id]
+		switch {
+		case !inBefore:
+			events = append(events, ChangeEvent{Kind: DeviceAdded, DeviceID: id, After: current})
+		case !inAfter:
+			events = append(events, ChangeEvent{Kind: DeviceRemoved, DeviceID: id, Before: previous})
+		default:
+			if fields := previous.changedFields(current); len(fields) > 0 {
+				events = append(events, ChangeEvent{Kind: DeviceModified, DeviceID: id, Before: previous, After: current, Fields: fields})
+			}
+		}
+	}
+
+	return events
+}
+
+// SnapshotStore persists the device snapshot of a [DeviceChangeDetector].
+type SnapshotStore interface {
+	// LoadSnapshot returns the stored snapshot, or nil when none is stored yet.
+	LoadSnapshot(ctx context.Context) (DeviceSnapshot, error)
+
+	// SaveSnapshot replaces the stored snapshot.
+	SaveSnapshot(ctx context.Context, snapshot DeviceSnapshot) error
+}
+
+// FileSnapshotStore is a [SnapshotStore] that keeps the snapshot as JSON in
+// the file at Path.
+type FileSnapshotStore struct {
+	Path string
+}
+
+var _ SnapshotStore = (*FileSnapshotStore)(nil)
+
+// LoadSnapshot implements [SnapshotStore]. A missing file means no snapshot.
+func (s *FileSnapshotStore) LoadSnapshot(ctx context.Context) (DeviceSnapshot, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read snapshot: %w", err)
+	}
+
+	snapshot := DeviceSnapshot{}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("decode snapshot %s: %w", s.Path, err)
+	}
+
+	return snapshot, nil
+}
+
+// SaveSnapshot implements [SnapshotStore]. The file is replaced atomically,
+// so a crash never leaves a partially written snapshot behind.
+func (s *FileSnapshotStore) SaveSnapshot(ctx context.Context, snapshot DeviceSnapshot) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(snapshot, json.Deterministic(true))
+	if err != nil {
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.Path); err != nil {
+		return fmt.Errorf("replace snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// ChangeSink receives the change events found by a [DeviceChangeDetector].
+type ChangeSink func(ctx context.Context, event ChangeEvent) error
+
+// DeviceChangeDetector reports changes to the assignment status, assigned MDM
+// server, and release time of organization devices by polling the API and
+// comparing each result with the previous one.
+type DeviceChangeDetector struct {
+	// Store keeps the snapshot between polls and across restarts.
+	Store SnapshotStore
+
+	// Sink receives every change event of a poll, ordered by device ID.
+	Sink ChangeSink
+}
+
+// LoadSnapshot returns the snapshot held by the detector's store, or nil when
+// none is stored yet.
+func (d *DeviceChangeDetector) LoadSnapshot(ctx context.Context) (DeviceSnapshot, error) {
+	if d.Store == nil {
+		return nil, fmt.Errorf("snapshot store is required")
+	}
+
+	return d.Store.LoadSnapshot(ctx)
+}
+
+// SaveSnapshot replaces the snapshot held by the detector's store.
+func (d *DeviceChangeDetector) SaveSnapshot(ctx context.Context, snapshot DeviceSnapshot) error {
+	if d.Store == nil {
+		return fmt.Errorf("snapshot store is required")
+	}
+
+	return d.Store.SaveSnapshot(ctx, snapshot)
+}
+
+// Poll fetches the current device snapshot through client, delivers its
+// differences from the stored snapshot to the sink, and stores it.
+//
+// When no snapshot is stored yet the current one becomes the baseline and no
+// events are delivered. When the sink returns an error the stored snapshot is
+// kept, so the same changes are delivered again by the next poll.
+func (d *DeviceChangeDetector) Poll(ctx context.Context, client *Client) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if d.Sink == nil {
+		return fmt.Errorf("change sink is required")
+	}
+
+	previous, err := d.LoadSnapshot(ctx)
+	if err != nil {
+		return fmt.Errorf("load snapshot: %w", err)
+	}
+
+	current, err := client.deviceSnapshot(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch device snapshot: %w", err)
+	}
+
+	if previous != nil {
+		for _, event := range DiffDeviceSnapshots(previous, current) {
+			if err := d.Sink(ctx, event); err != nil {
+				return fmt.Errorf("deliver %s event for device %q: %w", event.Kind, event.DeviceID, err)
+			}
+		}
+	}
+
+	if err := d.SaveSnapshot(ctx, current); err != nil {
+		return fmt.Errorf("save snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// Run polls immediately and then every interval until ctx is done or a poll
+// fails, and returns the context error or the poll error.
+func (d *DeviceChangeDetector) Run(ctx context.Context, client *Client, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("poll interval must be positive: %v", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.Poll(ctx, client); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// deviceSnapshot fetches the watched state of every organization device.
+// Devices are listed with only the watched fields, and assigned servers are
+// taken from each MDM server's device linkages.
+func (c *Client) deviceSnapshot(ctx context.Context) (DeviceSnapshot, error) {
+	query := url.Values{}
+	query.Set("fields[orgDevices]", "status,releasedFromOrgDateTime")
+	query.Set("limit", strconv.Itoa(maxPageLimit))
+
+	snapshot := DeviceSnapshot{}
+	for page, err := range crawlPages(ctx, c, orgDevicesPath, query, func(r *OrgDevicesResponse) string { return r.Links.Next }) {
+		if err != nil {
+			return nil, err
+		}
+		for _, device := range page.Data {
+			state := DeviceState{Status: device.Status()}
+			if device.Attributes != nil {
+				state.ReleasedFromOrgDateTime = device.Attributes.ReleasedFromOrgDateTime
+			}
+			snapshot[device.ID] = state
+		}
+	}
+
+	serverIDs, err := c.listMDMServerIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, serverID := range serverIDs {
+		deviceIDs, err := c.mdmServerDeviceIDs(ctx, serverID)
+		if err != nil {
+			return nil, fmt.Errorf("mdm server %q devices: %w", serverID, err)
+		}
+		for id := range deviceIDs {
+			// Linkages of devices missing from the device list are ignored.
+			if state, ok := snapshot[id]; ok {
+				state.AssignedServerID = serverID
+				snapshot[id] = state
+			}
+		}
+	}
+
+	return snapshot, nil
+}
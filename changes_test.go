@@ -0,0 +1,400 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiffDeviceSnapshots(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	released := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	assigned := DeviceState{Status: StatusAssigned, AssignedServerID: "mdm-1"}
+	unassigned := DeviceState{Status: StatusUnAssigned}
+
+	tests := map[string]struct {
+		before DeviceSnapshot
+		after  DeviceSnapshot
+		want   []ChangeEvent
+	}{
+		"success: both empty": {},
+		"success: no changes": {
+			before: DeviceSnapshot{"a": assigned, "b": unassigned},
+			after:  DeviceSnapshot{"a": assigned, "b": unassigned},
+		},
+		"success: release time in another zone is not a change": {
+			before: DeviceSnapshot{"a": {ReleasedFromOrgDateTime: released}},
+			after:  DeviceSnapshot{"a": {ReleasedFromOrgDateTime: released.In(time.FixedZone("JST", 9*60*60))}},
+		},
+		"success: added device": {
+			before: DeviceSnapshot{"a": assigned},
+			after:  DeviceSnapshot{"a": assigned, "b": unassigned},
+			want: []ChangeEvent{
+				{Kind: DeviceAdded, DeviceID: "b", After: unassigned},
+			},
+		},
+		"success: removed device": {
+			before: DeviceSnapshot{"a": assigned, "b": unassigned},
+			after:  DeviceSnapshot{"b": unassigned},
+			want: []ChangeEvent{
+				{Kind: DeviceRemoved, DeviceID: "a", Before: assigned},
+			},
+		},
+		"success: all devices added to empty snapshot": {
+			before: DeviceSnapshot{},
+			after:  DeviceSnapshot{"b": unassigned, "a": assigned},
+			want: []ChangeEvent{
+				{Kind: DeviceAdded, DeviceID: "a", After: assigned},
+				{Kind: DeviceAdded, DeviceID: "b", After: unassigned},
+			},
+		},
+		"success: status and server changed": {
+			before: DeviceSnapshot{"a": assigned},
+			after:  DeviceSnapshot{"a": unassigned},
+			want: []ChangeEvent{
+				{Kind: DeviceModified, DeviceID: "a", Before: assigned, After: unassigned, Fields: []string{"status", "assignedServer"}},
+			},
+		},
+		"success: moved to another server": {
+			before: DeviceSnapshot{"a": assigned},
+			after:  DeviceSnapshot{"a": {Status: StatusAssigned, AssignedServerID: "mdm-2"}},
+			want: []ChangeEvent{
+				{Kind: DeviceModified, DeviceID: "a", Before: assigned, After: DeviceState{Status: StatusAssigned, AssignedServerID: "mdm-2"}, Fields: []string{"assignedServer"}},
+			},
+		},
+		"success: released": {
+			before: DeviceSnapshot{"a": unassigned},
+			after:  DeviceSnapshot{"a": {Status: StatusUnAssigned, ReleasedFromOrgDateTime: released}},
+			want: []ChangeEvent{
+				{Kind: DeviceModified, DeviceID: "a", Before: unassigned, After: DeviceState{Status: StatusUnAssigned, ReleasedFromOrgDateTime: released}, Fields: []string{"releasedFromOrgDateTime"}},
+			},
+		},
+		"success: mixed changes ordered by device ID": {
+			before: DeviceSnapshot{"c": assigned, "a": assigned, "d": unassigned},
+			after:  DeviceSnapshot{"a": unassigned, "b": assigned, "d": unassigned},
+			want: []ChangeEvent{
+				{Kind: DeviceModified, DeviceID: "a", Before: assigned, After: unassigned, Fields: []string{"status", "assignedServer"}},
+				{Kind: DeviceAdded, DeviceID: "b", After: assigned},
+				{Kind: DeviceRemoved, DeviceID: "c", Before: assigned},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			got := DiffDeviceSnapshots(tt.before, tt.after)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("events mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFileSnapshotStore(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		content  string
+		save     DeviceSnapshot
+		want     DeviceSnapshot
+		wantErr  string
+		noCreate bool
+	}{
+		"success: missing file": {
+			noCreate: true,
+		},
+		"success: round trip": {
+			save: DeviceSnapshot{
+				"a": {Status: StatusAssigned, AssignedServerID: "mdm-1"},
+				"b": {Status: StatusUnAssigned, ReleasedFromOrgDateTime: time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)},
+			},
+			want: DeviceSnapshot{
+				"a": {Status: StatusAssigned, AssignedServerID: "mdm-1"},
+				"b": {Status: StatusUnAssigned, ReleasedFromOrgDateTime: time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		"success: empty snapshot": {
+			save: DeviceSnapshot{},
+			want: DeviceSnapshot{},
+		},
+		"error: corrupt file": {
+			content: `{"a":`,
+			wantErr: "decode snapshot",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			store := &FileSnapshotStore{Path: filepath.Join(t.TempDir(), "snapshot.json")}
+			if tt.content != "" {
+				if err := os.WriteFile(store.Path, []byte(tt.content), 0o600); err != nil {
+					t.Fatalf("write snapshot: %v", err)
+				}
+			}
+			if tt.save != nil {
+				if err := store.SaveSnapshot(ctx, tt.save); err != nil {
+					t.Fatalf("SaveSnapshot returned error: %v", err)
+				}
+			}
+
+			got, err := store.LoadSnapshot(ctx)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadSnapshot error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadSnapshot returned error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("snapshot mismatch (-want +got):\n%s", diff)
+			}
+
+			entries, err := os.ReadDir(filepath.Dir(store.Path))
+			if err != nil {
+				t.Fatalf("read snapshot dir: %v", err)
+			}
+			if tt.noCreate && len(entries) != 0 {
+				t.Fatalf("unexpected files: %v", entries)
+			}
+			if !tt.noCreate && len(entries) != 1 {
+				t.Fatalf("temporary files left behind: %v", entries)
+			}
+		})
+	}
+}
+
+// newChangingFleetServer serves a fleet whose org devices and MDM server
+// assignments come from cycles, advancing to the next cycle each time the
+// device list is requested.
+func newChangingFleetServer(t *testing.T, cycles []map[string]string, assignments []map[string][]string) *httptest.Server {
+	t.Helper()
+
+	var cycle atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := int(cycle.Load())
+		if r.URL.Path == "/v1/orgDevices" {
+			current = int(cycle.Add(1))
+		}
+		current = min(max(current, 1), len(cycles)) - 1
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/orgDevices":
+			if got := r.URL.Query().Get("fields[orgDevices]"); got != "status,releasedFromOrgDateTime" {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprintf(w, `{"error":"unexpected fields: %s"}`, got)
+				return
+			}
+			var data []string
+			for id, status := range cycles[current] {
+				data = append(data, fmt.Sprintf(`{"id":%q,"type":"orgDevices","attributes":{"status":%q}}`, id, status))
+			}
+			fmt.Fprintf(w, `{"data":[%s]}`, strings.Join(data, ","))
+		case r.URL.Path == "/v1/mdmServers":
+			fmt.Fprint(w, `{"data":[{"id":"mdm-1","type":"mdmServers"},{"id":"mdm-2","type":"mdmServers"}]}`)
+		case strings.HasPrefix(r.URL.Path, "/v1/mdmServers/"):
+			serverID := strings.Split(r.URL.Path, "/")[3]
+			var data []string
+			for _, id := range assignments[current][serverID] {
+				data = append(data, fmt.Sprintf(`{"id":%q,"type":"orgDevices"}`, id))
+			}
+			fmt.Fprintf(w, `{"data":[%s]}`, strings.Join(data, ","))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// cancelingStore calls cancel once saves snapshots have been saved.
+type cancelingStore struct {
+	SnapshotStore
+	saves  int
+	cancel context.CancelFunc
+}
+
+func (s *cancelingStore) SaveSnapshot(ctx context.Context, snapshot DeviceSnapshot) error {
+	if err := s.SnapshotStore.SaveSnapshot(ctx, snapshot); err != nil {
+		return err
+	}
+	if s.saves--; s.saves == 0 {
+		s.cancel()
+	}
+
+	return nil
+}
+
+func TestDeviceChangeDetector_Run(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	server := newChangingFleetServer(t,
+		[]map[string]string{
+			{"a": "ASSIGNED", "b": "UNASSIGNED", "c": "ASSIGNED"},
+			{"a": "ASSIGNED", "b": "ASSIGNED", "d": "UNASSIGNED"},
+		},
+		[]map[string][]string{
+			{"mdm-1": {"a", "c"}},
+			{"mdm-1": {"b"}, "mdm-2": {"a"}},
+		},
+	)
+	client := testClientForServer(t, server)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu     sync.Mutex
+		events []ChangeEvent
+	)
+	detector := &DeviceChangeDetector{
+		Store: &cancelingStore{
+			SnapshotStore: &FileSnapshotStore{Path: filepath.Join(t.TempDir(), "snapshot.json")},
+			saves:         2,
+			cancel:        cancel,
+		},
+		Sink: func(ctx context.Context, event ChangeEvent) error {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, event)
+			return nil
+		},
+	}
+
+	err := detector.Run(runCtx, client, time.Millisecond)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run error = %v, want %v", err, context.Canceled)
+	}
+
+	want := []ChangeEvent{
+		{
+			Kind:     DeviceModified,
+			DeviceID: "a",
+			Before:   DeviceState{Status: StatusAssigned, AssignedServerID: "mdm-1"},
+			After:    DeviceState{Status: StatusAssigned, AssignedServerID: "mdm-2"},
+			Fields:   []string{"assignedServer"},
+		},
+		{
+			Kind:     DeviceModified,
+			DeviceID: "b",
+			Before:   DeviceState{Status: StatusUnAssigned},
+			After:    DeviceState{Status: StatusAssigned, AssignedServerID: "mdm-1"},
+			Fields:   []string{"status", "assignedServer"},
+		},
+		{
+			Kind:     DeviceRemoved,
+			DeviceID: "c",
+			Before:   DeviceState{Status: StatusAssigned, AssignedServerID: "mdm-1"},
+		},
+		{
+			Kind:     DeviceAdded,
+			DeviceID: "d",
+			After:    DeviceState{Status: StatusUnAssigned},
+		},
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if diff := cmp.Diff(want, events); diff != "" {
+		t.Fatalf("events mismatch (-want +got):\n%s", diff)
+	}
+
+	stored, err := detector.LoadSnapshot(ctx)
+	if err != nil {
+		t.Fatalf("LoadSnapshot returned error: %v", err)
+	}
+	wantSnapshot := DeviceSnapshot{
+		"a": {Status: StatusAssigned, AssignedServerID: "mdm-2"},
+		"b": {Status: StatusAssigned, AssignedServerID: "mdm-1"},
+		"d": {Status: StatusUnAssigned},
+	}
+	if diff := cmp.Diff(wantSnapshot, stored); diff != "" {
+		t.Fatalf("stored snapshot mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDeviceChangeDetector_PollSinkError(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	server := newChangingFleetServer(t,
+		[]map[string]string{{"a": "UNASSIGNED"}, {"a": "ASSIGNED"}},
+		[]map[string][]string{{}, {"mdm-1": {"a"}}},
+	)
+	client := testClientForServer(t, server)
+
+	errSink := errors.New("sink unavailable")
+	baseline := DeviceSnapshot{"a": {Status: StatusUnAssigned}}
+	detector := &DeviceChangeDetector{
+		Store: &FileSnapshotStore{Path: filepath.Join(t.TempDir(), "snapshot.json")},
+		Sink: func(ctx context.Context, event ChangeEvent) error {
+			return errSink
+		},
+	}
+
+	if err := detector.Poll(ctx, client); err != nil {
+		t.Fatalf("baseline Poll returned error: %v", err)
+	}
+	if err := detector.Poll(ctx, client); !errors.Is(err, errSink) {
+		t.Fatalf("Poll error = %v, want %v", err, errSink)
+	}
+
+	stored, err := detector.LoadSnapshot(ctx)
+	if err != nil {
+		t.Fatalf("LoadSnapshot returned error: %v", err)
+	}
+	if diff := cmp.Diff(baseline, stored); diff != "" {
+		t.Fatalf("stored snapshot mismatch (-want +got):\n%s", diff)
+	}
+}
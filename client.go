@@ -19,15 +19,21 @@ package abm
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"net/url"
+	"reflect"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
 	"golang.org/x/oauth2"
 )
 
@@ -47,9 +53,256 @@ const (
 // Client represents an Apple Business Manager (ABM) API client.
 // The embedded HTTP client is already wrapped with an OAuth2 transport and
 // must not be shared with other callers after construction.
+//
+// A Client is safe for concurrent use by multiple goroutines: all of its
+// methods may be called concurrently. Its fields are set by the constructor
+// and never modified afterwards; any state shared between requests must be
+// synchronized.
 type Client struct {
-	baseURL    *url.URL
-	httpClient *http.Client // authorized via oauth2.Transport
+	// The fields below are immutable after construction.
+	baseURL           *url.URL
+	serviceFamily     ServiceFamily
+	httpClient        *http.Client // authorized via oauth2.Transport
+	requestTimeout    time.Duration
+	retryPolicy       RetryPolicy
+	sanitizeStrings   bool
+	sanitizeHook      SanitizeHook
+	expectContinue    bool
+	crawlRetryBudget  int
+	errorBodyMaxBytes int
+	pathRewriter      func(string) string
+	snapshotCache     *snapshotCache
+	crawlDepthHook    CrawlDepthHook
+	crawlPageSize     int
+
+	allowNextLinkVersionMismatch bool
+
+	responseTee       ResponseTee
+	responseTeeErrors bool
+
+	// tokenSource is the source the client authorizes requests with.
+	tokenSource oauth2.TokenSource
+
+	// tokenFamily wraps tokenSource and records the service family of the
+	// scope granted with the last token, for error hints.
+	tokenFamily *tokenFamilySource
+
+	// scopeVerifier is nil unless the client verifies the token's scope
+	// before its first request.
+	scopeVerifier *scopeVerifier
+
+	stableOrdering bool
+
+	userAgent string
+}
+
+// ClientOption configures a [Client].
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	retryPolicy     RetryPolicy
+	recordDir       string
+	acceptLanguage  string
+	sanitizeStrings bool
+	sanitizeHook    SanitizeHook
+
+	// maxIdleConns, maxIdleConnsPerHost, and maxConnsPerHost are nil when
+	// not configured.
+	maxIdleConns        *int
+	maxIdleConnsPerHost *int
+	maxConnsPerHost     *int
+
+	// expectContinueTimeout is zero when Expect: 100-continue is disabled.
+	expectContinueTimeout time.Duration
+
+	crawlRetryBudget int
+	crawlDepthHook   CrawlDepthHook
+
+	// crawlPageSize is nil when not configured.
+	crawlPageSize *int
+
+	allowNextLinkVersionMismatch bool
+
+	errorBodyMaxBytes int
+
+	pathRewriter func(string) string
+
+	// snapshotCacheDir is empty when the snapshot cache is disabled.
+	snapshotCacheDir      string
+	snapshotCacheMaxAge   time.Duration
+	snapshotCacheIdentity string
+
+	responseTee       ResponseTee
+	responseTeeErrors bool
+
+	verifyScope bool
+
+	stableOrdering bool
+
+	// userAgent is empty for the default User-Agent.
+	userAgent       string
+	userAgentSuffix string
+}
+
+// WithRetryPolicy enables retries of transient GET failures, see [RetryPolicy].
+// Retries are disabled by default.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(o *clientOptions) {
+		o.retryPolicy = policy
+	}
+}
+
+// WithAcceptLanguage sets the Accept-Language header sent with every request,
+// for example "ja-JP" or "en-US, en;q=0.9", so that localized strings such as
+// AppleCare coverage descriptions and error details come back in that locale.
+// By default, and when lang is empty, no header is sent and the server default
+// applies.
+func WithAcceptLanguage(lang string) ClientOption {
+	return func(o *clientOptions) {
+		o.acceptLanguage = lang
+	}
+}
+
+// WithMaxIdleConns sets [http.Transport.MaxIdleConns], the maximum number of
+// idle keep-alive connections across all hosts. Zero means no limit.
+//
+// The option applies to a copy of the HTTP client's transport, which must be an
+// [*http.Transport] or nil; the caller's transport is not modified.
+func WithMaxIdleConns(n int) ClientOption {
+	return func(o *clientOptions) {
+		o.maxIdleConns = &n
+	}
+}
+
+// WithMaxIdleConnsPerHost sets [http.Transport.MaxIdleConnsPerHost], the
+// maximum number of idle keep-alive connections kept to the API host. Bulk
+// pagination workloads benefit from a value above the default of
+// [http.DefaultMaxIdleConnsPerHost]. Zero means the default.
+//
+// Like [WithMaxIdleConns], it applies to a copy of the HTTP client's transport.
+func WithMaxIdleConnsPerHost(n int) ClientOption {
+	return func(o *clientOptions) {
+		o.maxIdleConnsPerHost = &n
+	}
+}
+
+// WithConnectionPool sizes the connection pool for crawling many pages from
+// the API host in one go. It sets [http.Transport.MaxIdleConns] to maxIdle,
+// [http.Transport.MaxIdleConnsPerHost] to maxIdlePerHost, and
+// [http.Transport.MaxConnsPerHost] to maxConnsPerHost. Zero means what it
+// means on [http.Transport]: no limit, except for maxIdlePerHost, where it
+// means [http.DefaultMaxIdleConnsPerHost].
+//
+// Like [WithMaxIdleConns], it applies to a copy of the HTTP client's transport.
+func WithConnectionPool(maxIdle, maxIdlePerHost, maxConnsPerHost int) ClientOption {
+	return func(o *clientOptions) {
+		o.maxIdleConns = &maxIdle
+		o.maxIdleConnsPerHost = &maxIdlePerHost
+		o.maxConnsPerHost = &maxConnsPerHost
+	}
+}
+
+// defaultExpectContinueTimeout is the time to wait for a 100 Continue
+// response when [WithExpectContinue] is given a non-positive timeout.
+const defaultExpectContinueTimeout = time.Second
+
+// WithExpectContinue sends request bodies, such as large batched activity
+// creations, with an "Expect: 100-continue" header, letting the server reject
+// a request from its headers before the body is transmitted. timeout is how
+// long to wait for the server's 100 Continue response before sending the body
+// anyway; zero or negative means one second. It is disabled by default.
+//
+// Like [WithMaxIdleConns], it applies to a copy of the HTTP client's
+// transport, setting [http.Transport.ExpectContinueTimeout].
+func WithExpectContinue(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		if timeout <= 0 {
+			timeout = defaultExpectContinueTimeout
+		}
+		o.expectContinueTimeout = timeout
+	}
+}
+
+// defaultErrorBodyMaxBytes is how much of an error response body an
+// [APIError] retains unless [WithErrorBodyMaxBytes] says otherwise.
+const defaultErrorBodyMaxBytes = 4 << 10
+
+// WithErrorBodyMaxBytes caps how many bytes of an error response body are kept
+// in [APIError.Body]; longer bodies are truncated and marked with
+// [APIError.BodyTruncated]. The structured [APIError.Response] is still decoded
+// from the full body. Zero or negative means 4 KiB, the default.
+func WithErrorBodyMaxBytes(n int) ClientOption {
+	return func(o *clientOptions) {
+		o.errorBodyMaxBytes = n
+	}
+}
+
+// WithPathRewriter rewrites the path of every request the client builds, such
+// as "v1/orgDevices", before it is joined onto the base URL. It lets the client
+// talk to a gateway that exposes the API under a different path scheme. Next
+// links returned by the server are followed as is and are not rewritten. A nil
+// rewriter, the default, leaves paths unchanged.
+func WithPathRewriter(rewrite func(path string) string) ClientOption {
+	return func(o *clientOptions) {
+		o.pathRewriter = rewrite
+	}
+}
+
+// configureTransport returns a copy of base with the connection pool and
+// Expect: 100-continue options applied, or base itself when none are set.
+func configureTransport(base http.RoundTripper, options clientOptions) (http.RoundTripper, error) {
+	if options.maxIdleConns == nil && options.maxIdleConnsPerHost == nil && options.maxConnsPerHost == nil && options.expectContinueTimeout == 0 {
+		return base, nil
+	}
+
+	transport, ok := base.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("transport options require an *http.Transport, got %T", base)
+	}
+	transport = transport.Clone()
+
+	if n := options.maxIdleConns; n != nil {
+		if *n < 0 {
+			return nil, fmt.Errorf("max idle connections must not be negative: %d", *n)
+		}
+		transport.MaxIdleConns = *n
+	}
+	if n := options.maxIdleConnsPerHost; n != nil {
+		if *n < 0 {
+			return nil, fmt.Errorf("max idle connections per host must not be negative: %d", *n)
+		}
+		transport.MaxIdleConnsPerHost = *n
+	}
+	if n := options.maxConnsPerHost; n != nil {
+		if *n < 0 {
+			return nil, fmt.Errorf("max connections per host must not be negative: %d", *n)
+		}
+		transport.MaxConnsPerHost = *n
+	}
+	if options.expectContinueTimeout > 0 {
+		transport.ExpectContinueTimeout = options.expectContinueTimeout
+	}
+
+	return transport, nil
+}
+
+// acceptLanguageTransport sets the Accept-Language header on each outgoing request.
+type acceptLanguageTransport struct {
+	base http.RoundTripper
+	lang string
+}
+
+// RoundTrip implements [http.RoundTripper]. A request that already carries an
+// Accept-Language header, such as one set with [WithHeader], is sent unchanged.
+func (t *acceptLanguageTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Accept-Language") != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	// A RoundTripper must not modify the caller's request.
+	req = req.Clone(req.Context())
+	req.Header.Set("Accept-Language", t.lang)
+	return t.base.RoundTrip(req)
 }
 
 // APIError contains API-level error details returned from Apple Business Manager.
@@ -58,9 +311,40 @@ type APIError struct {
 	Status     string
 	Response   ErrorResponse
 	Body       string
+
+	// Hint explains a likely cause of the error, such as using Business API
+	// credentials against the Apple School Manager host. It may be empty.
+	Hint string
+
+	// ExpectedStatusCodes lists the status codes the request would have accepted.
+	ExpectedStatusCodes []int
+
+	// BodyTruncated reports whether Body was cut short, see [WithErrorBodyMaxBytes].
+	BodyTruncated bool
+
+	// RequestID is the request ID from the response headers, which Apple
+	// support asks for when investigating a failure. It may be empty.
+	RequestID string
 }
 
 func (e *APIError) Error() string {
+	msg := e.message()
+	if e.RequestID != "" {
+		msg += " (request id: " + e.RequestID + ")"
+	}
+	if e.Hint != "" {
+		msg += " (hint: " + e.Hint + ")"
+	}
+
+	return msg
+}
+
+func (e *APIError) message() string {
+	// A successful-looking response is rejected only because its status was
+	// not the expected one, so its body is no help.
+	if e.StatusCode >= 200 && e.StatusCode < 300 && len(e.ExpectedStatusCodes) > 0 {
+		return fmt.Sprintf("abm api error: got status %d, expected %v", e.StatusCode, e.ExpectedStatusCodes)
+	}
 	if len(e.Response.Errors) > 0 {
 		errItem := e.Response.Errors[0]
 		if errItem.Code != "" || errItem.Detail != "" {
@@ -72,304 +356,444 @@ func (e *APIError) Error() string {
 		return fmt.Sprintf("abm api error: status=%d", e.StatusCode)
 	}
 
+	if e.BodyTruncated {
+		return fmt.Sprintf("abm api error: status=%d body=%q (truncated)", e.StatusCode, e.Body)
+	}
+
 	return fmt.Sprintf("abm api error: status=%d body=%q", e.StatusCode, e.Body)
 }
 
-// GetOrgDevicesOptions contains optional query parameters for GetOrgDevices.
+// GetOrgDevicesOptions contains optional query parameters for [OrgDevicesService.List].
 type GetOrgDevicesOptions struct {
 	Fields []string
 	Limit  int
+
+	// BluetoothMAC filters devices by Bluetooth MAC address. Colon-, hyphen-,
+	// and dot-separated as well as bare hexadecimal forms are accepted and
+	// normalized to upper-case colon-separated form.
+	BluetoothMAC string
+
+	// EthernetMAC filters devices by Ethernet MAC address, normalized like BluetoothMAC.
+	EthernetMAC string
+
+	// Color filters devices by color, such as "BLACK". Surrounding whitespace is trimmed.
+	Color string
+
+	// ProductType filters devices by Apple's model identifier, such as
+	// "iPhone16,2". Surrounding whitespace is trimmed.
+	ProductType string
+
+	// PurchaseSourceID filters devices by the reseller or Apple order they
+	// were purchased through. Surrounding whitespace is trimmed.
+	PurchaseSourceID string
 }
 
-// GetOrgDeviceOptions contains optional query parameters for GetOrgDevice.
+// GetOrgDeviceOptions contains optional query parameters for [OrgDevicesService.Get].
 type GetOrgDeviceOptions struct {
 	Fields []string
 }
 
-// GetOrgDeviceAppleCareCoverageOptions contains optional query parameters for GetOrgDeviceAppleCareCoverage.
+// GetOrgDeviceAppleCareCoverageOptions contains optional query parameters for [OrgDevicesService.AppleCareCoverage].
 type GetOrgDeviceAppleCareCoverageOptions struct {
 	Fields []string
 	Limit  int
 }
 
-// GetMDMServersOptions contains optional query parameters for [Client.GetMDMServers].
+// GetMDMServersOptions contains optional query parameters for [MDMServersService.List].
 type GetMDMServersOptions struct {
 	Fields []string
 	Limit  int
+
+	// CreatedAfter filters servers created at or after the time. Nil means
+	// no lower bound.
+	CreatedAfter *time.Time
+
+	// CreatedBefore filters servers created at or before the time. Nil means
+	// no upper bound.
+	CreatedBefore *time.Time
+
+	// UpdatedAfter filters servers last updated at or after the time. Nil
+	// means no lower bound.
+	UpdatedAfter *time.Time
+
+	// UpdatedBefore filters servers last updated at or before the time. Nil
+	// means no upper bound.
+	UpdatedBefore *time.Time
 }
 
-// GetMDMServerDeviceLinkagesOptions contains optional query parameters for [Client.GetMDMServerDeviceLinkages].
+// GetMDMServerDeviceLinkagesOptions contains optional query parameters for [MDMServersService.DeviceLinkages].
+//
+// Cursor and Sort cannot be combined: a cursor continues the crawl in the
+// order of the request that returned it, and the API rejects a sort next to
+// it, so the combination is reported as an [*OptionConflictError] before any
+// request is sent. Limit may accompany either, but with Cursor it should
+// equal the limit of the request that returned the cursor, which cannot be
+// checked locally.
 type GetMDMServerDeviceLinkagesOptions struct {
 	Limit int
+
+	// Sort orders the linkages by a field, such as "id"; prefix the field
+	// with "-" for descending order. Empty leaves the order to the server.
+	Sort string
+
+	// Cursor resumes paging from a meta.paging.nextCursor of an earlier
+	// page. Empty starts from the first page.
+	Cursor string
 }
 
-// GetOrgDeviceAssignedServerOptions contains optional query parameters for [Client.GetOrgDeviceAssignedServer].
+// GetOrgDeviceAssignedServerOptions contains optional query parameters for [OrgDevicesService.AssignedServer].
 type GetOrgDeviceAssignedServerOptions struct {
 	Fields []string
 }
 
-// GetOrgDeviceActivityOptions contains optional query parameters for [Client.GetOrgDeviceActivity].
+// GetOrgDeviceActivityOptions contains optional query parameters for [ActivitiesService.Get].
 type GetOrgDeviceActivityOptions struct {
 	Fields []string
+
+	// Include lists relationships whose resources are embedded in the
+	// response's Included field. Valid values are "devices" and "mdmServer".
+	Include []string
 }
 
+// orgDeviceActivityIncludes is the set of valid include values for org-device activities.
+var orgDeviceActivityIncludes = []string{"devices", "mdmServer"}
+
 // NewClient returns an authenticated ABM client using the default API base URL.
-func NewClient(httpClient *http.Client, tokenSource oauth2.TokenSource) (*Client, error) {
-	return NewClientWithBaseURL(httpClient, tokenSource, DefaultAPIBaseURL)
+func NewClient(httpClient *http.Client, tokenSource oauth2.TokenSource, opts ...ClientOption) (*Client, error) {
+	return NewClientWithBaseURL(httpClient, tokenSource, DefaultAPIBaseURL, opts...)
 }
 
 // NewClientWithBaseURL returns an authenticated ABM client using the provided API base URL.
-func NewClientWithBaseURL(httpClient *http.Client, tokenSource oauth2.TokenSource, baseURL string) (*Client, error) {
+func NewClientWithBaseURL(httpClient *http.Client, tokenSource oauth2.TokenSource, baseURL string, opts ...ClientOption) (*Client, error) {
 	if tokenSource == nil {
-		return nil, fmt.Errorf("token source is required")
+		return nil, errNilTokenSource()
 	}
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
 
-	resolvedBaseURL, err := parseBaseURL(baseURL)
+	resolvedBaseURL, err := ParseBaseURL(baseURL)
 	if err != nil {
 		return nil, err
 	}
 
+	var options clientOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	if options.retryPolicy.MaxRetries < 0 {
+		return nil, fmt.Errorf("retry policy max retries must not be negative: %d", options.retryPolicy.MaxRetries)
+	}
+	if options.crawlRetryBudget < 0 {
+		return nil, fmt.Errorf("crawl retry budget must not be negative: %d", options.crawlRetryBudget)
+	}
+	crawlPageSize := defaultCrawlPageSize
+	if options.crawlPageSize != nil {
+		crawlPageSize = *options.crawlPageSize
+		if crawlPageSize < 0 || crawlPageSize > maxPageLimit {
+			return nil, fmt.Errorf("crawl page size: %w", errInvalidLimit(crawlPageSize))
+		}
+	}
+	var cache *snapshotCache
+	if options.snapshotCacheDir != "" {
+		if options.snapshotCacheMaxAge <= 0 {
+			return nil, fmt.Errorf("snapshot cache max age must be positive: %s", options.snapshotCacheMaxAge)
+		}
+		if strings.TrimSpace(options.snapshotCacheIdentity) == "" {
+			return nil, errors.New("snapshot cache identity is required")
+		}
+		cache = &snapshotCache{
+			dir:      options.snapshotCacheDir,
+			maxAge:   options.snapshotCacheMaxAge,
+			identity: options.snapshotCacheIdentity,
+			now:      time.Now,
+		}
+	}
+
 	baseTransport := httpClient.Transport
 	if baseTransport == nil {
 		baseTransport = http.DefaultTransport
 	}
 
+	baseTransport, err = configureTransport(baseTransport, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.recordDir != "" {
+		baseTransport, err = newRecordingTransport(baseTransport, options.recordDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if options.acceptLanguage != "" {
+		baseTransport = &acceptLanguageTransport{base: baseTransport, lang: options.acceptLanguage}
+	}
+
+	var verifier *scopeVerifier
+	if options.verifyScope {
+		// Share the token checked for its scope with the requests.
+		tokenSource = oauth2.ReuseTokenSource(nil, tokenSource)
+		verifier = &scopeVerifier{}
+	}
+	tokenFamily := &tokenFamilySource{src: tokenSource}
+	tokenSource = tokenFamily
+
+	// On top of oauth2.Transport, http.Client.Timeout is implemented with the
+	// deprecated Transport.CancelRequest, so the timeout is applied through
+	// the request context instead, see [Client.requestContext].
 	authorizedClient := *httpClient
+	authorizedClient.Timeout = 0
 	authorizedClient.Transport = &oauth2.Transport{
 		Base:   baseTransport,
 		Source: tokenSource,
 	}
 
 	return &Client{
-		baseURL:    resolvedBaseURL,
-		httpClient: &authorizedClient,
+		baseURL:           resolvedBaseURL,
+		serviceFamily:     ServiceFamilyFromURL(resolvedBaseURL.String()),
+		httpClient:        &authorizedClient,
+		requestTimeout:    httpClient.Timeout,
+		retryPolicy:       options.retryPolicy,
+		sanitizeStrings:   options.sanitizeStrings,
+		sanitizeHook:      options.sanitizeHook,
+		expectContinue:    options.expectContinueTimeout > 0,
+		crawlRetryBudget:  options.crawlRetryBudget,
+		errorBodyMaxBytes: options.errorBodyMaxBytes,
+		pathRewriter:      options.pathRewriter,
+		snapshotCache:     cache,
+		crawlDepthHook:    options.crawlDepthHook,
+		crawlPageSize:     crawlPageSize,
+
+		allowNextLinkVersionMismatch: options.allowNextLinkVersionMismatch,
+
+		responseTee:       options.responseTee,
+		responseTeeErrors: options.responseTeeErrors,
+
+		tokenSource:   tokenSource,
+		tokenFamily:   tokenFamily,
+		scopeVerifier: verifier,
+
+		stableOrdering: options.stableOrdering,
+
+		userAgent: options.resolvedUserAgent(),
 	}, nil
 }
 
-// GetOrgDevices gets a list of organization devices.
-func (c *Client) GetOrgDevices(ctx context.Context, options *GetOrgDevicesOptions) (*OrgDevicesResponse, error) {
-	var fields []string
-	var limit int
-	if options != nil {
-		fields = options.Fields
-		limit = options.Limit
-	}
-
-	query, err := buildFieldsAndLimitQuery("fields[orgDevices]", fields, limit)
-	if err != nil {
-		return nil, err
-	}
-
-	var response OrgDevicesResponse
-	if err := c.doJSONRequest(ctx, http.MethodGet, orgDevicesPath, query, nil, &response, http.StatusOK); err != nil {
+func buildFieldsAndLimitQuery(fieldKey string, fields []string, limit int) (url.Values, error) {
+	query := url.Values{}
+	setFieldsQuery(query, fieldKey, fields)
+	if err := setLimitQuery(query, limit); err != nil {
 		return nil, err
 	}
 
-	return &response, nil
+	return query, nil
 }
 
-// GetOrgDevice gets information for a single organization device.
-func (c *Client) GetOrgDevice(ctx context.Context, orgDeviceID string, options *GetOrgDeviceOptions) (*OrgDeviceResponse, error) {
-	escapedID, err := validateAndEscapeID("org device ID", orgDeviceID)
-	if err != nil {
-		return nil, err
+func setFieldsQuery(query url.Values, key string, fields []string) {
+	if len(fields) == 0 {
+		return
 	}
 
-	query := url.Values{}
-	if options != nil {
-		setFieldsQuery(query, "fields[orgDevices]", options.Fields)
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		trimmed := strings.TrimSpace(field)
+		if trimmed == "" {
+			continue
+		}
+		parts = append(parts, trimmed)
 	}
-
-	var response OrgDeviceResponse
-	if err := c.doJSONRequest(ctx, http.MethodGet, joinPath(orgDevicesPath, escapedID), query, nil, &response, http.StatusOK); err != nil {
-		return nil, err
+	if len(parts) == 0 {
+		return
 	}
 
-	return &response, nil
+	query.Set(key, strings.Join(parts, ","))
 }
 
-// GetOrgDeviceAppleCareCoverage gets AppleCare coverage information for a single organization device.
-func (c *Client) GetOrgDeviceAppleCareCoverage(ctx context.Context, orgDeviceID string, options *GetOrgDeviceAppleCareCoverageOptions) (*AppleCareCoverageResponse, error) {
-	escapedID, err := validateAndEscapeID("org device ID", orgDeviceID)
-	if err != nil {
-		return nil, err
-	}
-
-	var fields []string
-	var limit int
-	if options != nil {
-		fields = options.Fields
-		limit = options.Limit
-	}
-
-	query, err := buildFieldsAndLimitQuery("fields[appleCareCoverage]", fields, limit)
-	if err != nil {
-		return nil, err
+// setSortQuery sets the sort query parameter to a field name made of ASCII
+// letters and digits, optionally prefixed with "-". An empty sort is omitted.
+func setSortQuery(query url.Values, sort string) error {
+	if sort == "" {
+		return nil
 	}
 
-	var response AppleCareCoverageResponse
-	path := joinPath(orgDevicesPath, escapedID, "appleCareCoverage")
-	if err := c.doJSONRequest(ctx, http.MethodGet, path, query, nil, &response, http.StatusOK); err != nil {
-		return nil, err
+	field := strings.TrimPrefix(sort, "-")
+	if field == "" || strings.ContainsFunc(field, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+	}) {
+		return fmt.Errorf("invalid sort %q: must be an alphanumeric field name optionally prefixed with \"-\"", sort)
 	}
+	query.Set("sort", sort)
 
-	return &response, nil
+	return nil
 }
 
-// GetMDMServers gets a list of device management services.
-func (c *Client) GetMDMServers(ctx context.Context, options *GetMDMServersOptions) (*MDMServersResponse, error) {
-	var fields []string
-	var limit int
-	if options != nil {
-		fields = options.Fields
-		limit = options.Limit
-	}
-
-	query, err := buildFieldsAndLimitQuery("fields[mdmServers]", fields, limit)
-	if err != nil {
-		return nil, err
+func setIncludeQuery(query url.Values, allowed, include []string) error {
+	parts := make([]string, 0, len(include))
+	for _, name := range include {
+		trimmed := strings.TrimSpace(name)
+		if trimmed == "" {
+			continue
+		}
+		if !slices.Contains(allowed, trimmed) {
+			return fmt.Errorf("unsupported include %q: must be one of %q", trimmed, allowed)
+		}
+		if !slices.Contains(parts, trimmed) {
+			parts = append(parts, trimmed)
+		}
 	}
-
-	var response MDMServersResponse
-	if err := c.doJSONRequest(ctx, http.MethodGet, mdmServersPath, query, nil, &response, http.StatusOK); err != nil {
-		return nil, err
+	if len(parts) == 0 {
+		return nil
 	}
 
-	return &response, nil
+	query.Set("include", strings.Join(parts, ","))
+	return nil
 }
 
-// GetMDMServerDeviceLinkages gets all org-device serial IDs linked to a device management service.
-func (c *Client) GetMDMServerDeviceLinkages(ctx context.Context, mdmServerID string, options *GetMDMServerDeviceLinkagesOptions) (*MDMServerDevicesLinkagesResponse, error) {
-	escapedID, err := validateAndEscapeID("mdm server ID", mdmServerID)
-	if err != nil {
-		return nil, err
+// setOrgDevicesFilterQuery sets the filter query parameters of options.
+func setOrgDevicesFilterQuery(query url.Values, options *GetOrgDevicesOptions) error {
+	if options == nil {
+		return nil
 	}
 
-	query := url.Values{}
-	if options != nil {
-		if err := setLimitQuery(query, options.Limit); err != nil {
-			return nil, err
+	if options.BluetoothMAC != "" {
+		mac, err := normalizeMACAddress(options.BluetoothMAC)
+		if err != nil {
+			return fmt.Errorf("bluetooth MAC filter: %w", err)
 		}
+		query.Set("filter[bluetoothMacAddress]", mac)
 	}
-
-	var response MDMServerDevicesLinkagesResponse
-	path := joinPath(mdmServersPath, escapedID, "relationships", "devices")
-	if err := c.doJSONRequest(ctx, http.MethodGet, path, query, nil, &response, http.StatusOK); err != nil {
-		return nil, err
+	if options.EthernetMAC != "" {
+		mac, err := normalizeMACAddress(options.EthernetMAC)
+		if err != nil {
+			return fmt.Errorf("ethernet MAC filter: %w", err)
+		}
+		query.Set("filter[ethernetMacAddress]", mac)
+	}
+	if color := strings.TrimSpace(options.Color); color != "" {
+		query.Set("filter[color]", color)
+	}
+	if productType := strings.TrimSpace(options.ProductType); productType != "" {
+		query.Set("filter[productType]", productType)
+	}
+	if purchaseSourceID := strings.TrimSpace(options.PurchaseSourceID); purchaseSourceID != "" {
+		query.Set("filter[purchaseSourceId]", purchaseSourceID)
 	}
 
-	return &response, nil
+	return nil
 }
 
-// GetOrgDeviceAssignedServerLinkage gets assigned device-management service ID linkage for a device.
-func (c *Client) GetOrgDeviceAssignedServerLinkage(ctx context.Context, orgDeviceID string) (*OrgDeviceAssignedServerLinkageResponse, error) {
-	escapedID, err := validateAndEscapeID("org device ID", orgDeviceID)
-	if err != nil {
-		return nil, err
+// setMDMServersFilterQuery sets the filter query parameters of options.
+func setMDMServersFilterQuery(query url.Values, options *GetMDMServersOptions) {
+	if options == nil {
+		return
 	}
 
-	var response OrgDeviceAssignedServerLinkageResponse
-	path := joinPath(orgDevicesPath, escapedID, "relationships", "assignedServer")
-	if err := c.doJSONRequest(ctx, http.MethodGet, path, nil, nil, &response, http.StatusOK); err != nil {
-		return nil, err
+	if options.CreatedAfter != nil {
+		query.Set("filter[createdDateTime][gte]", formatTimeFilter(*options.CreatedAfter))
 	}
+	if options.CreatedBefore != nil {
+		query.Set("filter[createdDateTime][lte]", formatTimeFilter(*options.CreatedBefore))
+	}
+	if options.UpdatedAfter != nil {
+		query.Set("filter[updatedDateTime][gte]", formatTimeFilter(*options.UpdatedAfter))
+	}
+	if options.UpdatedBefore != nil {
+		query.Set("filter[updatedDateTime][lte]", formatTimeFilter(*options.UpdatedBefore))
+	}
+}
 
-	return &response, nil
+// formatTimeFilter formats t for a date-time filter, in RFC 3339 form in UTC.
+func formatTimeFilter(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
 }
 
-// GetOrgDeviceAssignedServer gets assigned device-management service information for a device.
-func (c *Client) GetOrgDeviceAssignedServer(ctx context.Context, orgDeviceID string, options *GetOrgDeviceAssignedServerOptions) (*MDMServerResponse, error) {
-	escapedID, err := validateAndEscapeID("org device ID", orgDeviceID)
-	if err != nil {
-		return nil, err
-	}
+// normalizeMACAddress returns mac in upper-case colon-separated form, such as
+// "AA:BB:CC:DD:EE:FF". Colon, hyphen, and dot separators are accepted.
+func normalizeMACAddress(mac string) (string, error) {
+	hex := strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '-', '.':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(mac))
 
-	query := url.Values{}
-	if options != nil {
-		setFieldsQuery(query, "fields[mdmServers]", options.Fields)
+	if len(hex) != 12 {
+		return "", fmt.Errorf("invalid MAC address %q", mac)
 	}
-
-	var response MDMServerResponse
-	path := joinPath(orgDevicesPath, escapedID, "assignedServer")
-	if err := c.doJSONRequest(ctx, http.MethodGet, path, query, nil, &response, http.StatusOK); err != nil {
-		return nil, err
+	if _, err := strconv.ParseUint(hex, 16, 64); err != nil {
+		return "", fmt.Errorf("invalid MAC address %q", mac)
 	}
 
-	return &response, nil
-}
-
-// CreateOrgDeviceActivity creates an org-device activity that assigns or unassigns devices.
-func (c *Client) CreateOrgDeviceActivity(ctx context.Context, request OrgDeviceActivityCreateRequest) (*OrgDeviceActivityResponse, error) {
-	var response OrgDeviceActivityResponse
-	if err := c.doJSONRequest(ctx, http.MethodPost, orgDeviceActivitiesURL, nil, request, &response, http.StatusCreated); err != nil {
-		return nil, err
+	hex = strings.ToUpper(hex)
+	var b strings.Builder
+	b.Grow(17)
+	for i := 0; i < len(hex); i += 2 {
+		if i > 0 {
+			b.WriteByte(':')
+		}
+		b.WriteString(hex[i : i+2])
 	}
 
-	return &response, nil
+	return b.String(), nil
 }
 
-// GetOrgDeviceActivity gets organization device activity information.
-func (c *Client) GetOrgDeviceActivity(ctx context.Context, orgDeviceActivityID string, options *GetOrgDeviceActivityOptions) (*OrgDeviceActivityResponse, error) {
-	escapedID, err := validateAndEscapeID("org device activity ID", orgDeviceActivityID)
-	if err != nil {
-		return nil, err
-	}
+// errMissingID returns the error for an empty or blank ID, where field names
+// the ID, such as "org device ID".
+func errMissingID(field string) error {
+	return fmt.Errorf("%s is required", field)
+}
 
-	query := url.Values{}
-	if options != nil {
-		setFieldsQuery(query, "fields[orgDeviceActivities]", options.Fields)
-	}
+// LimitValidationError is returned for a page limit, such as
+// [GetOrgDevicesOptions.Limit], outside the range the API accepts.
+type LimitValidationError struct {
+	// Value is the rejected limit.
+	Value int
 
-	var response OrgDeviceActivityResponse
-	if err := c.doJSONRequest(ctx, http.MethodGet, joinPath(orgDeviceActivitiesURL, escapedID), query, nil, &response, http.StatusOK); err != nil {
-		return nil, err
-	}
+	// Min and Max are the inclusive bounds of the accepted range.
+	Min int
+	Max int
+}
 
-	return &response, nil
+func (e *LimitValidationError) Error() string {
+	return fmt.Sprintf("limit %d is out of range [%d, %d]", e.Value, e.Min, e.Max)
 }
 
-func buildFieldsAndLimitQuery(fieldKey string, fields []string, limit int) (url.Values, error) {
-	query := url.Values{}
-	setFieldsQuery(query, fieldKey, fields)
-	if err := setLimitQuery(query, limit); err != nil {
-		return nil, err
-	}
+// OptionConflictError is returned for request options that cannot be
+// combined, such as [GetMDMServerDeviceLinkagesOptions.Cursor] and
+// [GetMDMServerDeviceLinkagesOptions.Sort].
+type OptionConflictError struct {
+	// Options names the conflicting options.
+	Options []string
 
-	return query, nil
+	// Reason explains why they conflict.
+	Reason string
 }
 
-func setFieldsQuery(query url.Values, key string, fields []string) {
-	if len(fields) == 0 {
-		return
-	}
+func (e *OptionConflictError) Error() string {
+	return fmt.Sprintf("options %s cannot be combined: %s", strings.Join(e.Options, " and "), e.Reason)
+}
 
-	parts := make([]string, 0, len(fields))
-	for _, field := range fields {
-		trimmed := strings.TrimSpace(field)
-		if trimmed == "" {
-			continue
-		}
-		parts = append(parts, trimmed)
-	}
-	if len(parts) == 0 {
-		return
-	}
+// errInvalidLimit returns the error for a page limit outside [0, maxPageLimit].
+func errInvalidLimit(n int) error {
+	return &LimitValidationError{Value: n, Min: 0, Max: maxPageLimit}
+}
 
-	query.Set(key, strings.Join(parts, ","))
+// errNilTokenSource returns the error for a client constructed without a token source.
+func errNilTokenSource() error {
+	return errors.New("token source is required")
 }
 
 func setLimitQuery(query url.Values, limit int) error {
 	if limit == 0 {
 		return nil
 	}
-	if limit < 0 {
-		return fmt.Errorf("limit must be >= 0: %d", limit)
-	}
-	if limit > maxPageLimit {
-		return fmt.Errorf("limit must be <= %d: %d", maxPageLimit, limit)
+	if limit < 0 || limit > maxPageLimit {
+		return errInvalidLimit(limit)
 	}
 
 	query.Set("limit", strconv.Itoa(limit))
@@ -379,7 +803,12 @@ func setLimitQuery(query url.Values, limit int) error {
 func validateAndEscapeID(name, id string) (string, error) {
 	trimmed := strings.TrimSpace(id)
 	if trimmed == "" {
-		return "", fmt.Errorf("%s is required", name)
+		return "", errMissingID(name)
+	}
+	// Dot segments survive url.PathEscape and would be cleaned away by path
+	// joining, silently addressing a different resource.
+	if trimmed == "." || trimmed == ".." {
+		return "", fmt.Errorf("%s must not be a dot segment: %q", name, trimmed)
 	}
 
 	return url.PathEscape(trimmed), nil
@@ -398,7 +827,11 @@ func joinPath(parts ...string) string {
 	return strings.Join(filtered, "/")
 }
 
-func parseBaseURL(rawBaseURL string) (*url.URL, error) {
+// ParseBaseURL parses rawBaseURL as an API base URL the way [NewClientWithBaseURL]
+// does. An empty rawBaseURL means [DefaultAPIBaseURL]. The URL must be
+// absolute with a host, and the returned URL's path always ends in "/" so
+// request paths resolve below it.
+func ParseBaseURL(rawBaseURL string) (*url.URL, error) {
 	if rawBaseURL == "" {
 		rawBaseURL = DefaultAPIBaseURL
 	}
@@ -415,36 +848,122 @@ func parseBaseURL(rawBaseURL string) (*url.URL, error) {
 	}
 	if !strings.HasSuffix(parsed.Path, "/") {
 		parsed.Path += "/"
+		if parsed.RawPath != "" {
+			parsed.RawPath += "/"
+		}
 	}
 
 	return parsed, nil
 }
 
+// buildURL joins path onto the client base URL and merges query into any
+// query already present on the base URL.
+//
+// The path is first passed through the [WithPathRewriter] rewriter, if any. It
+// is treated as relative to the base URL path even when it has a leading
+// slash, and dot segments are cleaned. The resolved URL must stay
+// within the base URL; otherwise an error is returned.
 func (c *Client) buildURL(path string, query url.Values) (string, error) {
-	base := *c.baseURL // copy to avoid mutations
+	base := c.baseURL
+	if c.pathRewriter != nil {
+		path = c.pathRewriter(path)
+	}
 
-	relative, err := url.Parse(strings.TrimPrefix(path, "/"))
-	if err != nil {
-		return "", fmt.Errorf("parse request path: %w", err)
+	resolved := base.JoinPath(path)
+	resolved.Fragment = ""
+	resolved.RawFragment = ""
+
+	if !withinBaseURL(base, resolved) {
+		return "", fmt.Errorf("request path %q escapes base URL %q", path, base.String())
 	}
 
-	resolved := base.ResolveReference(relative)
-	if len(query) > 0 {
-		resolved.RawQuery = query.Encode()
+	merged := base.Query()
+	for key, values := range query {
+		merged[key] = values
+	}
+	resolved.RawQuery = ""
+	if len(merged) > 0 {
+		resolved.RawQuery = merged.Encode()
 	}
 
 	return resolved.String(), nil
 }
 
+// withinBaseURL reports whether resolved has the same origin as base and a
+// path equal to or below the base path.
+func withinBaseURL(base, resolved *url.URL) bool {
+	if resolved.Scheme != base.Scheme || resolved.Host != base.Host {
+		return false
+	}
+
+	basePath := strings.TrimSuffix(base.EscapedPath(), "/")
+	resolvedPath := resolved.EscapedPath()
+
+	return resolvedPath == basePath || strings.HasPrefix(resolvedPath, basePath+"/")
+}
+
+type acceptedStatusesKey struct{}
+
+// WithAcceptedStatuses returns a copy of ctx that makes client requests sent
+// with it accept codes as successful, in addition to the status codes each
+// client method expects. This suits gateways that, for example, answer a
+// creation with 200 instead of 201, while the API's own 201, or the 304 of
+// [OrgDevicesService.GetConditional], is still accepted. Without codes, ctx is
+// returned unchanged.
+func WithAcceptedStatuses(ctx context.Context, codes ...int) context.Context {
+	if len(codes) == 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, acceptedStatusesKey{}, slices.Clone(codes))
+}
+
+// acceptedStatuses returns defaults followed by the status codes set by
+// [WithAcceptedStatuses] on ctx that are not among them.
+func acceptedStatuses(ctx context.Context, defaults []int) []int {
+	codes, ok := ctx.Value(acceptedStatusesKey{}).([]int)
+	if !ok {
+		return defaults
+	}
+
+	accepted := slices.Clone(defaults)
+	for _, code := range codes {
+		if !slices.Contains(accepted, code) {
+			accepted = append(accepted, code)
+		}
+	}
+
+	return accepted
+}
+
 func statusAllowed(statusCode int, expectedStatusCodes []int) bool {
 	return slices.Contains(expectedStatusCodes, statusCode)
 }
 
-func decodeAPIError(resp *http.Response, payload []byte) error {
+// decodeAPIError builds an [APIError] from an error response, retaining at
+// most maxBody bytes of its body, or the default when maxBody <= 0.
+func decodeAPIError(resp *http.Response, payload []byte, maxBody int) *APIError {
+	if maxBody <= 0 {
+		maxBody = defaultErrorBodyMaxBytes
+	}
+
+	body := bytes.TrimSpace(payload)
+	truncated := len(body) > maxBody
+	if truncated {
+		// Cut at a rune boundary so the retained body stays valid UTF-8.
+		cut := maxBody
+		for cut > 0 && !utf8.RuneStart(body[cut]) {
+			cut--
+		}
+		body = body[:cut]
+	}
+
 	apiErr := &APIError{
-		StatusCode: resp.StatusCode,
-		Status:     resp.Status,
-		Body:       strings.TrimSpace(string(payload)),
+		StatusCode:    resp.StatusCode,
+		Status:        resp.Status,
+		Body:          string(body),
+		BodyTruncated: truncated,
+		RequestID:     responseRequestID(resp),
 	}
 
 	if len(payload) == 0 {
@@ -459,7 +978,10 @@ func decodeAPIError(resp *http.Response, payload []byte) error {
 	return apiErr
 }
 
-func (c *Client) doJSONRequest(ctx context.Context, method, path string, query url.Values, requestBody, responseBody any, expectedStatusCodes ...int) error {
+// doJSONRequest sends a JSON API request with the call options opts and
+// decodes the response into responseBody. GET requests are retried according
+// to the client's [RetryPolicy].
+func (c *Client) doJSONRequest(ctx context.Context, method, path string, query url.Values, opts []CallOption, requestBody, responseBody any, expectedStatusCodes ...int) error {
 	if err := ctx.Err(); err != nil {
 		return err
 	}
@@ -472,7 +994,77 @@ func (c *Client) doJSONRequest(ctx context.Context, method, path string, query u
 		return err
 	}
 
+	return c.doJSONExchange(ctx, method, requestURL, newCallExchange(opts), requestBody, responseBody, expectedStatusCodes...)
+}
+
+// doJSONRequestRaw is like doJSONRequest for a request without a body, and
+// also returns the response body exactly as received.
+func (c *Client) doJSONRequestRaw(ctx context.Context, method, path string, query url.Values, opts []CallOption, responseBody any) (jsontext.Value, error) {
+	requestURL, err := c.buildURL(path, query)
+	if err != nil {
+		return nil, err
+	}
+
+	ex := newCallExchange(opts)
+	if err := c.doJSONExchange(ctx, method, requestURL, ex, nil, responseBody, http.StatusOK); err != nil {
+		return nil, err
+	}
+
+	return jsontext.Value(ex.respBody), nil
+}
+
+// exchange carries extra request headers into a JSON request and reports
+// metadata of the final response out of it.
+type exchange struct {
+	// header is added to the request headers.
+	header http.Header
+
+	// budget, if non-nil, is charged for every retry of the request.
+	budget *retryBudget
+
+	// timeout, if positive, replaces the client's request timeout for each
+	// attempt.
+	timeout time.Duration
+
+	// noRetry disables retries of the request.
+	noRetry bool
+
+	// statusCode, respHeader, and respBody are set from the final response.
+	statusCode int
+	respHeader http.Header
+	respBody   []byte
+}
+
+// requestContext returns ctx limited to the Timeout of the [http.Client] the
+// client was created with, or to timeout when it is positive. The request and
+// the read of its response body must finish before cancel is called.
+func (c *Client) requestContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		timeout = c.requestTimeout
+	}
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// doJSONExchange is like doJSONRequest but sends the request to an already
+// resolved URL, and applies and records ex, which may be nil.
+func (c *Client) doJSONExchange(ctx context.Context, method, requestURL string, ex *exchange, requestBody, responseBody any, expectedStatusCodes ...int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(expectedStatusCodes) == 0 {
+		expectedStatusCodes = []int{http.StatusOK}
+	}
+	expectedStatusCodes = acceptedStatuses(ctx, expectedStatusCodes)
+	if err := c.verifyScopeOnFirstUse(ctx); err != nil {
+		return err
+	}
+
 	var body []byte
+	var err error
 	if requestBody != nil {
 		body, err = json.Marshal(requestBody)
 		if err != nil {
@@ -480,42 +1072,155 @@ func (c *Client) doJSONRequest(ctx context.Context, method, path string, query u
 		}
 	}
 
+	maxRetries := 0
+	if method == http.MethodGet && (ex == nil || !ex.noRetry) {
+		maxRetries = c.retryPolicy.MaxRetries
+	}
+
+	trace := attemptTraceFrom(ctx)
+	decodeRetried := false
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && responseBody != nil {
+			// A failed decode may have partially populated responseBody.
+			reflect.ValueOf(responseBody).Elem().SetZero()
+		}
+
+		var start time.Time
+		if trace != nil {
+			start = time.Now()
+		}
+		result := c.doJSONAttempt(ctx, method, requestURL, ex, body, responseBody, expectedStatusCodes)
+		if trace != nil {
+			trace.record(method, requestURL, start, result.response, result.err, result.retryReason)
+		}
+		if result.err == nil {
+			return nil
+		}
+		if result.retryReason == "" || attempt >= maxRetries || ctx.Err() != nil {
+			return result.err
+		}
+		if result.retryReason == retryReasonDecode {
+			if decodeRetried {
+				return result.err
+			}
+			decodeRetried = true
+		}
+		if ex != nil && ex.budget != nil && !ex.budget.take() {
+			return fmt.Errorf("%w (%d retries): %w", ErrCrawlRetryBudgetExceeded, ex.budget.limit, result.err)
+		}
+
+		delay := jitteredBackoff(attempt, c.retryPolicy.BaseDelay, c.retryPolicy.MaxDelay)
+		if result.retryAfter > 0 {
+			delay = min(result.retryAfter, max(c.retryPolicy.MaxDelay, 0))
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return errors.Join(err, result.err)
+		}
+	}
+}
+
+// attemptResult is the outcome of a single HTTP round trip.
+type attemptResult struct {
+	err error
+
+	// retryReason is non-empty when err is transient and the request may be retried.
+	retryReason string
+
+	// retryAfter is the delay requested by the server's Retry-After header.
+	retryAfter time.Duration
+
+	// response is the response received, whose body is already closed, or
+	// nil when none arrived.
+	response *http.Response
+}
+
+func (c *Client) doJSONAttempt(ctx context.Context, method, requestURL string, ex *exchange, body []byte, responseBody any, expectedStatusCodes []int) attemptResult {
 	requestReader := io.Reader(http.NoBody)
 	if len(body) > 0 {
 		requestReader = bytes.NewReader(body)
 	}
 
+	var timeout time.Duration
+	if ex != nil {
+		timeout = ex.timeout
+	}
+	ctx, cancel := c.requestContext(ctx, timeout)
+	defer cancel()
+
 	req, err := http.NewRequestWithContext(ctx, method, requestURL, requestReader)
 	if err != nil {
-		return fmt.Errorf("build request: %w", err)
+		return attemptResult{err: fmt.Errorf("build request: %w", err)}
 	}
 	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
 	if len(body) > 0 {
 		req.Header.Set("Content-Type", "application/json")
+		if c.expectContinue {
+			req.Header.Set("Expect", "100-continue")
+		}
+	}
+	if ex != nil {
+		maps.Copy(req.Header, ex.header)
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("send request: %w", err)
+		result := attemptResult{err: fmt.Errorf("send request: %w", err)}
+		if !isContextError(err) {
+			result.retryReason = retryReasonTransport
+		}
+		return result
 	}
 	defer resp.Body.Close()
 
+	if ex != nil {
+		ex.statusCode = resp.StatusCode
+		ex.respHeader = resp.Header
+	}
+
 	payload, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("read response body: %w", err)
+		result := attemptResult{response: resp, err: fmt.Errorf("read response body: %w", err)}
+		if !isContextError(err) {
+			result.retryReason = retryReasonTransport
+		}
+		return result
+	}
+	if ex != nil {
+		ex.respBody = payload
 	}
 
-	if !statusAllowed(resp.StatusCode, expectedStatusCodes) {
-		return decodeAPIError(resp, payload)
+	allowed := statusAllowed(resp.StatusCode, expectedStatusCodes)
+	c.teeResponse(c.endpoint(req.URL), allowed, payload)
+	if !allowed {
+		apiErr := decodeAPIError(resp, payload, c.errorBodyMaxBytes)
+		apiErr.Hint = c.serviceFamilyHint(resp.StatusCode)
+		apiErr.ExpectedStatusCodes = expectedStatusCodes
+
+		result := attemptResult{response: resp, err: apiErr}
+		if retryableStatus(resp.StatusCode) {
+			result.retryReason = retryReasonStatus
+			result.retryAfter = retryAfter(resp.Header)
+		}
+		return result
 	}
 
 	if responseBody == nil || len(payload) == 0 {
-		return nil
+		return attemptResult{response: resp}
 	}
 
-	if err := json.Unmarshal(payload, responseBody); err != nil {
-		return fmt.Errorf("decode response body: %w", err)
+	if c.sanitizeStrings {
+		err = c.decodeSanitized(method, req.URL.Path, payload, responseBody)
+	} else {
+		err = json.Unmarshal(payload, responseBody)
+	}
+	if err != nil {
+		return attemptResult{
+			response:    resp,
+			err:         fmt.Errorf("decode response body: %w", err),
+			retryReason: retryReasonDecode,
+		}
 	}
 
-	return nil
+	return attemptResult{response: resp}
 }
@@ -21,17 +21,24 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/go-json-experiment/json"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"golang.org/x/oauth2"
 )
 
-func testClientForServer(t *testing.T, server *httptest.Server) *Client {
+func testClientForServer(t *testing.T, server *httptest.Server, opts ...ClientOption) *Client {
 	t.Helper()
 
 	ctx := t.Context()
@@ -40,7 +47,7 @@ func testClientForServer(t *testing.T, server *httptest.Server) *Client {
 	}
 
 	tokenSource := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token"})
-	client, err := NewClientWithBaseURL(server.Client(), tokenSource, server.URL)
+	client, err := NewClientWithBaseURL(server.Client(), tokenSource, server.URL, opts...)
 	if err != nil {
 		t.Fatalf("NewClientWithBaseURL returned error: %v", err)
 	}
@@ -48,6 +55,13 @@ func testClientForServer(t *testing.T, server *httptest.Server) *Client {
 	return client
 }
 
+// roundTripperFunc adapts a function to [http.RoundTripper].
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
 func TestNewClientWithBaseURL(t *testing.T) {
 	ctx := t.Context()
 	if err := ctx.Err(); err != nil {
@@ -60,6 +74,7 @@ func TestNewClientWithBaseURL(t *testing.T) {
 		httpClient   *http.Client
 		tokenSource  oauth2.TokenSource
 		baseURL      string
+		opts         []ClientOption
 		wantErr      bool
 		wantBaseHost string
 	}{
@@ -86,6 +101,13 @@ func TestNewClientWithBaseURL(t *testing.T) {
 			baseURL:     "://bad-url",
 			wantErr:     true,
 		},
+		"error: negative max retries": {
+			httpClient:  http.DefaultClient,
+			tokenSource: tokenSource,
+			baseURL:     DefaultAPIBaseURL,
+			opts:        []ClientOption{WithRetryPolicy(RetryPolicy{MaxRetries: -1})},
+			wantErr:     true,
+		},
 	}
 
 	for name, tt := range tests {
@@ -95,7 +117,7 @@ func TestNewClientWithBaseURL(t *testing.T) {
 				t.Fatalf("context error: %v", err)
 			}
 
-			client, err := NewClientWithBaseURL(tt.httpClient, tt.tokenSource, tt.baseURL)
+			client, err := NewClientWithBaseURL(tt.httpClient, tt.tokenSource, tt.baseURL, tt.opts...)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("NewClientWithBaseURL error mismatch: err=%v wantErr=%v", err, tt.wantErr)
 			}
@@ -113,6 +135,71 @@ func TestNewClientWithBaseURL(t *testing.T) {
 	}
 }
 
+func TestParseBaseURL(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		rawURL  string
+		want    string
+		wantErr string
+	}{
+		"success: empty url returns default": {
+			rawURL: "",
+			want:   DefaultAPIBaseURL,
+		},
+		"success: custom url gains trailing slash": {
+			rawURL: "https://example.com/abm",
+			want:   "https://example.com/abm/",
+		},
+		"success: custom url with query": {
+			rawURL: "https://example.com/abm/?tenant=1",
+			want:   "https://example.com/abm/?tenant=1",
+		},
+		"success: escaped path keeps raw path": {
+			rawURL: "https://example.com/a%2Fb",
+			want:   "https://example.com/a%2Fb/",
+		},
+		"error: relative url": {
+			rawURL:  "/v1",
+			wantErr: "base URL must be absolute",
+		},
+		"error: url without host": {
+			rawURL:  "https:///v1",
+			wantErr: "base URL host is required",
+		},
+		"error: unparsable url": {
+			rawURL:  "https://example.com/%zz",
+			wantErr: "parse base URL",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			got, err := ParseBaseURL(tt.rawURL)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseBaseURL error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseBaseURL returned error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Fatalf("base URL mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestNewClient(t *testing.T) {
 	ctx := t.Context()
 	if err := ctx.Err(); err != nil {
@@ -195,6 +282,115 @@ func TestClient_ABMOperationsSuccess(t *testing.T) {
 				return nil
 			},
 		},
+		"success: get org devices by bluetooth mac": {
+			method: http.MethodGet,
+			path:   "/v1/orgDevices",
+			query: url.Values{
+				"filter[bluetoothMacAddress]": []string{"66:77:88:99:AA:BB"},
+			},
+			statusCode:   http.StatusOK,
+			responseBody: `{"data":[],"links":{"self":"https://api-business.apple.com/v1/orgDevices"}}`,
+			invoke: func(ctx context.Context, client *Client) error {
+				_, err := client.GetOrgDevices(ctx, &GetOrgDevicesOptions{BluetoothMAC: "66-77-88-99-aa-bb"})
+				return err
+			},
+		},
+		"success: get org devices by ethernet mac": {
+			method: http.MethodGet,
+			path:   "/v1/orgDevices",
+			query: url.Values{
+				"filter[ethernetMacAddress]": []string{"CC:DD:EE:FF:00:11"},
+			},
+			statusCode:   http.StatusOK,
+			responseBody: `{"data":[],"links":{"self":"https://api-business.apple.com/v1/orgDevices"}}`,
+			invoke: func(ctx context.Context, client *Client) error {
+				_, err := client.GetOrgDevices(ctx, &GetOrgDevicesOptions{EthernetMAC: "cc:dd:ee:ff:00:11"})
+				return err
+			},
+		},
+		"success: get org devices with empty ethernet mac": {
+			method:       http.MethodGet,
+			path:         "/v1/orgDevices",
+			query:        url.Values{},
+			statusCode:   http.StatusOK,
+			responseBody: `{"data":[],"links":{"self":"https://api-business.apple.com/v1/orgDevices"}}`,
+			invoke: func(ctx context.Context, client *Client) error {
+				_, err := client.GetOrgDevices(ctx, &GetOrgDevicesOptions{EthernetMAC: ""})
+				return err
+			},
+		},
+		"success: get org devices by color": {
+			method: http.MethodGet,
+			path:   "/v1/orgDevices",
+			query: url.Values{
+				"filter[color]": []string{"SPACE GRAY"},
+			},
+			statusCode:   http.StatusOK,
+			responseBody: `{"data":[],"links":{"self":"https://api-business.apple.com/v1/orgDevices"}}`,
+			invoke: func(ctx context.Context, client *Client) error {
+				_, err := client.GetOrgDevices(ctx, &GetOrgDevicesOptions{Color: "  SPACE GRAY "})
+				return err
+			},
+		},
+		"success: get org devices with blank color": {
+			method:       http.MethodGet,
+			path:         "/v1/orgDevices",
+			query:        url.Values{},
+			statusCode:   http.StatusOK,
+			responseBody: `{"data":[],"links":{"self":"https://api-business.apple.com/v1/orgDevices"}}`,
+			invoke: func(ctx context.Context, client *Client) error {
+				_, err := client.GetOrgDevices(ctx, &GetOrgDevicesOptions{Color: "   "})
+				return err
+			},
+		},
+		"success: get org devices by product type": {
+			method: http.MethodGet,
+			path:   "/v1/orgDevices",
+			query: url.Values{
+				"filter[productType]": []string{"iPhone16,2"},
+			},
+			statusCode:   http.StatusOK,
+			responseBody: `{"data":[],"links":{"self":"https://api-business.apple.com/v1/orgDevices"}}`,
+			invoke: func(ctx context.Context, client *Client) error {
+				_, err := client.GetOrgDevices(ctx, &GetOrgDevicesOptions{ProductType: " iPhone16,2 "})
+				return err
+			},
+		},
+		"success: get org devices with empty product type": {
+			method:       http.MethodGet,
+			path:         "/v1/orgDevices",
+			query:        url.Values{},
+			statusCode:   http.StatusOK,
+			responseBody: `{"data":[],"links":{"self":"https://api-business.apple.com/v1/orgDevices"}}`,
+			invoke: func(ctx context.Context, client *Client) error {
+				_, err := client.GetOrgDevices(ctx, &GetOrgDevicesOptions{ProductType: ""})
+				return err
+			},
+		},
+		"success: get org devices by purchase source ID": {
+			method: http.MethodGet,
+			path:   "/v1/orgDevices",
+			query: url.Values{
+				"filter[purchaseSourceId]": []string{"PS-123"},
+			},
+			statusCode:   http.StatusOK,
+			responseBody: `{"data":[],"links":{"self":"https://api-business.apple.com/v1/orgDevices"}}`,
+			invoke: func(ctx context.Context, client *Client) error {
+				_, err := client.OrgDevices().List(ctx, &GetOrgDevicesOptions{PurchaseSourceID: " PS-123 "})
+				return err
+			},
+		},
+		"success: get org devices with empty purchase source ID": {
+			method:       http.MethodGet,
+			path:         "/v1/orgDevices",
+			query:        url.Values{},
+			statusCode:   http.StatusOK,
+			responseBody: `{"data":[],"links":{"self":"https://api-business.apple.com/v1/orgDevices"}}`,
+			invoke: func(ctx context.Context, client *Client) error {
+				_, err := client.OrgDevices().List(ctx, &GetOrgDevicesOptions{PurchaseSourceID: ""})
+				return err
+			},
+		},
 		"success: get org device": {
 			method:       http.MethodGet,
 			path:         "/v1/orgDevices/device-1",
@@ -347,9 +543,52 @@ func TestClient_ABMOperationsSuccess(t *testing.T) {
 				if err != nil {
 					return err
 				}
-				if diff := cmp.Diff("COMPLETED", resp.Data.Attributes.Status); diff != "" {
+				if diff := cmp.Diff(OrgDeviceActivityStatusCompleted, resp.Data.Attributes.Status); diff != "" {
 					return fmt.Errorf("activity status mismatch (-want +got):\n%s", diff)
 				}
+				if len(resp.Included) != 0 {
+					return fmt.Errorf("unexpected included length: %d", len(resp.Included))
+				}
+				return nil
+			},
+		},
+		"success: get org device activity with includes": {
+			method: http.MethodGet,
+			path:   "/v1/orgDeviceActivities/activity-1",
+			query: url.Values{
+				"fields[orgDeviceActivities]": []string{"status"},
+				"include":                     []string{"devices,mdmServer"},
+			},
+			statusCode:   http.StatusOK,
+			responseBody: `{"data":{"id":"activity-1","type":"orgDeviceActivities","attributes":{"status":"COMPLETED"}},"included":[{"id":"device-1","type":"orgDevices","attributes":{"serialNumber":"SER-1"}},{"id":"mdm-1","type":"mdmServers","attributes":{"serverName":"Primary MDM"}},{"id":"other-1","type":"others"}],"links":{"self":"https://api-business.apple.com/v1/orgDeviceActivities/activity-1"}}`,
+			invoke: func(ctx context.Context, client *Client) error {
+				resp, err := client.GetOrgDeviceActivity(ctx, "activity-1", &GetOrgDeviceActivityOptions{
+					Fields:  []string{"status"},
+					Include: []string{"devices", " mdmServer ", "devices", ""},
+				})
+				if err != nil {
+					return err
+				}
+				if len(resp.Included) != 3 {
+					return fmt.Errorf("unexpected included length: %d", len(resp.Included))
+				}
+				if diff := cmp.Diff("others", resp.Included[2].Type); diff != "" {
+					return fmt.Errorf("unknown included type mismatch (-want +got):\n%s", diff)
+				}
+				devices := resp.IncludedOrgDevices()
+				if len(devices) != 1 {
+					return fmt.Errorf("unexpected included devices length: %d", len(devices))
+				}
+				if diff := cmp.Diff("SER-1", devices[0].Attributes.SerialNumber); diff != "" {
+					return fmt.Errorf("included device serial mismatch (-want +got):\n%s", diff)
+				}
+				servers := resp.IncludedMDMServers()
+				if len(servers) != 1 {
+					return fmt.Errorf("unexpected included servers length: %d", len(servers))
+				}
+				if diff := cmp.Diff("Primary MDM", servers[0].Attributes.ServerName); diff != "" {
+					return fmt.Errorf("included server name mismatch (-want +got):\n%s", diff)
+				}
 				return nil
 			},
 		},
@@ -438,6 +677,69 @@ func TestClient_APIError(t *testing.T) {
 	}
 }
 
+func TestClient_APIErrorRequestID(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		header    string
+		value     string
+		wantID    string
+		wantError string
+	}{
+		"success: X-Apple-Request-UUID": {
+			header:    "X-Apple-Request-UUID",
+			value:     "3f2a9c1e-0000-4b1d-9e8f-abcdef012345",
+			wantID:    "3f2a9c1e-0000-4b1d-9e8f-abcdef012345",
+			wantError: `abm api error: status=404 code="NOT_FOUND" detail="device not found" (request id: 3f2a9c1e-0000-4b1d-9e8f-abcdef012345)`,
+		},
+		"success: X-Request-ID": {
+			header:    "X-Request-ID",
+			value:     "req-123",
+			wantID:    "req-123",
+			wantError: `abm api error: status=404 code="NOT_FOUND" detail="device not found" (request id: req-123)`,
+		},
+		"success: no header": {
+			wantError: `abm api error: status=404 code="NOT_FOUND" detail="device not found"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.header != "" {
+					w.Header().Set(tt.header, tt.value)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"errors":[{"code":"NOT_FOUND","detail":"device not found","status":"404","title":"Not Found"}]}`)
+			}))
+			t.Cleanup(server.Close)
+
+			client := testClientForServer(t, server)
+			_, err := client.OrgDevices().Get(ctx, "missing-device", nil)
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected APIError, got: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantID, apiErr.RequestID); diff != "" {
+				t.Fatalf("request ID mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantError, apiErr.Error()); diff != "" {
+				t.Fatalf("error message mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestClient_ParameterValidation(t *testing.T) {
 	ctx := t.Context()
 	if err := ctx.Err(); err != nil {
@@ -477,6 +779,13 @@ func TestClient_ParameterValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		"error: unsupported include": {
+			invoke: func() error {
+				_, err := client.GetOrgDeviceActivity(ctx, "activity-1", &GetOrgDeviceActivityOptions{Include: []string{"assignedServer"}})
+				return err
+			},
+			wantErr: true,
+		},
 		"error: negative limit": {
 			invoke: func() error {
 				_, err := client.GetOrgDevices(ctx, &GetOrgDevicesOptions{Limit: -1})
@@ -484,6 +793,20 @@ func TestClient_ParameterValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		"error: invalid bluetooth mac": {
+			invoke: func() error {
+				_, err := client.GetOrgDevices(ctx, &GetOrgDevicesOptions{BluetoothMAC: "66:77:88:99:AA"})
+				return err
+			},
+			wantErr: true,
+		},
+		"error: invalid ethernet mac": {
+			invoke: func() error {
+				_, err := client.GetOrgDevices(ctx, &GetOrgDevicesOptions{EthernetMAC: "not-a-mac"})
+				return err
+			},
+			wantErr: true,
+		},
 		"error: too large limit": {
 			invoke: func() error {
 				_, err := client.GetMDMServers(ctx, &GetMDMServersOptions{Limit: 1001})
@@ -507,3 +830,1661 @@ func TestClient_ParameterValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_buildURL(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		baseURL string
+		path    string
+		query   url.Values
+		want    string
+		wantErr bool
+	}{
+		"success: default base url": {
+			baseURL: DefaultAPIBaseURL,
+			path:    orgDevicesPath,
+			want:    "https://api-business.apple.com/v1/orgDevices",
+		},
+		"success: base url path without trailing slash": {
+			baseURL: "https://example.test/abm",
+			path:    orgDevicesPath,
+			want:    "https://example.test/abm/v1/orgDevices",
+		},
+		"success: base url path with trailing slash": {
+			baseURL: "https://example.test/abm/",
+			path:    orgDevicesPath,
+			want:    "https://example.test/abm/v1/orgDevices",
+		},
+		"success: nested base url path": {
+			baseURL: "https://example.test/gateway/abm",
+			path:    mdmServersPath,
+			want:    "https://example.test/gateway/abm/v1/mdmServers",
+		},
+		"success: leading slash stays under base path": {
+			baseURL: "https://example.test/abm",
+			path:    "/v1/orgDevices",
+			want:    "https://example.test/abm/v1/orgDevices",
+		},
+		"success: dot slash stays under base path": {
+			baseURL: "https://example.test/abm",
+			path:    "./v1/orgDevices",
+			want:    "https://example.test/abm/v1/orgDevices",
+		},
+		"success: trailing slash on path is preserved": {
+			baseURL: "https://example.test/abm",
+			path:    "v1/orgDevices/",
+			want:    "https://example.test/abm/v1/orgDevices/",
+		},
+		"success: empty path resolves to base path": {
+			baseURL: "https://example.test/abm",
+			path:    "",
+			want:    "https://example.test/abm",
+		},
+		"success: base url with port": {
+			baseURL: "http://127.0.0.1:8080/abm",
+			path:    orgDevicesPath,
+			want:    "http://127.0.0.1:8080/abm/v1/orgDevices",
+		},
+		"success: query is encoded": {
+			baseURL: DefaultAPIBaseURL,
+			path:    orgDevicesPath,
+			query: url.Values{
+				"fields[orgDevices]": []string{"partNumber,serialNumber"},
+				"limit":              []string{"10"},
+			},
+			want: "https://api-business.apple.com/v1/orgDevices?fields%5BorgDevices%5D=partNumber%2CserialNumber&limit=10",
+		},
+		"success: base url query is merged": {
+			baseURL: "https://example.test/abm?tenant=acme",
+			path:    orgDevicesPath,
+			query: url.Values{
+				"limit": []string{"10"},
+			},
+			want: "https://example.test/abm/v1/orgDevices?limit=10&tenant=acme",
+		},
+		"success: base url query is kept without request query": {
+			baseURL: "https://example.test/abm?tenant=acme",
+			path:    orgDevicesPath,
+			want:    "https://example.test/abm/v1/orgDevices?tenant=acme",
+		},
+		"success: request query overrides base url query": {
+			baseURL: "https://example.test/abm?limit=1",
+			path:    orgDevicesPath,
+			query: url.Values{
+				"limit": []string{"10"},
+			},
+			want: "https://example.test/abm/v1/orgDevices?limit=10",
+		},
+		"success: base url fragment is dropped": {
+			baseURL: "https://example.test/abm#frag",
+			path:    orgDevicesPath,
+			want:    "https://example.test/abm/v1/orgDevices",
+		},
+		"success: escaped id with slash": {
+			baseURL: "https://example.test/abm",
+			path:    joinPath(orgDevicesPath, url.PathEscape("a/b")),
+			want:    "https://example.test/abm/v1/orgDevices/a%2Fb",
+		},
+		"success: escaped id with space and question mark": {
+			baseURL: DefaultAPIBaseURL,
+			path:    joinPath(orgDevicesPath, url.PathEscape("a b?c")),
+			want:    "https://api-business.apple.com/v1/orgDevices/a%20b%3Fc",
+		},
+		"success: escaped id with percent": {
+			baseURL: DefaultAPIBaseURL,
+			path:    joinPath(orgDevicesPath, url.PathEscape("100%")),
+			want:    "https://api-business.apple.com/v1/orgDevices/100%25",
+		},
+		"success: escaped base url path": {
+			baseURL: "https://example.test/a%2Fb",
+			path:    orgDevicesPath,
+			want:    "https://example.test/a%2Fb/v1/orgDevices",
+		},
+		"error: parent segment escapes base path": {
+			baseURL: "https://example.test/abm",
+			path:    "../v1/orgDevices",
+			wantErr: true,
+		},
+		"error: nested parent segments escape base path": {
+			baseURL: "https://example.test/gateway/abm",
+			path:    "v1/../../../v1/orgDevices",
+			wantErr: true,
+		},
+		"error: parent segment escapes to sibling prefix": {
+			baseURL: "https://example.test/abm",
+			path:    "../abm-other/v1/orgDevices",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			client, err := NewClientWithBaseURL(nil, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}), tt.baseURL)
+			if err != nil {
+				t.Fatalf("NewClientWithBaseURL returned error: %v", err)
+			}
+
+			got, err := client.buildURL(tt.path, tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildURL error mismatch: err=%v wantErr=%v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("url mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestClient_buildURLDifferential compares buildURL against a naive string
+// concatenation of the base URL path and the request path for every
+// combination of base URL shape and endpoint path the client produces.
+func TestClient_buildURLDifferential(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	baseURLs := []string{
+		"https://api-business.apple.com",
+		"https://api-business.apple.com/",
+		"https://example.test/abm",
+		"https://example.test/abm/",
+		"https://example.test/gateway/abm",
+		"https://example.test/gateway/abm/",
+		"http://127.0.0.1:8080",
+		"http://127.0.0.1:8080/prefix/",
+	}
+
+	ids := []string{
+		"device-1",
+		"C02ABC123XYZ",
+		"a/b",
+		"a b",
+		"a?b#c",
+		"100%",
+		"ü-device",
+	}
+
+	var paths []string
+	for _, id := range ids {
+		escapedID, err := validateAndEscapeID("id", id)
+		if err != nil {
+			t.Fatalf("validateAndEscapeID returned error: %v", err)
+		}
+		paths = append(paths,
+			orgDevicesPath,
+			mdmServersPath,
+			orgDeviceActivitiesURL,
+			joinPath(orgDevicesPath, escapedID),
+			joinPath(orgDevicesPath, escapedID, "appleCareCoverage"),
+			joinPath(orgDevicesPath, escapedID, "relationships", "assignedServer"),
+			joinPath(orgDevicesPath, escapedID, "assignedServer"),
+			joinPath(mdmServersPath, escapedID, "relationships", "devices"),
+			joinPath(orgDeviceActivitiesURL, escapedID),
+		)
+	}
+
+	tokenSource := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"})
+	for _, baseURL := range baseURLs {
+		client, err := NewClientWithBaseURL(nil, tokenSource, baseURL)
+		if err != nil {
+			t.Fatalf("NewClientWithBaseURL(%q) returned error: %v", baseURL, err)
+		}
+
+		for _, path := range paths {
+			for _, prefix := range []string{"", "/", "./"} {
+				requestPath := prefix + path
+				want := strings.TrimSuffix(baseURL, "/") + "/" + path
+
+				got, err := client.buildURL(requestPath, nil)
+				if err != nil {
+					t.Fatalf("buildURL(%q) with base %q returned error: %v", requestPath, baseURL, err)
+				}
+				if diff := cmp.Diff(want, got); diff != "" {
+					t.Fatalf("buildURL(%q) with base %q mismatch (-want +got):\n%s", requestPath, baseURL, diff)
+				}
+
+				parsed, err := url.Parse(got)
+				if err != nil {
+					t.Fatalf("parse built url %q: %v", got, err)
+				}
+				if !withinBaseURL(client.baseURL, parsed) {
+					t.Fatalf("built url %q is outside base url %q", got, client.baseURL)
+				}
+			}
+		}
+	}
+}
+
+func TestValidateAndEscapeID(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		id      string
+		want    string
+		wantErr bool
+	}{
+		"success: plain id": {
+			id:   "device-1",
+			want: "device-1",
+		},
+		"success: trims spaces": {
+			id:   "  device-1  ",
+			want: "device-1",
+		},
+		"success: escapes slash": {
+			id:   "a/b",
+			want: "a%2Fb",
+		},
+		"success: dots inside id": {
+			id:   "a..b",
+			want: "a..b",
+		},
+		"error: empty id": {
+			id:      " ",
+			wantErr: true,
+		},
+		"error: dot segment": {
+			id:      ".",
+			wantErr: true,
+		},
+		"error: parent segment": {
+			id:      "..",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			got, err := validateAndEscapeID("org device ID", tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateAndEscapeID error mismatch: err=%v wantErr=%v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("escaped id mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNormalizeMACAddress(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		mac     string
+		want    string
+		wantErr bool
+	}{
+		"success: colon separated": {
+			mac:  "66:77:88:99:aa:bb",
+			want: "66:77:88:99:AA:BB",
+		},
+		"success: hyphen separated": {
+			mac:  "66-77-88-99-AA-bb",
+			want: "66:77:88:99:AA:BB",
+		},
+		"success: dot separated": {
+			mac:  "6677.8899.aabb",
+			want: "66:77:88:99:AA:BB",
+		},
+		"success: bare hex with spaces": {
+			mac:  " 66778899aabb ",
+			want: "66:77:88:99:AA:BB",
+		},
+		"error: too short": {
+			mac:     "66:77:88:99:aa",
+			wantErr: true,
+		},
+		"error: non hex": {
+			mac:     "66:77:88:99:aa:zz",
+			wantErr: true,
+		},
+		"error: sign prefix": {
+			mac:     "+6677889aabb",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			got, err := normalizeMACAddress(tt.mac)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeMACAddress error mismatch: err=%v wantErr=%v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("normalizeMACAddress mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_CreateOrgDeviceActivityCorrelationID(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	bodyCh := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodyCh <- string(body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"data":{"id":"activity-1","type":"orgDeviceActivities"},"links":{"self":"https://api-business.apple.com/v1/orgDeviceActivities/activity-1"}}`)
+	}))
+	t.Cleanup(server.Close)
+	client := testClientForServer(t, server)
+
+	request := newOrgDeviceActivityCreateRequest(OrgDeviceActivityTypeAssignDevices, "mdm-1", "device-1")
+	request.CorrelationID = "job-42"
+
+	resp, err := client.CreateOrgDeviceActivity(ctx, request)
+	if err != nil {
+		t.Fatalf("CreateOrgDeviceActivity returned error: %v", err)
+	}
+	if diff := cmp.Diff("activity-1", resp.Data.ID); diff != "" {
+		t.Fatalf("activity id mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff("job-42", resp.CorrelationID); diff != "" {
+		t.Fatalf("correlation id mismatch (-want +got):\n%s", diff)
+	}
+
+	body := <-bodyCh
+	if strings.Contains(body, "job-42") || strings.Contains(body, "orrelation") {
+		t.Fatalf("correlation id leaked into request body: %s", body)
+	}
+	if !strings.HasPrefix(body, `{"data":{`) {
+		t.Fatalf("request body is not a JSON:API document: %s", body)
+	}
+}
+
+func TestClient_ConcurrentUse(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	const (
+		goroutines = 100
+		pageCount  = 3
+		pageSize   = 5
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/orgDevices":
+			pageNumber := 1
+			if page := r.URL.Query().Get("page"); page != "" {
+				pageNumber, _ = strconv.Atoi(page)
+			}
+			nextLink := ""
+			if pageNumber < pageCount {
+				nextLink = fmt.Sprintf("/v1/orgDevices?page=%d", pageNumber+1)
+			}
+			w.Write(buildOrgDevicesPageJSON(pageNumber, pageSize, nextLink))
+		case r.URL.Path == "/v1/orgDevices/device-1":
+			fmt.Fprint(w, `{"data":{"id":"device-1","type":"orgDevices"},"links":{"self":"/v1/orgDevices/device-1"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errors":[{"code":"NOT_FOUND","detail":"missing","status":"404","title":"Not Found"}]}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := testClientForServer(t, server, WithRetryPolicy(RetryPolicy{MaxRetries: 1}))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines)
+	for i := range goroutines {
+		wg.Go(func() {
+			switch i % 3 {
+			case 0:
+				resp, err := client.GetOrgDevice(ctx, "device-1", nil)
+				if err != nil {
+					errs <- fmt.Errorf("GetOrgDevice: %w", err)
+					return
+				}
+				if resp.Data.ID != "device-1" {
+					errs <- fmt.Errorf("GetOrgDevice returned %q", resp.Data.ID)
+				}
+			case 1:
+				partNumbers, err := client.FetchOrgDevicePartNumbers(ctx)
+				if err != nil {
+					errs <- fmt.Errorf("FetchOrgDevicePartNumbers: %w", err)
+					return
+				}
+				if len(partNumbers) != pageCount*pageSize {
+					errs <- fmt.Errorf("FetchOrgDevicePartNumbers returned %d part numbers", len(partNumbers))
+				}
+				rows, err := client.ExportOrgDevicesCSV(ctx, io.Discard, nil)
+				if err != nil {
+					errs <- fmt.Errorf("ExportOrgDevicesCSV: %w", err)
+					return
+				}
+				if rows != pageCount*pageSize {
+					errs <- fmt.Errorf("ExportOrgDevicesCSV wrote %d rows", rows)
+				}
+			case 2:
+				_, err := client.GetOrgDevice(ctx, "missing", nil)
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+					errs <- fmt.Errorf("GetOrgDevice(missing): expected 404 APIError, got %v", err)
+				}
+			}
+		})
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestWithAcceptLanguage(t *testing.T) {
+	tests := map[string]struct {
+		opts       []ClientOption
+		wantHeader []string
+	}{
+		"success: header is sent on every request": {
+			opts:       []ClientOption{WithAcceptLanguage("ja-JP")},
+			wantHeader: []string{"ja-JP"},
+		},
+		"success: empty language sends no header": {
+			opts:       []ClientOption{WithAcceptLanguage("")},
+			wantHeader: nil,
+		},
+		"success: header is omitted by default": {
+			wantHeader: nil,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			var mu sync.Mutex
+			var headers [][]string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				headers = append(headers, r.Header.Values("Accept-Language"))
+				mu.Unlock()
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"data":[],"links":{"self":"https://api-business.apple.com/v1/orgDevices"},"meta":{"paging":{"limit":100}}}`)
+			}))
+			t.Cleanup(server.Close)
+			client := testClientForServer(t, server, tt.opts...)
+
+			if _, err := client.GetOrgDevices(ctx, nil); err != nil {
+				t.Fatalf("GetOrgDevices returned error: %v", err)
+			}
+			if _, err := client.FetchOrgDevicePartNumbers(ctx); err != nil {
+				t.Fatalf("FetchOrgDevicePartNumbers returned error: %v", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if diff := cmp.Diff(2, len(headers)); diff != "" {
+				t.Fatalf("request count mismatch (-want +got):\n%s", diff)
+			}
+			for i, got := range headers {
+				if diff := cmp.Diff(tt.wantHeader, got); diff != "" {
+					t.Fatalf("Accept-Language mismatch for request %d (-want +got):\n%s", i, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_GetOrgDeviceConditional(t *testing.T) {
+	const currentETag = `"v2"`
+
+	tests := map[string]struct {
+		etag            string
+		wantIfNoneMatch string
+		wantModified    bool
+		wantETag        string
+		wantSerial      string
+	}{
+		"success: no cached etag fetches the device": {
+			etag:         "",
+			wantModified: true,
+			wantETag:     currentETag,
+			wantSerial:   "SERIAL-1",
+		},
+		"success: stale etag fetches the device": {
+			etag:            `"v1"`,
+			wantIfNoneMatch: `"v1"`,
+			wantModified:    true,
+			wantETag:        currentETag,
+			wantSerial:      "SERIAL-1",
+		},
+		"success: current etag is not modified": {
+			etag:            currentETag,
+			wantIfNoneMatch: currentETag,
+			wantModified:    false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			ifNoneMatch := make(chan string, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ifNoneMatch <- r.Header.Get("If-None-Match")
+				if r.Header.Get("If-None-Match") == currentETag {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("ETag", currentETag)
+				fmt.Fprint(w, `{"data":{"id":"device-1","type":"orgDevices","attributes":{"serialNumber":"SERIAL-1"}},"links":{"self":"https://api-business.apple.com/v1/orgDevices/device-1"}}`)
+			}))
+			t.Cleanup(server.Close)
+			client := testClientForServer(t, server)
+
+			resp, etag, modified, err := client.GetOrgDeviceConditional(ctx, "device-1", tt.etag, nil)
+			if err != nil {
+				t.Fatalf("GetOrgDeviceConditional returned error: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantIfNoneMatch, <-ifNoneMatch); diff != "" {
+				t.Fatalf("If-None-Match mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantModified, modified); diff != "" {
+				t.Fatalf("modified mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantETag, etag); diff != "" {
+				t.Fatalf("etag mismatch (-want +got):\n%s", diff)
+			}
+			if !tt.wantModified {
+				if resp != nil {
+					t.Fatalf("response = %+v, want nil when not modified", resp)
+				}
+				return
+			}
+			if diff := cmp.Diff(tt.wantSerial, resp.Data.Attributes.SerialNumber); diff != "" {
+				t.Fatalf("serial number mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewClient_ConnPoolOptions(t *testing.T) {
+	tests := map[string]struct {
+		transport               http.RoundTripper
+		opts                    []ClientOption
+		wantMaxIdleConns        int
+		wantMaxIdleConnsPerHost int
+		wantMaxConnsPerHost     int
+		wantErrText             string
+	}{
+		"success: per-host limit of one": {
+			transport:               &http.Transport{MaxIdleConns: 7},
+			opts:                    []ClientOption{WithMaxIdleConnsPerHost(1)},
+			wantMaxIdleConns:        7,
+			wantMaxIdleConnsPerHost: 1,
+		},
+		"success: both limits on the default transport": {
+			opts:                    []ClientOption{WithMaxIdleConns(64), WithMaxIdleConnsPerHost(32)},
+			wantMaxIdleConns:        64,
+			wantMaxIdleConnsPerHost: 32,
+		},
+		"success: connection pool": {
+			opts:                    []ClientOption{WithConnectionPool(128, 64, 16)},
+			wantMaxIdleConns:        128,
+			wantMaxIdleConnsPerHost: 64,
+			wantMaxConnsPerHost:     16,
+		},
+		"success: connection pool zero values": {
+			transport: &http.Transport{MaxIdleConns: 7, MaxConnsPerHost: 3},
+			opts:      []ClientOption{WithConnectionPool(0, 0, 0)},
+		},
+		"error: negative max conns per host": {
+			opts:        []ClientOption{WithConnectionPool(1, 1, -1)},
+			wantErrText: "max connections per host must not be negative: -1",
+		},
+		"error: negative max idle conns": {
+			opts:        []ClientOption{WithMaxIdleConns(-1)},
+			wantErrText: "max idle connections must not be negative: -1",
+		},
+		"error: negative max idle conns per host": {
+			opts:        []ClientOption{WithMaxIdleConnsPerHost(-1)},
+			wantErrText: "max idle connections per host must not be negative: -1",
+		},
+		"error: custom round tripper": {
+			transport:   roundTripperFunc(http.DefaultTransport.RoundTrip),
+			opts:        []ClientOption{WithMaxIdleConnsPerHost(1)},
+			wantErrText: "transport options require an *http.Transport",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"data":{"id":"device-1","type":"orgDevices"},"links":{"self":"https://api-business.apple.com/v1/orgDevices/device-1"}}`)
+			}))
+			t.Cleanup(server.Close)
+
+			tokenSource := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token"})
+			client, err := NewClientWithBaseURL(&http.Client{Transport: tt.transport}, tokenSource, server.URL, tt.opts...)
+			if tt.wantErrText != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrText) {
+					t.Fatalf("NewClientWithBaseURL error = %v, want containing %q", err, tt.wantErrText)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewClientWithBaseURL returned error: %v", err)
+			}
+
+			transport, ok := client.httpClient.Transport.(*oauth2.Transport).Base.(*http.Transport)
+			if !ok {
+				t.Fatalf("base transport is %T, want *http.Transport", client.httpClient.Transport.(*oauth2.Transport).Base)
+			}
+			if transport == tt.transport || transport == http.DefaultTransport {
+				t.Fatal("caller's transport was modified in place")
+			}
+			if diff := cmp.Diff(tt.wantMaxIdleConns, transport.MaxIdleConns); diff != "" {
+				t.Fatalf("MaxIdleConns mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantMaxIdleConnsPerHost, transport.MaxIdleConnsPerHost); diff != "" {
+				t.Fatalf("MaxIdleConnsPerHost mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantMaxConnsPerHost, transport.MaxConnsPerHost); diff != "" {
+				t.Fatalf("MaxConnsPerHost mismatch (-want +got):\n%s", diff)
+			}
+
+			for range 3 {
+				if _, err := client.GetOrgDevice(ctx, "device-1", nil); err != nil {
+					t.Fatalf("GetOrgDevice returned error: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestWithExpectContinue(t *testing.T) {
+	tests := map[string]struct {
+		opts           []ClientOption
+		reject         bool
+		wantTimeout    time.Duration
+		wantExpect     string
+		wantStatusCode int
+	}{
+		"success: header is sent and the body follows": {
+			opts:        []ClientOption{WithExpectContinue(2 * time.Second)},
+			wantTimeout: 2 * time.Second,
+			wantExpect:  "100-continue",
+		},
+		"success: disabled by default": {
+			wantExpect: "",
+		},
+		"error: server rejects before reading the body": {
+			opts:           []ClientOption{WithExpectContinue(0)},
+			reject:         true,
+			wantTimeout:    time.Second,
+			wantExpect:     "100-continue",
+			wantStatusCode: http.StatusConflict,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			expect := make(chan string, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				expect <- r.Header.Get("Expect")
+				w.Header().Set("Content-Type", "application/json")
+				if tt.reject {
+					w.WriteHeader(http.StatusConflict)
+					fmt.Fprint(w, `{"errors":[{"status":"409","code":"CONFLICT","title":"Conflict","detail":"device already assigned"}]}`)
+					return
+				}
+				if _, err := io.Copy(io.Discard, r.Body); err != nil {
+					t.Errorf("read request body: %v", err)
+				}
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprint(w, `{"data":{"id":"activity-1","type":"orgDeviceActivities"},"links":{"self":"https://api-business.apple.com/v1/orgDeviceActivities/activity-1"}}`)
+			}))
+			t.Cleanup(server.Close)
+			client := testClientForServer(t, server, tt.opts...)
+			if tt.wantTimeout > 0 {
+				transport := client.httpClient.Transport.(*oauth2.Transport).Base.(*http.Transport)
+				if diff := cmp.Diff(tt.wantTimeout, transport.ExpectContinueTimeout); diff != "" {
+					t.Fatalf("ExpectContinueTimeout mismatch (-want +got):\n%s", diff)
+				}
+			}
+
+			_, err := client.CreateOrgDeviceActivity(ctx, newOrgDeviceActivityCreateRequest(OrgDeviceActivityTypeAssignDevices, "mdm-1", "device-1"))
+			if diff := cmp.Diff(tt.wantExpect, <-expect); diff != "" {
+				t.Fatalf("Expect header mismatch (-want +got):\n%s", diff)
+			}
+			if tt.wantStatusCode == 0 {
+				if err != nil {
+					t.Fatalf("CreateOrgDeviceActivity returned error: %v", err)
+				}
+				return
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("CreateOrgDeviceActivity error = %v, want *APIError", err)
+			}
+			if diff := cmp.Diff(tt.wantStatusCode, apiErr.StatusCode); diff != "" {
+				t.Fatalf("status code mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_GetOrgDeviceReader(t *testing.T) {
+	const body = `{"data":{"id":"device-1","type":"orgDevices"},"links":{"self":"https://api-business.apple.com/v1/orgDevices/device-1"}}`
+
+	tests := map[string]struct {
+		orgDeviceID    string
+		wantStatusCode int
+		wantErr        bool
+	}{
+		"success: raw body is streamed": {
+			orgDeviceID: "device-1",
+		},
+		"error: api error": {
+			orgDeviceID:    "missing",
+			wantStatusCode: http.StatusNotFound,
+			wantErr:        true,
+		},
+		"error: missing org device id": {
+			orgDeviceID: "",
+			wantErr:     true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/orgDevices/device-1" {
+					w.WriteHeader(http.StatusNotFound)
+					fmt.Fprint(w, `{"errors":[{"status":"404","code":"NOT_FOUND","title":"Not Found","detail":"no such device"}]}`)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, body)
+			}))
+			t.Cleanup(server.Close)
+			client := testClientForServer(t, server)
+
+			reader, err := client.GetOrgDeviceReader(ctx, tt.orgDeviceID)
+			if tt.wantErr {
+				if err == nil {
+					reader.Close()
+					t.Fatal("expected error")
+				}
+				var apiErr *APIError
+				if tt.wantStatusCode != 0 && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantStatusCode) {
+					t.Fatalf("GetOrgDeviceReader error = %v, want APIError with status %d", err, tt.wantStatusCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetOrgDeviceReader returned error: %v", err)
+			}
+			if reader == nil {
+				t.Fatal("GetOrgDeviceReader returned a nil reader")
+			}
+			t.Cleanup(func() { reader.Close() })
+
+			first := make([]byte, 1)
+			if _, err := io.ReadFull(reader, first); err != nil {
+				t.Fatalf("read first byte: %v", err)
+			}
+			if diff := cmp.Diff("{", string(first)); diff != "" {
+				t.Fatalf("first byte mismatch (-want +got):\n%s", diff)
+			}
+			rest, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("read body: %v", err)
+			}
+			if diff := cmp.Diff(body, string(first)+string(rest)); diff != "" {
+				t.Fatalf("body mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_GetMDMServerDeviceLinkagesSort(t *testing.T) {
+	tests := map[string]struct {
+		sort        string
+		wantQuery   string
+		wantErrText string
+	}{
+		"success: ascending": {
+			sort:      "id",
+			wantQuery: "sort=id",
+		},
+		"success: descending": {
+			sort:      "-id",
+			wantQuery: "sort=-id",
+		},
+		"success: empty sort is omitted": {
+			sort:      "",
+			wantQuery: "",
+		},
+		"error: bare minus": {
+			sort:        "-",
+			wantErrText: `invalid sort "-"`,
+		},
+		"error: double minus": {
+			sort:        "--id",
+			wantErrText: `invalid sort "--id"`,
+		},
+		"error: multiple fields": {
+			sort:        "id,createdDateTime",
+			wantErrText: `invalid sort "id,createdDateTime"`,
+		},
+		"error: whitespace": {
+			sort:        " id",
+			wantErrText: `invalid sort " id"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			queries := make(chan string, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				queries <- r.URL.RawQuery
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"data":[],"links":{"self":"https://api-business.apple.com/v1/mdmServers/server-1/relationships/devices"}}`)
+			}))
+			t.Cleanup(server.Close)
+			client := testClientForServer(t, server)
+
+			_, err := client.GetMDMServerDeviceLinkages(ctx, "server-1", &GetMDMServerDeviceLinkagesOptions{Sort: tt.sort})
+			if tt.wantErrText != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrText) {
+					t.Fatalf("GetMDMServerDeviceLinkages error = %v, want containing %q", err, tt.wantErrText)
+				}
+				if len(queries) != 0 {
+					t.Fatal("request was sent despite an invalid sort")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetMDMServerDeviceLinkages returned error: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantQuery, <-queries); diff != "" {
+				t.Fatalf("query mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_GetMDMServerDeviceLinkagesOptionConflicts(t *testing.T) {
+	tests := map[string]struct {
+		options     *GetMDMServerDeviceLinkagesOptions
+		wantQuery   string
+		wantOptions []string
+	}{
+		"success: cursor": {
+			options:   &GetMDMServerDeviceLinkagesOptions{Cursor: "abc"},
+			wantQuery: "cursor=abc",
+		},
+		"success: cursor with limit": {
+			options:   &GetMDMServerDeviceLinkagesOptions{Cursor: "abc", Limit: 50},
+			wantQuery: "cursor=abc&limit=50",
+		},
+		"success: sort with limit": {
+			options:   &GetMDMServerDeviceLinkagesOptions{Sort: "-id", Limit: 50},
+			wantQuery: "limit=50&sort=-id",
+		},
+		"error: cursor with sort": {
+			options:     &GetMDMServerDeviceLinkagesOptions{Cursor: "abc", Sort: "id"},
+			wantOptions: []string{"Cursor", "Sort"},
+		},
+		"error: cursor with sort and limit": {
+			options:     &GetMDMServerDeviceLinkagesOptions{Cursor: "abc", Sort: "-id", Limit: 50},
+			wantOptions: []string{"Cursor", "Sort"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			queries := make(chan string, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				queries <- r.URL.RawQuery
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"data":[],"links":{"self":"https://api-business.apple.com/v1/mdmServers/server-1/relationships/devices"}}`)
+			}))
+			t.Cleanup(server.Close)
+			client := testClientForServer(t, server)
+
+			_, err := client.MDMServers().DeviceLinkages(ctx, "server-1", tt.options)
+			if tt.wantOptions != nil {
+				var conflict *OptionConflictError
+				if !errors.As(err, &conflict) {
+					t.Fatalf("DeviceLinkages error = %v, want *OptionConflictError", err)
+				}
+				if diff := cmp.Diff(tt.wantOptions, conflict.Options); diff != "" {
+					t.Fatalf("conflicting options mismatch (-want +got):\n%s", diff)
+				}
+				if !strings.Contains(err.Error(), "Cursor and Sort") {
+					t.Fatalf("error %q does not name the conflict", err)
+				}
+				if len(queries) != 0 {
+					t.Fatal("request was sent despite conflicting options")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DeviceLinkages returned error: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantQuery, <-queries); diff != "" {
+				t.Fatalf("query mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_GetMDMServersTimeFilters(t *testing.T) {
+	after := time.Date(2025, time.January, 1, 9, 0, 0, 0, time.FixedZone("JST", 9*60*60))
+	before := time.Date(2025, time.June, 30, 23, 59, 59, 0, time.UTC)
+
+	tests := map[string]struct {
+		options   *GetMDMServersOptions
+		wantQuery url.Values
+	}{
+		"success: no bounds": {
+			options:   &GetMDMServersOptions{},
+			wantQuery: url.Values{},
+		},
+		"success: created after": {
+			options: &GetMDMServersOptions{CreatedAfter: &after},
+			wantQuery: url.Values{
+				"filter[createdDateTime][gte]": {"2025-01-01T00:00:00Z"},
+			},
+		},
+		"success: created before": {
+			options: &GetMDMServersOptions{CreatedBefore: &before},
+			wantQuery: url.Values{
+				"filter[createdDateTime][lte]": {"2025-06-30T23:59:59Z"},
+			},
+		},
+		"success: created between": {
+			options: &GetMDMServersOptions{CreatedAfter: &after, CreatedBefore: &before},
+			wantQuery: url.Values{
+				"filter[createdDateTime][gte]": {"2025-01-01T00:00:00Z"},
+				"filter[createdDateTime][lte]": {"2025-06-30T23:59:59Z"},
+			},
+		},
+		"success: updated after": {
+			options: &GetMDMServersOptions{UpdatedAfter: &after},
+			wantQuery: url.Values{
+				"filter[updatedDateTime][gte]": {"2025-01-01T00:00:00Z"},
+			},
+		},
+		"success: updated before": {
+			options: &GetMDMServersOptions{UpdatedBefore: &before},
+			wantQuery: url.Values{
+				"filter[updatedDateTime][lte]": {"2025-06-30T23:59:59Z"},
+			},
+		},
+		"success: updated between": {
+			options: &GetMDMServersOptions{UpdatedAfter: &after, UpdatedBefore: &before},
+			wantQuery: url.Values{
+				"filter[updatedDateTime][gte]": {"2025-01-01T00:00:00Z"},
+				"filter[updatedDateTime][lte]": {"2025-06-30T23:59:59Z"},
+			},
+		},
+		"success: created and updated bounds": {
+			options: &GetMDMServersOptions{CreatedAfter: &after, UpdatedBefore: &before},
+			wantQuery: url.Values{
+				"filter[createdDateTime][gte]": {"2025-01-01T00:00:00Z"},
+				"filter[updatedDateTime][lte]": {"2025-06-30T23:59:59Z"},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			queries := make(chan url.Values, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				queries <- r.URL.Query()
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"data":[],"links":{"self":"https://api-business.apple.com/v1/mdmServers"}}`)
+			}))
+			t.Cleanup(server.Close)
+			client := testClientForServer(t, server)
+
+			if _, err := client.MDMServers().List(ctx, tt.options); err != nil {
+				t.Fatalf("List returned error: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantQuery, <-queries); diff != "" {
+				t.Fatalf("query mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithAcceptedStatuses(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	const activityJSON = `{"data":{"id":"activity-1","type":"orgDeviceActivities","attributes":{"status":"IN_PROGRESS"}}}`
+
+	tests := map[string]struct {
+		status       int
+		accepted     []int
+		wantID       string
+		wantErr      string
+		wantExpected []int
+	}{
+		"success: gateway 200 accepted with override": {
+			status:   http.StatusOK,
+			accepted: []int{http.StatusOK},
+			wantID:   "activity-1",
+		},
+		"success: default 201 still accepted with override": {
+			status:   http.StatusCreated,
+			accepted: []int{http.StatusOK},
+			wantID:   "activity-1",
+		},
+		"error: 200 rejected by default": {
+			status:       http.StatusOK,
+			wantErr:      "abm api error: got status 200, expected [201]",
+			wantExpected: []int{http.StatusCreated},
+		},
+		"error: 200 rejected by other override": {
+			status:       http.StatusOK,
+			accepted:     []int{http.StatusAccepted, http.StatusCreated},
+			wantErr:      "abm api error: got status 200, expected [201 202]",
+			wantExpected: []int{http.StatusCreated, http.StatusAccepted},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, activityJSON)
+			}))
+			t.Cleanup(server.Close)
+			client := testClientForServer(t, server)
+
+			request := newOrgDeviceActivityCreateRequest(OrgDeviceActivityTypeAssignDevices, "mdm-1", "device-1")
+			resp, err := client.CreateOrgDeviceActivity(WithAcceptedStatuses(ctx, tt.accepted...), request)
+			if tt.wantErr != "" {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) {
+					t.Fatalf("expected APIError, got: %v", err)
+				}
+				if diff := cmp.Diff(tt.wantErr, apiErr.Error()); diff != "" {
+					t.Fatalf("error message mismatch (-want +got):\n%s", diff)
+				}
+				if diff := cmp.Diff(tt.wantExpected, apiErr.ExpectedStatusCodes); diff != "" {
+					t.Fatalf("expected status codes mismatch (-want +got):\n%s", diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateOrgDeviceActivity returned error: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantID, resp.Data.ID); diff != "" {
+				t.Fatalf("activity id mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(OrgDeviceActivityStatusInProgress, resp.Data.Attributes.Status); diff != "" {
+				t.Fatalf("activity status mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAcceptedStatuses(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		accepted []int
+		defaults []int
+		want     []int
+	}{
+		"success: defaults without override": {
+			defaults: []int{http.StatusOK, http.StatusNotModified},
+			want:     []int{http.StatusOK, http.StatusNotModified},
+		},
+		"success: override keeps not modified": {
+			accepted: []int{http.StatusOK},
+			defaults: []int{http.StatusOK, http.StatusNotModified},
+			want:     []int{http.StatusOK, http.StatusNotModified},
+		},
+		"success: override adds gateway status": {
+			accepted: []int{http.StatusOK},
+			defaults: []int{http.StatusCreated},
+			want:     []int{http.StatusCreated, http.StatusOK},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			got := acceptedStatuses(WithAcceptedStatuses(ctx, tt.accepted...), tt.defaults)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("acceptedStatuses mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_GetOrgDeviceActivityHistory(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		body string
+		want []OrgDeviceActivityHistoryEntry
+	}{
+		"success: history entries": {
+			body: `{"data":{"id":"activity-1","type":"orgDeviceActivities","attributes":{"status":"COMPLETED","history":[` +
+				`{"status":"IN_PROGRESS","timestamp":"2026-01-02T03:04:05Z","description":"Activity created"},` +
+				`{"status":"COMPLETED","timestamp":"2026-01-02T03:09:05.5+09:00","description":"All devices assigned"}]}}}`,
+			want: []OrgDeviceActivityHistoryEntry{
+				{
+					Status:      OrgDeviceActivityStatusInProgress,
+					Timestamp:   time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC),
+					Description: "Activity created",
+				},
+				{
+					Status:      OrgDeviceActivityStatusCompleted,
+					Timestamp:   time.Date(2026, time.January, 2, 3, 9, 5, 500_000_000, time.FixedZone("", 9*60*60)),
+					Description: "All devices assigned",
+				},
+			},
+		},
+		"success: history omitted": {
+			body: `{"data":{"id":"activity-1","type":"orgDeviceActivities","attributes":{"status":"IN_PROGRESS"}}}`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, tt.body)
+			}))
+			t.Cleanup(server.Close)
+			client := testClientForServer(t, server)
+
+			resp, err := client.GetOrgDeviceActivity(ctx, "activity-1", nil)
+			if err != nil {
+				t.Fatalf("GetOrgDeviceActivity returned error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, resp.Data.Attributes.History, cmpopts.EquateApproxTime(0)); diff != "" {
+				t.Fatalf("history mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithErrorBodyMaxBytes(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	// A structured error followed by a large HTML-like tail, as a proxy error page might send.
+	largeBody := `{"errors":[{"code":"FORBIDDEN","detail":"access denied"}],"page":"` + strings.Repeat("x", 1<<20) + `"}`
+	multiByteBody := strings.Repeat("あ", 10)
+
+	tests := map[string]struct {
+		body          string
+		opts          []ClientOption
+		wantBody      string
+		wantTruncated bool
+		wantCode      string
+		wantMessage   string
+	}{
+		"success: large body truncated to default": {
+			body:          largeBody,
+			wantBody:      largeBody[:defaultErrorBodyMaxBytes],
+			wantTruncated: true,
+			wantCode:      "FORBIDDEN",
+			wantMessage:   `abm api error: status=403 code="FORBIDDEN" detail="access denied"`,
+		},
+		"success: large body truncated to option": {
+			body:          largeBody,
+			opts:          []ClientOption{WithErrorBodyMaxBytes(16)},
+			wantBody:      largeBody[:16],
+			wantTruncated: true,
+			wantCode:      "FORBIDDEN",
+		},
+		"success: small body kept": {
+			body:     `{"errors":[{"code":"FORBIDDEN"}]}`,
+			opts:     []ClientOption{WithErrorBodyMaxBytes(1 << 10)},
+			wantBody: `{"errors":[{"code":"FORBIDDEN"}]}`,
+			wantCode: "FORBIDDEN",
+		},
+		"success: truncated at rune boundary": {
+			body:          multiByteBody,
+			opts:          []ClientOption{WithErrorBodyMaxBytes(8)},
+			wantBody:      "ああ",
+			wantTruncated: true,
+			wantMessage:   `abm api error: status=403 body="ああ" (truncated)`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, tt.body)
+			}))
+			t.Cleanup(server.Close)
+			client := testClientForServer(t, server, tt.opts...)
+
+			_, err := client.GetOrgDevice(ctx, "device-1", nil)
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected APIError, got: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantBody, apiErr.Body); diff != "" {
+				t.Fatalf("body mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantTruncated, apiErr.BodyTruncated); diff != "" {
+				t.Fatalf("truncated mismatch (-want +got):\n%s", diff)
+			}
+			var gotCode string
+			if len(apiErr.Response.Errors) > 0 {
+				gotCode = apiErr.Response.Errors[0].Code
+			}
+			if diff := cmp.Diff(tt.wantCode, gotCode); diff != "" {
+				t.Fatalf("error code mismatch (-want +got):\n%s", diff)
+			}
+			if tt.wantMessage != "" {
+				if diff := cmp.Diff(tt.wantMessage, apiErr.Error()); diff != "" {
+					t.Fatalf("error message mismatch (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestLimitValidationError(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		call func(ctx context.Context, client *Client) error
+		want LimitValidationError
+	}{
+		"error: negative limit": {
+			call: func(ctx context.Context, client *Client) error {
+				_, err := client.OrgDevices().List(ctx, &GetOrgDevicesOptions{Limit: -1})
+				return err
+			},
+			want: LimitValidationError{Value: -1, Min: 0, Max: maxPageLimit},
+		},
+		"error: limit too large": {
+			call: func(ctx context.Context, client *Client) error {
+				_, err := client.MDMServers().List(ctx, &GetMDMServersOptions{Limit: maxPageLimit + 1})
+				return err
+			},
+			want: LimitValidationError{Value: maxPageLimit + 1, Min: 0, Max: maxPageLimit},
+		},
+		"error: crawl limit too large": {
+			call: func(ctx context.Context, client *Client) error {
+				_, err := client.OrgDeviceCountCrawled(ctx, &GetOrgDevicesOptions{Limit: 5000})
+				return err
+			},
+			want: LimitValidationError{Value: 5000, Min: 0, Max: maxPageLimit},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Errorf("unexpected request: %s", r.URL)
+			}))
+			t.Cleanup(server.Close)
+
+			err := tt.call(ctx, testClientForServer(t, server))
+			var limitErr *LimitValidationError
+			if !errors.As(err, &limitErr) {
+				t.Fatalf("error = %v, want *LimitValidationError", err)
+			}
+			if diff := cmp.Diff(tt.want, *limitErr); diff != "" {
+				t.Fatalf("LimitValidationError mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestErrorHelpers(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		err  error
+		want string
+	}{
+		"success: missing ID": {
+			err:  errMissingID("org device ID"),
+			want: "org device ID is required",
+		},
+		"success: negative limit": {
+			err:  errInvalidLimit(-1),
+			want: "limit -1 is out of range [0, 1000]",
+		},
+		"success: limit too large": {
+			err:  errInvalidLimit(maxPageLimit + 1),
+			want: "limit 1001 is out of range [0, 1000]",
+		},
+		"success: nil token source": {
+			err:  errNilTokenSource(),
+			want: "token source is required",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, tt.err.Error()); diff != "" {
+				t.Fatalf("error message mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithPathRewriter(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	prefix := func(path string) string { return "apple-bm/" + path }
+
+	tests := map[string]struct {
+		rewriter  func(string) string
+		prefix    string
+		call      func(context.Context, *Client) error
+		wantPaths []string
+	}{
+		"success: rewritten single request": {
+			rewriter: prefix,
+			prefix:   "/apple-bm",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.OrgDevices().Get(ctx, "device-1", nil)
+				return err
+			},
+			wantPaths: []string{"/apple-bm/v1/orgDevices/device-1"},
+		},
+		"success: page iterator next link not rewritten again": {
+			rewriter: prefix,
+			prefix:   "/apple-bm",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.FetchOrgDevicePartNumbers(ctx)
+				return err
+			},
+			wantPaths: []string{"/apple-bm/v1/orgDevices", "/apple-bm/v1/orgDevices"},
+		},
+		"success: crawl next link not rewritten again": {
+			rewriter: prefix,
+			prefix:   "/apple-bm",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.OrgDeviceCountCrawled(ctx, nil)
+				return err
+			},
+			wantPaths: []string{"/apple-bm/v1/orgDevices", "/apple-bm/v1/orgDevices"},
+		},
+		"success: nil rewriter is identity": {
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.FetchOrgDevicePartNumbers(ctx)
+				return err
+			},
+			wantPaths: []string{"/v1/orgDevices", "/v1/orgDevices"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			var (
+				mu    sync.Mutex
+				paths []string
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				paths = append(paths, r.URL.Path)
+				mu.Unlock()
+
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/device-1"):
+					fmt.Fprint(w, `{"data":{"id":"device-1","type":"orgDevices"}}`)
+				case r.URL.Query().Get("page") == "":
+					fmt.Fprintf(w, `{"data":[{"id":"device-1","type":"orgDevices","attributes":{"partNumber":"P1"}}],"links":{"next":"%s/v1/orgDevices?page=2"}}`, tt.prefix)
+				default:
+					fmt.Fprint(w, `{"data":[{"id":"device-2","type":"orgDevices","attributes":{"partNumber":"P2"}}]}`)
+				}
+			}))
+			t.Cleanup(server.Close)
+			client := testClientForServer(t, server, WithPathRewriter(tt.rewriter))
+
+			if err := tt.call(ctx, client); err != nil {
+				t.Fatalf("call returned error: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantPaths, paths); diff != "" {
+				t.Fatalf("request paths mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_ConnectionReuse(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	const calls = 100
+
+	// A body larger than the transport's read buffer, so a partial read
+	// leaves unread bytes on the connection.
+	large := `{"data":{"id":"device-1","type":"orgDevices","attributes":{"color":"` + strings.Repeat("x", 64<<10) + `"}}}`
+
+	stalled := make(chan struct{})
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch strings.TrimPrefix(r.URL.Path, "/v1/orgDevices") {
+		case "":
+			fmt.Fprint(w, `{"data":[{"id":"device-1","type":"orgDevices"}],"links":{"self":"/v1/orgDevices"}}`)
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errors":[{"code":"NOT_FOUND","detail":"device not found","status":"404","title":"Not Found"}]}`)
+		case "/malformed":
+			fmt.Fprint(w, `{"data":`)
+		case "/large":
+			fmt.Fprint(w, large)
+		case "/stalled":
+			// Send the headers and part of the body, then stall until the
+			// client gives up on the request.
+			fmt.Fprint(w, `{"data":`)
+			w.(http.Flusher).Flush()
+			select {
+			case <-r.Context().Done():
+			case <-stalled:
+			}
+		}
+	}))
+	var newConns atomic.Int64
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			newConns.Add(1)
+		}
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(stalled) })
+
+	client := testClientForServer(t, server)
+
+	stalledCalls := 0
+	for i := range calls {
+		switch i % 10 {
+		case 0, 1, 2:
+			if _, err := client.OrgDevices().List(ctx, nil); err != nil {
+				t.Fatalf("call %d: List: %v", i, err)
+			}
+		case 3, 4:
+			var apiErr *APIError
+			if _, err := client.OrgDevices().Get(ctx, "missing", nil); !errors.As(err, &apiErr) {
+				t.Fatalf("call %d: Get error = %v, want *APIError", i, err)
+			}
+		case 5:
+			if _, err := client.OrgDevices().Get(ctx, "malformed", nil); err == nil || !strings.Contains(err.Error(), "decode response body") {
+				t.Fatalf("call %d: Get error = %v, want decode error", i, err)
+			}
+		case 6, 7:
+			// The caller reads only part of a streamed body before closing it.
+			body, err := client.OrgDevices().GetReader(ctx, "large")
+			if err != nil {
+				t.Fatalf("call %d: GetReader: %v", i, err)
+			}
+			if _, err := io.ReadFull(body, make([]byte, 16)); err != nil {
+				t.Fatalf("call %d: read: %v", i, err)
+			}
+			if err := body.Close(); err != nil {
+				t.Fatalf("call %d: close: %v", i, err)
+			}
+		case 8:
+			canceled, cancel := context.WithCancel(ctx)
+			cancel()
+			if _, err := client.OrgDevices().List(canceled, nil); !errors.Is(err, context.Canceled) {
+				t.Fatalf("call %d: List error = %v, want context.Canceled", i, err)
+			}
+		case 9:
+			// Canceled between the response headers and the end of the body.
+			// The connection cannot be reused, but it must not leak either.
+			stalledCalls++
+			callCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+			_, err := client.OrgDevices().Get(callCtx, "stalled", nil)
+			cancel()
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("call %d: Get error = %v, want context.DeadlineExceeded", i, err)
+			}
+		}
+	}
+
+	// Each stalled call costs its connection; every other call reuses one.
+	if got, limit := newConns.Load(), int64(stalledCalls+1); got > limit {
+		t.Fatalf("%d calls opened %d connections, want at most %d", calls, got, limit)
+	}
+}
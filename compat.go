@@ -0,0 +1,112 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"context"
+	"io"
+)
+
+// The flat Client methods below predate the service groups returned by
+// [Client.OrgDevices], [Client.MDMServers], and [Client.Activities], and
+// delegate to them.
+
+// GetOrgDevices gets a page of organization devices.
+//
+// Deprecated: Use [OrgDevicesService.List] through [Client.OrgDevices].
+func (c *Client) GetOrgDevices(ctx context.Context, options *GetOrgDevicesOptions, opts ...CallOption) (*OrgDevicesResponse, error) {
+	return c.OrgDevices().List(ctx, options, opts...)
+}
+
+// GetOrgDevice gets information for a single organization device.
+//
+// Deprecated: Use [OrgDevicesService.Get] through [Client.OrgDevices].
+func (c *Client) GetOrgDevice(ctx context.Context, orgDeviceID string, options *GetOrgDeviceOptions, opts ...CallOption) (*OrgDeviceResponse, error) {
+	return c.OrgDevices().Get(ctx, orgDeviceID, options, opts...)
+}
+
+// GetOrgDeviceConditional gets a single organization device unless it is unchanged since the
+// response that returned etag.
+//
+// Deprecated: Use [OrgDevicesService.GetConditional] through [Client.OrgDevices].
+func (c *Client) GetOrgDeviceConditional(ctx context.Context, orgDeviceID, etag string, options *GetOrgDeviceOptions, opts ...CallOption) (*OrgDeviceResponse, string, bool, error) {
+	return c.OrgDevices().GetConditional(ctx, orgDeviceID, etag, options, opts...)
+}
+
+// GetOrgDeviceReader gets a single organization device as a raw JSON stream.
+//
+// Deprecated: Use [OrgDevicesService.GetReader] through [Client.OrgDevices].
+func (c *Client) GetOrgDeviceReader(ctx context.Context, orgDeviceID string) (io.ReadCloser, error) {
+	return c.OrgDevices().GetReader(ctx, orgDeviceID)
+}
+
+// GetOrgDeviceAppleCareCoverage gets AppleCare coverage information for a single organization device.
+//
+// Deprecated: Use [OrgDevicesService.AppleCareCoverage] through [Client.OrgDevices].
+func (c *Client) GetOrgDeviceAppleCareCoverage(ctx context.Context, orgDeviceID string, options *GetOrgDeviceAppleCareCoverageOptions, opts ...CallOption) (*AppleCareCoverageResponse, error) {
+	return c.OrgDevices().AppleCareCoverage(ctx, orgDeviceID, options, opts...)
+}
+
+// GetOrgDeviceAssignedServerLinkage gets assigned device-management service ID linkage for a device.
+//
+// Deprecated: Use [OrgDevicesService.AssignedServerLinkage] through [Client.OrgDevices].
+func (c *Client) GetOrgDeviceAssignedServerLinkage(ctx context.Context, orgDeviceID string, opts ...CallOption) (*OrgDeviceAssignedServerLinkageResponse, error) {
+	return c.OrgDevices().AssignedServerLinkage(ctx, orgDeviceID, opts...)
+}
+
+// GetOrgDeviceAssignedServer gets assigned device-management service information for a device.
+//
+// Deprecated: Use [OrgDevicesService.AssignedServer] through [Client.OrgDevices].
+func (c *Client) GetOrgDeviceAssignedServer(ctx context.Context, orgDeviceID string, options *GetOrgDeviceAssignedServerOptions, opts ...CallOption) (*MDMServerResponse, error) {
+	return c.OrgDevices().AssignedServer(ctx, orgDeviceID, options, opts...)
+}
+
+// GetMDMServers gets a page of device management services.
+//
+// Deprecated: Use [MDMServersService.List] through [Client.MDMServers].
+func (c *Client) GetMDMServers(ctx context.Context, options *GetMDMServersOptions, opts ...CallOption) (*MDMServersResponse, error) {
+	return c.MDMServers().List(ctx, options, opts...)
+}
+
+// GetMDMServerDeviceLinkages gets a page of the org-device IDs linked to a device management service.
+//
+// Deprecated: Use [MDMServersService.DeviceLinkages] through [Client.MDMServers].
+func (c *Client) GetMDMServerDeviceLinkages(ctx context.Context, mdmServerID string, options *GetMDMServerDeviceLinkagesOptions, opts ...CallOption) (*MDMServerDevicesLinkagesResponse, error) {
+	return c.MDMServers().DeviceLinkages(ctx, mdmServerID, options, opts...)
+}
+
+// CreateOrgDeviceActivity creates an org-device activity that assigns or unassigns devices.
+//
+// Deprecated: Use [ActivitiesService.Create] through [Client.Activities].
+func (c *Client) CreateOrgDeviceActivity(ctx context.Context, request OrgDeviceActivityCreateRequest, opts ...CallOption) (*OrgDeviceActivityResponse, error) {
+	return c.Activities().Create(ctx, request, opts...)
+}
+
+// GetOrgDeviceActivity gets organization device activity information.
+//
+// Deprecated: Use [ActivitiesService.Get] through [Client.Activities].
+func (c *Client) GetOrgDeviceActivity(ctx context.Context, orgDeviceActivityID string, options *GetOrgDeviceActivityOptions, opts ...CallOption) (*OrgDeviceActivityResponse, error) {
+	return c.Activities().Get(ctx, orgDeviceActivityID, options, opts...)
+}
+
+// WaitForOrgDeviceActivity polls the org-device activity until it finishes or
+// ctx is done, and returns the final activity.
+//
+// Deprecated: Use [ActivitiesService.Wait] through [Client.Activities].
+func (c *Client) WaitForOrgDeviceActivity(ctx context.Context, orgDeviceActivityID string, options *WaitOptions) (*OrgDeviceActivityResponse, error) {
+	return c.Activities().Wait(ctx, orgDeviceActivityID, options)
+}
@@ -0,0 +1,263 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+// Config holds everything needed to construct a [Client], in a form suitable
+// for loading from a JSON or YAML configuration file. Optional fields left at
+// their zero value use the library defaults.
+type Config struct {
+	// ClientID is the API account's client ID. It is required.
+	ClientID string `json:"clientId" yaml:"clientId"`
+
+	// KeyID is the ID of the API account's private key. It is required.
+	KeyID string `json:"keyId" yaml:"keyId"`
+
+	// PrivateKey is the PEM-encoded private key. Exactly one of PrivateKey and
+	// PrivateKeyPath must be set.
+	PrivateKey string `json:"privateKey,omitzero" yaml:"privateKey,omitempty"`
+
+	// PrivateKeyPath is the path of a PEM-encoded private key file.
+	PrivateKeyPath string `json:"privateKeyPath,omitzero" yaml:"privateKeyPath,omitempty"`
+
+	// BaseURL is the API base URL. Empty means [DefaultAPIBaseURL].
+	BaseURL string `json:"baseUrl,omitzero" yaml:"baseUrl,omitempty"`
+
+	// TokenURL is the OAuth2 token endpoint. Empty means [TokenURL].
+	TokenURL string `json:"tokenUrl,omitzero" yaml:"tokenUrl,omitempty"`
+
+	// Scope is the OAuth2 scope. Empty means [ScopeBusinessAPI].
+	Scope string `json:"scope,omitzero" yaml:"scope,omitempty"`
+
+	// RequestTimeout bounds each API and token request, including reading the
+	// response body. Zero means the API requests have no timeout and token
+	// requests use the [NewTokenSource] default. In JSON it is a duration
+	// string such as "30s".
+	RequestTimeout time.Duration `json:"requestTimeout,omitzero" yaml:"requestTimeout,omitempty"`
+
+	// RetryPolicy controls retries of transient GET failures. The zero value
+	// disables retries, see [WithRetryPolicy]. Its delays are duration
+	// strings in JSON, like RequestTimeout.
+	RetryPolicy RetryPolicy `json:"retryPolicy,omitzero" yaml:"retryPolicy,omitempty"`
+}
+
+// configJSON is the JSON form of [Config], with the durations encoded as
+// duration strings.
+type configJSON struct {
+	ClientID       string       `json:"clientId"`
+	KeyID          string       `json:"keyId"`
+	PrivateKey     string       `json:"privateKey,omitzero"`
+	PrivateKeyPath string       `json:"privateKeyPath,omitzero"`
+	BaseURL        string       `json:"baseUrl,omitzero"`
+	TokenURL       string       `json:"tokenUrl,omitzero"`
+	Scope          string       `json:"scope,omitzero"`
+	RequestTimeout jsonDuration `json:"requestTimeout,omitzero"`
+	RetryPolicy    RetryPolicy  `json:"retryPolicy,omitzero"`
+}
+
+// MarshalJSONTo implements [json.MarshalerTo].
+func (cfg Config) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return json.MarshalEncode(enc, configJSON{
+		ClientID:       cfg.ClientID,
+		KeyID:          cfg.KeyID,
+		PrivateKey:     cfg.PrivateKey,
+		PrivateKeyPath: cfg.PrivateKeyPath,
+		BaseURL:        cfg.BaseURL,
+		TokenURL:       cfg.TokenURL,
+		Scope:          cfg.Scope,
+		RequestTimeout: jsonDuration(cfg.RequestTimeout),
+		RetryPolicy:    cfg.RetryPolicy,
+	})
+}
+
+// UnmarshalJSONFrom implements [json.UnmarshalerFrom].
+func (cfg *Config) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	var v configJSON
+	if err := json.UnmarshalDecode(dec, &v); err != nil {
+		return err
+	}
+
+	*cfg = Config{
+		ClientID:       v.ClientID,
+		KeyID:          v.KeyID,
+		PrivateKey:     v.PrivateKey,
+		PrivateKeyPath: v.PrivateKeyPath,
+		BaseURL:        v.BaseURL,
+		TokenURL:       v.TokenURL,
+		Scope:          v.Scope,
+		RequestTimeout: time.Duration(v.RequestTimeout),
+		RetryPolicy:    v.RetryPolicy,
+	}
+	return nil
+}
+
+// jsonDuration is a [time.Duration] encoded in JSON as a duration string
+// such as "1m30s", using [time.Duration.String] and [time.ParseDuration].
+type jsonDuration time.Duration
+
+// MarshalJSONTo implements [json.MarshalerTo].
+func (d jsonDuration) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return enc.WriteToken(jsontext.String(time.Duration(d).String()))
+}
+
+// UnmarshalJSONFrom implements [json.UnmarshalerFrom].
+func (d *jsonDuration) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	tok, err := dec.ReadToken()
+	if err != nil {
+		return err
+	}
+	if tok.Kind() != '"' {
+		return fmt.Errorf("duration must be a string such as \"30s\", got %s", tok.Kind())
+	}
+
+	parsed, err := time.ParseDuration(tok.String())
+	if err != nil {
+		return err
+	}
+	*d = jsonDuration(parsed)
+	return nil
+}
+
+// Validate checks the configuration up front: the required credentials are
+// present, exactly one private key source is set and holds a valid ECDSA key,
+// and the URLs, scope, timeout and retry policy are well formed. All problems
+// are reported together.
+func (cfg Config) Validate() error {
+	_, err := cfg.validate()
+	return err
+}
+
+// validate is like Validate and also returns the PEM-encoded private key.
+func (cfg Config) validate() ([]byte, error) {
+	var errs []error
+
+	if strings.TrimSpace(cfg.ClientID) == "" {
+		errs = append(errs, errors.New("client ID is required"))
+	}
+	if strings.TrimSpace(cfg.KeyID) == "" {
+		errs = append(errs, errors.New("key ID is required"))
+	}
+
+	var pemBytes []byte
+	switch {
+	case cfg.PrivateKey != "" && cfg.PrivateKeyPath != "":
+		errs = append(errs, errors.New("private key and private key path are mutually exclusive"))
+	case cfg.PrivateKey != "":
+		pemBytes = []byte(cfg.PrivateKey)
+	case cfg.PrivateKeyPath != "":
+		data, err := os.ReadFile(cfg.PrivateKeyPath)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("read private key: %w", err))
+		}
+		pemBytes = data
+	default:
+		errs = append(errs, errors.New("one of private key or private key path is required"))
+	}
+	if pemBytes != nil {
+		if _, err := parseECDSAPrivateKeyFromPEM(pemBytes); err != nil {
+			errs = append(errs, fmt.Errorf("parse private key: %w", err))
+		}
+	}
+
+	if cfg.BaseURL != "" {
+		if _, err := ParseBaseURL(cfg.BaseURL); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if cfg.TokenURL != "" {
+		if u, err := url.Parse(cfg.TokenURL); err != nil {
+			errs = append(errs, fmt.Errorf("parse token URL: %w", err))
+		} else if !u.IsAbs() || u.Host == "" {
+			errs = append(errs, fmt.Errorf("token URL must be absolute: %q", cfg.TokenURL))
+		}
+	}
+	if strings.ContainsAny(cfg.Scope, " \t\n") {
+		errs = append(errs, fmt.Errorf("scope must be a single scope: %q", cfg.Scope))
+	}
+	if cfg.RequestTimeout < 0 {
+		errs = append(errs, fmt.Errorf("request timeout must not be negative: %s", cfg.RequestTimeout))
+	}
+	if cfg.RetryPolicy.MaxRetries < 0 {
+		errs = append(errs, fmt.Errorf("retry policy max retries must not be negative: %d", cfg.RetryPolicy.MaxRetries))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return pemBytes, nil
+}
+
+// NewClientFromConfig validates cfg, creates the client assertion and token
+// source, and returns a client for cfg.BaseURL. opts are applied after the
+// options derived from cfg, so they take precedence.
+//
+// As with [NewTokenSource], ctx is used for the token requests made over the
+// client's lifetime and should not be canceled while the client is in use.
+func NewClientFromConfig(ctx context.Context, cfg Config, opts ...ClientOption) (*Client, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	privateKey, err := cfg.validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	assertion, err := NewAssertion(ctx, cfg.ClientID, cfg.KeyID, string(privateKey))
+	if err != nil {
+		return nil, fmt.Errorf("create client assertion: %w", err)
+	}
+
+	// The token source uses the Timeout as is. The API client moves it to the
+	// request context, see [NewClientWithBaseURL].
+	var httpClient *http.Client
+	if cfg.RequestTimeout > 0 {
+		httpClient = &http.Client{Timeout: cfg.RequestTimeout}
+	}
+
+	var tokenOpts []TokenSourceOption
+	if cfg.TokenURL != "" {
+		tokenOpts = append(tokenOpts, WithTokenURL(cfg.TokenURL))
+	}
+	tokenSource, err := NewTokenSource(ctx, httpClient, cfg.ClientID, assertion, cfg.Scope, tokenOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("create token source: %w", err)
+	}
+
+	clientOpts := make([]ClientOption, 0, len(opts)+2)
+	clientOpts = append(clientOpts, WithSnapshotCacheIdentity(cfg.ClientID))
+	if cfg.RetryPolicy != (RetryPolicy{}) {
+		clientOpts = append(clientOpts, WithRetryPolicy(cfg.RetryPolicy))
+	}
+	clientOpts = append(clientOpts, opts...)
+
+	return NewClientWithBaseURL(httpClient, tokenSource, cfg.BaseURL, clientOpts...)
+}
@@ -0,0 +1,390 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-json-experiment/json"
+	"github.com/google/go-cmp/cmp"
+)
+
+// testPrivateKeyPEM returns a PEM-encoded P-256 private key.
+func testPrivateKeyPEM(t *testing.T) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate P-256 key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal P-256 PKCS8 key: %v", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+func TestNewClientFromConfig(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	privateKey := testPrivateKeyPEM(t)
+	keyPath := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(keyPath, []byte(privateKey), 0o600); err != nil {
+		t.Fatalf("write key file: %v", err)
+	}
+
+	tests := map[string]struct {
+		privateKey     string
+		privateKeyPath string
+	}{
+		"success: inline private key": {
+			privateKey: privateKey,
+		},
+		"success: private key path": {
+			privateKeyPath: keyPath,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			tokenForm := make(chan map[string]string, 1)
+			tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseForm(); err != nil {
+					t.Errorf("parse token form: %v", err)
+				}
+				tokenForm <- map[string]string{
+					"client_id":             r.PostForm.Get("client_id"),
+					"client_assertion_type": r.PostForm.Get("client_assertion_type"),
+					"scope":                 r.PostForm.Get("scope"),
+				}
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"access_token":"config-token","token_type":"Bearer","expires_in":3600}`)
+			}))
+			t.Cleanup(tokenServer.Close)
+
+			apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Authorization"); got != "Bearer config-token" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"data":{"id":"device-1","type":"orgDevices"},"links":{"self":"https://api-business.apple.com/v1/orgDevices/device-1"}}`)
+			}))
+			t.Cleanup(apiServer.Close)
+
+			client, err := NewClientFromConfig(ctx, Config{
+				ClientID:       "BUSINESSAPI.client",
+				KeyID:          "key-1",
+				PrivateKey:     tt.privateKey,
+				PrivateKeyPath: tt.privateKeyPath,
+				BaseURL:        apiServer.URL,
+				TokenURL:       tokenServer.URL + "/auth/oauth2/token",
+				RequestTimeout: 5 * time.Second,
+				RetryPolicy:    RetryPolicy{MaxRetries: 2},
+			})
+			if err != nil {
+				t.Fatalf("NewClientFromConfig returned error: %v", err)
+			}
+
+			resp, err := client.GetOrgDevice(ctx, "device-1", nil)
+			if err != nil {
+				t.Fatalf("GetOrgDevice returned error: %v", err)
+			}
+			if diff := cmp.Diff("device-1", resp.Data.ID); diff != "" {
+				t.Fatalf("device id mismatch (-want +got):\n%s", diff)
+			}
+
+			wantForm := map[string]string{
+				"client_id":             "BUSINESSAPI.client",
+				"client_assertion_type": ClientAssertionURI,
+				"scope":                 ScopeBusinessAPI,
+			}
+			if diff := cmp.Diff(wantForm, <-tokenForm); diff != "" {
+				t.Fatalf("token form mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(5*time.Second, client.requestTimeout); diff != "" {
+				t.Fatalf("request timeout mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(RetryPolicy{MaxRetries: 2}, client.retryPolicy); diff != "" {
+				t.Fatalf("retry policy mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewClientFromConfigDefaults(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	client, err := NewClientFromConfig(ctx, Config{
+		ClientID:   "BUSINESSAPI.client",
+		KeyID:      "key-1",
+		PrivateKey: testPrivateKeyPEM(t),
+	})
+	if err != nil {
+		t.Fatalf("NewClientFromConfig returned error: %v", err)
+	}
+
+	if diff := cmp.Diff(DefaultAPIBaseURL, client.baseURL.String()); diff != "" {
+		t.Fatalf("base URL mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(time.Duration(0), client.requestTimeout); diff != "" {
+		t.Fatalf("request timeout mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(RetryPolicy{}, client.retryPolicy); diff != "" {
+		t.Fatalf("retry policy mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(ServiceFamilyBusiness, client.serviceFamily); diff != "" {
+		t.Fatalf("service family mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestConfig_JSON(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		cfg  Config
+		want string
+	}{
+		"success: durations and retry policy": {
+			cfg: Config{
+				ClientID:       "BUSINESSAPI.client",
+				KeyID:          "key-1",
+				RequestTimeout: 30 * time.Second,
+				RetryPolicy: RetryPolicy{
+					MaxRetries: 3,
+					BaseDelay:  500 * time.Millisecond,
+					MaxDelay:   10 * time.Second,
+				},
+			},
+			want: `{"clientId":"BUSINESSAPI.client","keyId":"key-1","requestTimeout":"30s","retryPolicy":{"maxRetries":3,"baseDelay":"500ms","maxDelay":"10s"}}`,
+		},
+		"success: optional fields omitted": {
+			cfg: Config{
+				ClientID: "BUSINESSAPI.client",
+				KeyID:    "key-1",
+			},
+			want: `{"clientId":"BUSINESSAPI.client","keyId":"key-1"}`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			data, err := json.Marshal(tt.cfg)
+			if err != nil {
+				t.Fatalf("Marshal returned error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, string(data)); diff != "" {
+				t.Fatalf("JSON mismatch (-want +got):\n%s", diff)
+			}
+
+			var got Config
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("Unmarshal returned error: %v", err)
+			}
+			if diff := cmp.Diff(tt.cfg, got); diff != "" {
+				t.Fatalf("round-tripped config mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConfig_UnmarshalJSON(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		data        string
+		want        Config
+		wantErrText string
+	}{
+		"success: compound duration strings": {
+			data: `{"clientId":"BUSINESSAPI.client","requestTimeout":"1m30s","retryPolicy":{"maxRetries":2,"maxDelay":"1.5s"}}`,
+			want: Config{
+				ClientID:       "BUSINESSAPI.client",
+				RequestTimeout: 90 * time.Second,
+				RetryPolicy:    RetryPolicy{MaxRetries: 2, MaxDelay: 1500 * time.Millisecond},
+			},
+		},
+		"error: duration as a number": {
+			data:        `{"requestTimeout":30}`,
+			wantErrText: `duration must be a string such as "30s"`,
+		},
+		"error: malformed duration string": {
+			data:        `{"retryPolicy":{"baseDelay":"soon"}}`,
+			wantErrText: `invalid duration "soon"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			var got Config
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if tt.wantErrText != "" {
+				if err == nil {
+					t.Fatal("Unmarshal returned nil error")
+				}
+				if !strings.Contains(err.Error(), tt.wantErrText) {
+					t.Fatalf("Unmarshal error %q does not contain %q", err, tt.wantErrText)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal returned error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("config mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	privateKey := testPrivateKeyPEM(t)
+	valid := Config{ClientID: "BUSINESSAPI.client", KeyID: "key-1", PrivateKey: privateKey}
+
+	tests := map[string]struct {
+		modify       func(*Config)
+		wantErrTexts []string
+	}{
+		"success: minimal config": {
+			modify: func(*Config) {},
+		},
+		"error: missing client id": {
+			modify:       func(c *Config) { c.ClientID = " " },
+			wantErrTexts: []string{"client ID is required"},
+		},
+		"error: missing key id": {
+			modify:       func(c *Config) { c.KeyID = "" },
+			wantErrTexts: []string{"key ID is required"},
+		},
+		"error: both private key sources": {
+			modify:       func(c *Config) { c.PrivateKeyPath = "/etc/abm/key.pem" },
+			wantErrTexts: []string{"private key and private key path are mutually exclusive"},
+		},
+		"error: no private key source": {
+			modify:       func(c *Config) { c.PrivateKey = "" },
+			wantErrTexts: []string{"one of private key or private key path is required"},
+		},
+		"error: missing private key file": {
+			modify: func(c *Config) {
+				c.PrivateKey = ""
+				c.PrivateKeyPath = filepath.Join(t.TempDir(), "missing.pem")
+			},
+			wantErrTexts: []string{"read private key"},
+		},
+		"error: malformed private key": {
+			modify:       func(c *Config) { c.PrivateKey = "not a pem" },
+			wantErrTexts: []string{"parse private key"},
+		},
+		"error: relative base url": {
+			modify:       func(c *Config) { c.BaseURL = "/v1/" },
+			wantErrTexts: []string{"base URL must be absolute"},
+		},
+		"error: relative token url": {
+			modify:       func(c *Config) { c.TokenURL = "auth/oauth2/token" },
+			wantErrTexts: []string{"token URL must be absolute"},
+		},
+		"error: multiple scopes": {
+			modify:       func(c *Config) { c.Scope = "business.api school.api" },
+			wantErrTexts: []string{"scope must be a single scope"},
+		},
+		"error: negative request timeout": {
+			modify:       func(c *Config) { c.RequestTimeout = -time.Second },
+			wantErrTexts: []string{"request timeout must not be negative"},
+		},
+		"error: negative max retries": {
+			modify:       func(c *Config) { c.RetryPolicy.MaxRetries = -1 },
+			wantErrTexts: []string{"retry policy max retries must not be negative"},
+		},
+		"error: all problems are reported together": {
+			modify: func(c *Config) {
+				*c = Config{RequestTimeout: -time.Second}
+			},
+			wantErrTexts: []string{"client ID is required", "key ID is required", "one of private key or private key path is required", "request timeout must not be negative"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			cfg := valid
+			tt.modify(&cfg)
+			err := cfg.Validate()
+			if len(tt.wantErrTexts) == 0 {
+				if err != nil {
+					t.Fatalf("Validate returned error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate returned nil error, want %q", tt.wantErrTexts)
+			}
+			for _, want := range tt.wantErrTexts {
+				if !strings.Contains(err.Error(), want) {
+					t.Fatalf("Validate error = %v, want containing %q", err, want)
+				}
+			}
+
+			if _, err := NewClientFromConfig(ctx, cfg); err == nil || !strings.Contains(err.Error(), "invalid config") {
+				t.Fatalf("NewClientFromConfig error = %v, want invalid config error", err)
+			}
+		})
+	}
+}
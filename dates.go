@@ -0,0 +1,89 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import "time"
+
+// DateField names a date-time attribute of an organization device, by its
+// JSON name, for date-based reporting such as [GroupDevicesByMonth].
+type DateField string
+
+const (
+	DateFieldOrderDateTime      DateField = "orderDateTime"
+	DateFieldAddedToOrgDateTime DateField = "addedToOrgDateTime"
+	DateFieldUpdatedDateTime    DateField = "updatedDateTime"
+)
+
+// unknownDateBucket is the [GroupDevicesByMonth] key for devices without the date.
+const unknownDateBucket = "unknown"
+
+// dayIn returns midnight of the calendar day t falls on in loc, or the zero
+// time when t is zero. A nil loc means UTC. The day is taken in loc itself,
+// not by truncating the UTC time, so days that are 23 or 25 hours long
+// around a daylight saving change are handled.
+func dayIn(t time.Time, loc *time.Location) time.Time {
+	if t.IsZero() {
+		return time.Time{}
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	year, month, day := t.In(loc).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+// OrderDate returns the day the device was ordered in loc, as midnight of
+// that day in loc, or the zero time when the order date is unknown. A nil loc
+// means UTC. Reports by local date should use it instead of OrderDateTime,
+// which is in UTC and may fall on the previous or next day in loc.
+func (a *OrgDeviceAttributes) OrderDate(loc *time.Location) time.Time {
+	if a == nil {
+		return time.Time{}
+	}
+
+	return dayIn(a.OrderDateTime, loc)
+}
+
+// EndDate returns the day the coverage ends in loc, as midnight of that day
+// in loc, or the zero time when the end date is unknown. A nil loc means UTC.
+func (a *AppleCareCoverageAttributes) EndDate(loc *time.Location) time.Time {
+	if a == nil {
+		return time.Time{}
+	}
+
+	return dayIn(a.EndDateTime, loc)
+}
+
+// dateTime returns the date-time attribute of a named by field, or the zero
+// time when a is nil or field is not a declared [DateField].
+func (a *OrgDeviceAttributes) dateTime(field DateField) time.Time {
+	if a == nil {
+		return time.Time{}
+	}
+
+	switch field {
+	case DateFieldOrderDateTime:
+		return a.OrderDateTime
+	case DateFieldAddedToOrgDateTime:
+		return a.AddedToOrgDateTime
+	case DateFieldUpdatedDateTime:
+		return a.UpdatedDateTime
+	default:
+		return time.Time{}
+	}
+}
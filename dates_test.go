@@ -0,0 +1,100 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDateHelpers(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skipf("load time zone: %v", err)
+	}
+
+	tests := map[string]struct {
+		at   time.Time
+		loc  *time.Location
+		want time.Time
+	}{
+		"success: early UTC morning is the previous day in Los Angeles": {
+			at:   time.Date(2025, time.March, 1, 3, 0, 0, 0, time.UTC),
+			loc:  la,
+			want: time.Date(2025, time.February, 28, 0, 0, 0, 0, la),
+		},
+		"success: same instant is its own day in UTC": {
+			at:   time.Date(2025, time.March, 1, 3, 0, 0, 0, time.UTC),
+			loc:  time.UTC,
+			want: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+		},
+		"success: nil location means UTC": {
+			at:   time.Date(2025, time.March, 1, 3, 0, 0, 0, time.UTC),
+			loc:  nil,
+			want: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+		},
+		"success: day of a daylight saving change": {
+			at:   time.Date(2025, time.March, 9, 20, 0, 0, 0, time.UTC),
+			loc:  la,
+			want: time.Date(2025, time.March, 9, 0, 0, 0, 0, la),
+		},
+		"success: zero time stays zero": {
+			at:   time.Time{},
+			loc:  la,
+			want: time.Time{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			device := &OrgDeviceAttributes{OrderDateTime: tt.at}
+			if diff := cmp.Diff(tt.want, device.OrderDate(tt.loc)); diff != "" {
+				t.Fatalf("OrderDate mismatch (-want +got):\n%s", diff)
+			}
+			coverage := &AppleCareCoverageAttributes{EndDateTime: tt.at}
+			if diff := cmp.Diff(tt.want, coverage.EndDate(tt.loc)); diff != "" {
+				t.Fatalf("EndDate mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("success: nil receivers", func(t *testing.T) {
+		ctx := t.Context()
+		if err := ctx.Err(); err != nil {
+			t.Fatalf("context error: %v", err)
+		}
+
+		if got := (*OrgDeviceAttributes)(nil).OrderDate(la); !got.IsZero() {
+			t.Fatalf("OrderDate = %v, want zero", got)
+		}
+		if got := (*AppleCareCoverageAttributes)(nil).EndDate(la); !got.IsZero() {
+			t.Fatalf("EndDate = %v, want zero", got)
+		}
+	})
+}
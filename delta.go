@@ -0,0 +1,73 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"context"
+	"iter"
+	"slices"
+	"time"
+)
+
+// OrgDevicesUpdatedSince yields the organization devices whose update time is
+// after since, following pagination until all pages are consumed. It is meant
+// for incremental syncs that keep the latest update time seen as a checkpoint.
+//
+// The Apple Business Manager API documents neither a filter nor a sort order
+// on the update time, so every page is still fetched and the devices are
+// filtered on the client. When options.Fields is set, "updatedDateTime" is
+// requested in addition so the filter can be applied. Devices without an
+// update time are skipped.
+func (c *Client) OrgDevicesUpdatedSince(ctx context.Context, since time.Time, options *GetOrgDevicesOptions) iter.Seq2[OrgDevice, error] {
+	return func(yield func(OrgDevice, error) bool) {
+		var fields []string
+		var limit int
+		if options != nil {
+			fields = options.Fields
+			limit = options.Limit
+		}
+		if len(fields) > 0 && !slices.Contains(fields, "updatedDateTime") {
+			fields = append(slices.Clip(fields), "updatedDateTime")
+		}
+
+		query, err := buildFieldsAndLimitQuery("fields[orgDevices]", fields, c.crawlLimit(limit))
+		if err != nil {
+			yield(OrgDevice{}, err)
+			return
+		}
+		if err := setOrgDevicesFilterQuery(query, options); err != nil {
+			yield(OrgDevice{}, err)
+			return
+		}
+
+		for page, err := range crawlPages(ctx, c, orgDevicesPath, query, func(r *OrgDevicesResponse) string { return r.Links.Next }) {
+			if err != nil {
+				yield(OrgDevice{}, err)
+				return
+			}
+
+			for _, device := range page.Data {
+				if device.Attributes == nil || !device.Attributes.UpdatedDateTime.After(since) {
+					continue
+				}
+				if !yield(device, nil) {
+					return
+				}
+			}
+		}
+	}
+}
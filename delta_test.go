@@ -0,0 +1,140 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClient_OrgDevicesUpdatedSince(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	pages := map[string]string{
+		"": `{"data":[` +
+			`{"id":"old","attributes":{"updatedDateTime":"2026-01-01T00:00:00Z"}},` +
+			`{"id":"new-1","attributes":{"updatedDateTime":"2026-03-01T00:00:00Z"}},` +
+			`{"id":"no-attributes"}` +
+			`],"links":{"next":"/v1/orgDevices?page=2"}}`,
+		"2": `{"data":[` +
+			`{"id":"at-checkpoint","attributes":{"updatedDateTime":"2026-02-01T00:00:00Z"}},` +
+			`{"id":"never-updated","attributes":{}},` +
+			`{"id":"new-2","attributes":{"updatedDateTime":"2026-02-01T00:00:01Z"}}` +
+			`]}`,
+	}
+	since := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		options     *GetOrgDevicesOptions
+		stopAfter   int
+		wantIDs     []string
+		wantFields  string
+		wantQueries int
+		wantErr     bool
+	}{
+		"success: all pages filtered client-side": {
+			wantIDs:     []string{"new-1", "new-2"},
+			wantQueries: 2,
+		},
+		"success: update time added to requested fields": {
+			options:     &GetOrgDevicesOptions{Fields: []string{"serialNumber"}, Limit: 3},
+			wantIDs:     []string{"new-1", "new-2"},
+			wantFields:  "serialNumber,updatedDateTime",
+			wantQueries: 2,
+		},
+		"success: early break stops crawl": {
+			stopAfter:   1,
+			wantIDs:     []string{"new-1"},
+			wantQueries: 1,
+		},
+		"error: invalid limit": {
+			options: &GetOrgDevicesOptions{Limit: -1},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			var (
+				mu     sync.Mutex
+				fields []string
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				fields = append(fields, r.URL.Query().Get("fields[orgDevices]"))
+				mu.Unlock()
+
+				body, ok := pages[r.URL.Query().Get("page")]
+				if !ok {
+					w.WriteHeader(http.StatusBadRequest)
+					fmt.Fprintf(w, `{"error":"unexpected query: %s"}`, r.URL.RawQuery)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, body)
+			}))
+			t.Cleanup(server.Close)
+			client := testClientForServer(t, server)
+
+			var gotIDs []string
+			var gotErr error
+			for device, err := range client.OrgDevicesUpdatedSince(ctx, since, tt.options) {
+				if err != nil {
+					gotErr = err
+					break
+				}
+				gotIDs = append(gotIDs, device.ID)
+				if len(gotIDs) == tt.stopAfter {
+					break
+				}
+			}
+
+			if (gotErr != nil) != tt.wantErr {
+				t.Fatalf("OrgDevicesUpdatedSince error = %v, wantErr %t", gotErr, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantIDs, gotIDs); diff != "" {
+				t.Fatalf("device IDs mismatch (-want +got):\n%s", diff)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if diff := cmp.Diff(tt.wantQueries, len(fields)); diff != "" {
+				t.Fatalf("request count mismatch (-want +got):\n%s", diff)
+			}
+			// Later pages follow the server's next link verbatim.
+			if len(fields) > 0 {
+				if diff := cmp.Diff(tt.wantFields, fields[0]); diff != "" {
+					t.Fatalf("fields query mismatch (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FullProductDescription returns the device model followed by its capacity,
+// such as "iPhone 15 Pro (256GB)". The capacity is omitted when empty.
+// It returns an empty string when a is nil or the model is empty.
+func (a *OrgDeviceAttributes) FullProductDescription() string {
+	if a == nil || a.DeviceModel == "" {
+		return ""
+	}
+	if a.DeviceCapacity == "" {
+		return a.DeviceModel
+	}
+
+	return a.DeviceModel + " (" + a.DeviceCapacity + ")"
+}
+
+// SummaryLine returns a concise single-line description of the device
+// suitable for log lines, such as
+// "SN:ABC123 Model:iPhone 15 Pro (256GB) Status:ASSIGNED".
+// Empty fields are omitted. It returns an empty string when a is nil.
+func (a *OrgDeviceAttributes) SummaryLine() string {
+	if a == nil {
+		return ""
+	}
+
+	parts := make([]string, 0, 3)
+	if a.SerialNumber != "" {
+		parts = append(parts, "SN:"+a.SerialNumber)
+	}
+	if model := a.FullProductDescription(); model != "" {
+		parts = append(parts, "Model:"+model)
+	}
+	if a.Status != "" {
+		parts = append(parts, "Status:"+a.Status.String())
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// DeviceCapacityGB parses the device capacity, such as "256GB" or "1TB",
+// into gigabytes. Units are case-insensitive and may be separated from the
+// number by spaces; one terabyte is 1024 gigabytes.
+func (a *OrgDeviceAttributes) DeviceCapacityGB() (int, error) {
+	if a == nil {
+		return 0, fmt.Errorf("device capacity is not set")
+	}
+
+	capacity := strings.ToUpper(strings.TrimSpace(a.DeviceCapacity))
+	multiplier := 1
+	switch {
+	case strings.HasSuffix(capacity, "TB"):
+		multiplier = 1024
+		capacity = strings.TrimSuffix(capacity, "TB")
+	case strings.HasSuffix(capacity, "GB"):
+		capacity = strings.TrimSuffix(capacity, "GB")
+	default:
+		return 0, fmt.Errorf("invalid device capacity %q", a.DeviceCapacity)
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(capacity))
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid device capacity %q", a.DeviceCapacity)
+	}
+
+	return n * multiplier, nil
+}
+
+// IsCapacity reports whether the device capacity is gb gigabytes.
+// It returns false when a is nil or the capacity cannot be parsed.
+func (a *OrgDeviceAttributes) IsCapacity(gb int) bool {
+	capacity, err := a.DeviceCapacityGB()
+	return err == nil && capacity == gb
+}
+
+// IsProductType reports whether the device product type, such as "iPhone16,2",
+// equals productType, ignoring case. It returns false when a is nil or
+// productType is empty.
+func (a *OrgDeviceAttributes) IsProductType(productType string) bool {
+	if a == nil || productType == "" {
+		return false
+	}
+
+	return strings.EqualFold(a.ProductType, productType)
+}
+
+// WasReleasedFromOrg reports whether the device has been released from the
+// organization, that is, its release time is set and in the past. A release
+// time in the future is a scheduled release and does not count yet. It
+// returns false when a is nil. [OrgDevice.IsReleased] applies the same rule
+// to a device.
+func (a *OrgDeviceAttributes) WasReleasedFromOrg() bool {
+	if a == nil {
+		return false
+	}
+	released := a.ReleasedFromOrgDateTime
+
+	return !released.IsZero() && released.Before(time.Now())
+}
+
+// PartNumber returns the device's part number, or "" when d or its attributes are nil.
+func (d *OrgDevice) PartNumber() string {
+	if d == nil || d.Attributes == nil {
+		return ""
+	}
+
+	return d.Attributes.PartNumber
+}
+
+// SerialNumber returns the device's serial number, or "" when d or its attributes are nil.
+func (d *OrgDevice) SerialNumber() string {
+	if d == nil || d.Attributes == nil {
+		return ""
+	}
+
+	return d.Attributes.SerialNumber
+}
+
+// Status returns the device's assignment status, or "" when d or its attributes are nil.
+func (d *OrgDevice) Status() OrgDeviceAttributesStatus {
+	if d == nil || d.Attributes == nil {
+		return ""
+	}
+
+	return d.Attributes.Status
+}
+
+// IsReleased reports whether the device has been released from the
+// organization, as [OrgDeviceAttributes.WasReleasedFromOrg] does for its
+// attributes. It returns false when d or its attributes are nil.
+func (d *OrgDevice) IsReleased() bool {
+	if d == nil {
+		return false
+	}
+
+	return d.Attributes.WasReleasedFromOrg()
+}
+
+// TenureDays returns the number of whole days the device has been in the
+// organization as of at, counted from its added time up to at or its release
+// time, whichever is earlier. It returns 0 when d or its attributes are nil,
+// the added time is unset, or at precedes the added time.
+func (d *OrgDevice) TenureDays(at time.Time) int {
+	if d == nil || d.Attributes == nil || d.Attributes.AddedToOrgDateTime.IsZero() {
+		return 0
+	}
+
+	end := at
+	if released := d.Attributes.ReleasedFromOrgDateTime; !released.IsZero() && released.Before(end) {
+		end = released
+	}
+	tenure := end.Sub(d.Attributes.AddedToOrgDateTime)
+	if tenure <= 0 {
+		return 0
+	}
+
+	return int(tenure / (24 * time.Hour))
+}
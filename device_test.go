@@ -0,0 +1,488 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOrgDeviceAttributes_FullProductDescription(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		attributes *OrgDeviceAttributes
+		want       string
+	}{
+		"success: model and capacity": {
+			attributes: &OrgDeviceAttributes{
+				DeviceModel:    "iPhone 15 Pro",
+				DeviceCapacity: "256GB",
+			},
+			want: "iPhone 15 Pro (256GB)",
+		},
+		"success: only model": {
+			attributes: &OrgDeviceAttributes{
+				DeviceModel: "iPhone 15 Pro",
+			},
+			want: "iPhone 15 Pro",
+		},
+		"success: only capacity": {
+			attributes: &OrgDeviceAttributes{
+				DeviceCapacity: "256GB",
+			},
+			want: "",
+		},
+		"success: nil attributes": {
+			attributes: nil,
+			want:       "",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, tt.attributes.FullProductDescription()); diff != "" {
+				t.Fatalf("FullProductDescription mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOrgDeviceAttributes_SummaryLine(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		attributes *OrgDeviceAttributes
+		want       string
+	}{
+		"success: fully populated": {
+			attributes: &OrgDeviceAttributes{
+				AddedToOrgDateTime:  time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+				Color:               "BLACK",
+				DeviceCapacity:      "256GB",
+				DeviceModel:         "iPhone 15 Pro",
+				IMEI:                []string{"356789012345678"},
+				WifiMacAddress:      []string{"AA:BB:CC:DD:EE:FF"},
+				BluetoothMacAddress: []string{"AA:BB:CC:DD:EE:00"},
+				OrderNumber:         "ORDER-1",
+				PartNumber:          "MTV43LL/A",
+				ProductFamily:       ProductFamilyIPhone,
+				ProductType:         "iPhone16,1",
+				PurchaseSourceType:  PurchaseSourceTypeApple,
+				PurchaseSourceID:    "1234",
+				SerialNumber:        "ABC123",
+				Status:              StatusAssigned,
+			},
+			want: "SN:ABC123 Model:iPhone 15 Pro (256GB) Status:ASSIGNED",
+		},
+		"success: missing model": {
+			attributes: &OrgDeviceAttributes{
+				SerialNumber: "ABC123",
+				Status:       StatusUnAssigned,
+			},
+			want: "SN:ABC123 Status:UNASSIGNED",
+		},
+		"success: empty attributes": {
+			attributes: &OrgDeviceAttributes{},
+			want:       "",
+		},
+		"success: nil attributes": {
+			attributes: nil,
+			want:       "",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, tt.attributes.SummaryLine()); diff != "" {
+				t.Fatalf("SummaryLine mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOrgDevice_IsReleased(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	now := time.Now()
+
+	tests := map[string]struct {
+		device *OrgDevice
+		want   bool
+	}{
+		"success: released in the past": {
+			device: &OrgDevice{Attributes: &OrgDeviceAttributes{ReleasedFromOrgDateTime: now.Add(-time.Hour)}},
+			want:   true,
+		},
+		"success: release scheduled in the future": {
+			device: &OrgDevice{Attributes: &OrgDeviceAttributes{ReleasedFromOrgDateTime: now.Add(time.Hour)}},
+			want:   false,
+		},
+		"success: release time unset": {
+			device: &OrgDevice{Attributes: &OrgDeviceAttributes{AddedToOrgDateTime: now.Add(-time.Hour)}},
+			want:   false,
+		},
+		"success: nil attributes": {
+			device: &OrgDevice{},
+			want:   false,
+		},
+		"success: nil device": {
+			device: nil,
+			want:   false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, tt.device.IsReleased()); diff != "" {
+				t.Fatalf("IsReleased mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOrgDevice_TenureDays(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	added := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := time.Date(2025, 3, 1, 11, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		device *OrgDevice
+		want   int
+	}{
+		"success: still in org": {
+			device: &OrgDevice{Attributes: &OrgDeviceAttributes{AddedToOrgDateTime: added}},
+			want:   58,
+		},
+		"success: released before at": {
+			device: &OrgDevice{Attributes: &OrgDeviceAttributes{
+				AddedToOrgDateTime:      added,
+				ReleasedFromOrgDateTime: added.Add(10 * 24 * time.Hour),
+			}},
+			want: 10,
+		},
+		"success: released after at": {
+			device: &OrgDevice{Attributes: &OrgDeviceAttributes{
+				AddedToOrgDateTime:      added,
+				ReleasedFromOrgDateTime: at.Add(24 * time.Hour),
+			}},
+			want: 58,
+		},
+		"success: at before added": {
+			device: &OrgDevice{Attributes: &OrgDeviceAttributes{AddedToOrgDateTime: at.Add(time.Hour)}},
+			want:   0,
+		},
+		"success: added time unset": {
+			device: &OrgDevice{Attributes: &OrgDeviceAttributes{}},
+			want:   0,
+		},
+		"success: nil attributes": {
+			device: &OrgDevice{},
+			want:   0,
+		},
+		"success: nil device": {
+			device: nil,
+			want:   0,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, tt.device.TenureDays(at)); diff != "" {
+				t.Fatalf("TenureDays mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOrgDeviceAttributes_DeviceCapacityGB(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		attributes *OrgDeviceAttributes
+		want       int
+		wantErr    bool
+	}{
+		"success: gigabytes": {
+			attributes: &OrgDeviceAttributes{DeviceCapacity: "256GB"},
+			want:       256,
+		},
+		"success: terabytes": {
+			attributes: &OrgDeviceAttributes{DeviceCapacity: "1TB"},
+			want:       1024,
+		},
+		"success: lower case with space": {
+			attributes: &OrgDeviceAttributes{DeviceCapacity: " 512 gb "},
+			want:       512,
+		},
+		"error: missing unit": {
+			attributes: &OrgDeviceAttributes{DeviceCapacity: "256"},
+			wantErr:    true,
+		},
+		"error: malformed number": {
+			attributes: &OrgDeviceAttributes{DeviceCapacity: "lotsGB"},
+			wantErr:    true,
+		},
+		"error: zero": {
+			attributes: &OrgDeviceAttributes{DeviceCapacity: "0GB"},
+			wantErr:    true,
+		},
+		"error: empty": {
+			attributes: &OrgDeviceAttributes{},
+			wantErr:    true,
+		},
+		"error: nil attributes": {
+			attributes: nil,
+			wantErr:    true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			got, err := tt.attributes.DeviceCapacityGB()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DeviceCapacityGB error mismatch: err=%v wantErr=%v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("DeviceCapacityGB mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOrgDeviceAttributes_IsCapacity(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		attributes *OrgDeviceAttributes
+		gb         int
+		want       bool
+	}{
+		"success: matching capacity": {
+			attributes: &OrgDeviceAttributes{DeviceCapacity: "256GB"},
+			gb:         256,
+			want:       true,
+		},
+		"success: different capacity": {
+			attributes: &OrgDeviceAttributes{DeviceCapacity: "128GB"},
+			gb:         256,
+			want:       false,
+		},
+		"success: malformed capacity": {
+			attributes: &OrgDeviceAttributes{DeviceCapacity: "256 gigs"},
+			gb:         256,
+			want:       false,
+		},
+		"success: nil attributes": {
+			attributes: nil,
+			gb:         256,
+			want:       false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, tt.attributes.IsCapacity(tt.gb)); diff != "" {
+				t.Fatalf("IsCapacity mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOrgDeviceAttributes_IsProductType(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		attributes  *OrgDeviceAttributes
+		productType string
+		want        bool
+	}{
+		"success: case-insensitive match": {
+			attributes:  &OrgDeviceAttributes{ProductType: "iPhone16,2"},
+			productType: "iphone16,2",
+			want:        true,
+		},
+		"success: different product type": {
+			attributes:  &OrgDeviceAttributes{ProductType: "iPhone16,2"},
+			productType: "iPhone15,3",
+			want:        false,
+		},
+		"success: empty product type": {
+			attributes:  &OrgDeviceAttributes{},
+			productType: "",
+			want:        false,
+		},
+		"success: nil attributes": {
+			attributes:  nil,
+			productType: "iPhone16,2",
+			want:        false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, tt.attributes.IsProductType(tt.productType)); diff != "" {
+				t.Fatalf("IsProductType mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOrgDeviceAttributes_WasReleasedFromOrg(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		attributes *OrgDeviceAttributes
+		want       bool
+	}{
+		"success: released": {
+			attributes: &OrgDeviceAttributes{ReleasedFromOrgDateTime: time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)},
+			want:       true,
+		},
+		"success: release scheduled in the future": {
+			attributes: &OrgDeviceAttributes{ReleasedFromOrgDateTime: time.Now().Add(24 * time.Hour)},
+			want:       false,
+		},
+		"success: not released": {
+			attributes: &OrgDeviceAttributes{Status: StatusAssigned},
+			want:       false,
+		},
+		"success: nil attributes": {
+			attributes: nil,
+			want:       false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, tt.attributes.WasReleasedFromOrg()); diff != "" {
+				t.Fatalf("WasReleasedFromOrg mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOrgDevice_Accessors(t *testing.T) {
+	type accessors struct {
+		PartNumber   string
+		SerialNumber string
+		Status       OrgDeviceAttributesStatus
+	}
+
+	tests := map[string]struct {
+		device *OrgDevice
+		want   accessors
+	}{
+		"success: attributes set": {
+			device: &OrgDevice{Attributes: &OrgDeviceAttributes{PartNumber: "MX2E3LL/A", SerialNumber: "C02XK1JHJG5J", Status: StatusAssigned}},
+			want:   accessors{PartNumber: "MX2E3LL/A", SerialNumber: "C02XK1JHJG5J", Status: StatusAssigned},
+		},
+		"success: nil attributes": {
+			device: &OrgDevice{ID: "device-1"},
+			want:   accessors{},
+		},
+		"success: nil device": {
+			device: nil,
+			want:   accessors{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			got := accessors{
+				PartNumber:   tt.device.PartNumber(),
+				SerialNumber: tt.device.SerialNumber(),
+				Status:       tt.device.Status(),
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("accessors mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldChange describes a field whose value differs between two versions of
+// a resource, as reported by [DiffOrgDeviceAttributes] and [DiffOrgDevices].
+type FieldChange struct {
+	// Field is the field's JSON name, such as "deviceCapacity". Nested
+	// fields are joined with dots, such as
+	// "relationships.assignedServer.links.related".
+	Field string
+
+	// Old and New are the rendered values; an absent value renders as "".
+	// Times are rendered in RFC 3339 and lists joined with ",".
+	Old string
+	New string
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// DiffOrgDeviceAttributes returns the attributes that differ between old and
+// new, in declaration order, for example to record
+// "deviceCapacity: 128GB -> 256GB" in an audit log. A nil old reports every
+// set attribute of new as added, and a nil new every set attribute of old as
+// removed.
+//
+// Times are compared with [time.Time.Equal], so the same instant in another
+// time zone is not a change. The identifier lists, IMEI, MEID, and the MAC
+// addresses, are compared as sets and rendered sorted: Apple does not
+// guarantee their order, so a reordering alone is not reported.
+func DiffOrgDeviceAttributes(old, new *OrgDeviceAttributes) []FieldChange {
+	return diffStruct(nil, "", reflect.ValueOf(old), reflect.ValueOf(new))
+}
+
+// DiffOrgDevices is like [DiffOrgDeviceAttributes] for whole devices: it also
+// reports changes to the relationships, with fields prefixed by
+// "relationships.". A device resource carries only relationship links, not
+// the assigned server itself; [DiffDeviceSnapshots] reports assignment changes.
+func DiffOrgDevices(old, new *OrgDevice) []FieldChange {
+	var oldAttributes, newAttributes *OrgDeviceAttributes
+	var oldRelationships, newRelationships *OrgDeviceRelationships
+	if old != nil {
+		oldAttributes, oldRelationships = old.Attributes, old.Relationships
+	}
+	if new != nil {
+		newAttributes, newRelationships = new.Attributes, new.Relationships
+	}
+
+	changes := diffStruct(nil, "", reflect.ValueOf(oldAttributes), reflect.ValueOf(newAttributes))
+	return diffStruct(changes, "relationships.", reflect.ValueOf(oldRelationships), reflect.ValueOf(newRelationships))
+}
+
+// diffStruct appends to changes the fields that differ between the structs
+// old and new point to, naming them by JSON name after prefix. A nil pointer
+// compares as the zero struct. Nested structs other than times are diffed
+// field by field.
+func diffStruct(changes []FieldChange, prefix string, old, new reflect.Value) []FieldChange {
+	typ := old.Type().Elem()
+	oldStruct, newStruct := reflect.Zero(typ), reflect.Zero(typ)
+	if !old.IsNil() {
+		oldStruct = old.Elem()
+	}
+	if !new.IsNil() {
+		newStruct = new.Elem()
+	}
+
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		name = prefix + name
+		oldValue, newValue := oldStruct.Field(i), newStruct.Field(i)
+
+		if t := field.Type; t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Struct && t.Elem() != timeType {
+			changes = diffStruct(changes, name+".", oldValue, newValue)
+			continue
+		}
+		if fieldValuesEqual(oldValue, newValue) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: name, Old: renderFieldValue(oldValue), New: renderFieldValue(newValue)})
+	}
+
+	return changes
+}
+
+// fieldValuesEqual reports whether the field values a and b are equal: times
+// by instant, pointers by their targets, and everything else, including
+// string lists as sets, by rendered value.
+func fieldValuesEqual(a, b reflect.Value) bool {
+	if a.Kind() == reflect.Pointer {
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return fieldValuesEqual(a.Elem(), b.Elem())
+	}
+
+	if a.Type() == timeType {
+		return a.Interface().(time.Time).Equal(b.Interface().(time.Time))
+	}
+	return renderFieldValue(a) == renderFieldValue(b)
+}
+
+// renderFieldValue renders a field value for a [FieldChange].
+func renderFieldValue(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Type() == timeType:
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	case v.Kind() == reflect.String:
+		return v.String()
+	case v.Kind() == reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		values := make([]string, v.Len())
+		for i := range values {
+			values[i] = v.Index(i).String()
+		}
+		slices.Sort(values)
+		return strings.Join(values, ",")
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
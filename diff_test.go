@@ -0,0 +1,246 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiffOrgDeviceAttributes(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	added := time.Date(2025, time.March, 1, 9, 30, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		old  *OrgDeviceAttributes
+		new  *OrgDeviceAttributes
+		want []FieldChange
+	}{
+		"success: string change": {
+			old:  &OrgDeviceAttributes{DeviceCapacity: "128GB"},
+			new:  &OrgDeviceAttributes{DeviceCapacity: "256GB"},
+			want: []FieldChange{{Field: "deviceCapacity", Old: "128GB", New: "256GB"}},
+		},
+		"success: enum change": {
+			old:  &OrgDeviceAttributes{Status: StatusUnAssigned},
+			new:  &OrgDeviceAttributes{Status: StatusAssigned},
+			want: []FieldChange{{Field: "status", Old: "UNASSIGNED", New: "ASSIGNED"}},
+		},
+		"success: time change renders RFC 3339": {
+			old:  &OrgDeviceAttributes{AddedToOrgDateTime: added},
+			new:  &OrgDeviceAttributes{AddedToOrgDateTime: added.Add(time.Hour)},
+			want: []FieldChange{{Field: "addedToOrgDateTime", Old: "2025-03-01T09:30:00Z", New: "2025-03-01T10:30:00Z"}},
+		},
+		"success: same instant in another zone is unchanged": {
+			old: &OrgDeviceAttributes{AddedToOrgDateTime: added},
+			new: &OrgDeviceAttributes{AddedToOrgDateTime: added.In(time.FixedZone("JST", 9*60*60))},
+		},
+		"success: reordered list is unchanged": {
+			old: &OrgDeviceAttributes{IMEI: []string{"111", "222"}},
+			new: &OrgDeviceAttributes{IMEI: []string{"222", "111"}},
+		},
+		"success: list change renders sorted": {
+			old:  &OrgDeviceAttributes{WifiMacAddress: []string{"bb", "aa"}},
+			new:  &OrgDeviceAttributes{WifiMacAddress: []string{"cc", "aa"}},
+			want: []FieldChange{{Field: "wifiMacAddress", Old: "aa,bb", New: "aa,cc"}},
+		},
+		"success: changes in declaration order": {
+			old: &OrgDeviceAttributes{Color: "black", SerialNumber: "S1"},
+			new: &OrgDeviceAttributes{Color: "white", SerialNumber: "S2"},
+			want: []FieldChange{
+				{Field: "color", Old: "black", New: "white"},
+				{Field: "serialNumber", Old: "S1", New: "S2"},
+			},
+		},
+		"success: nil old reports all added": {
+			new: &OrgDeviceAttributes{SerialNumber: "S1", IMEI: []string{"111"}},
+			want: []FieldChange{
+				{Field: "imei", New: "111"},
+				{Field: "serialNumber", New: "S1"},
+			},
+		},
+		"success: nil new reports all removed": {
+			old:  &OrgDeviceAttributes{SerialNumber: "S1", UpdatedDateTime: added},
+			want: []FieldChange{{Field: "serialNumber", Old: "S1"}, {Field: "updatedDateTime", Old: "2025-03-01T09:30:00Z"}},
+		},
+		"success: both nil": {},
+		"success: equal": {
+			old: &OrgDeviceAttributes{SerialNumber: "S1"},
+			new: &OrgDeviceAttributes{SerialNumber: "S1"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			got := DiffOrgDeviceAttributes(tt.old, tt.new)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("DiffOrgDeviceAttributes mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDiffStruct_PointerFields(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	type record struct {
+		Seen    *time.Time `json:"seen,omitzero"`
+		Enabled *bool      `json:"enabled,omitzero"`
+		Ignored string
+	}
+
+	seen := time.Date(2025, time.March, 1, 9, 30, 0, 0, time.UTC)
+	seenElsewhere := seen.In(time.FixedZone("JST", 9*60*60))
+	later := seen.Add(time.Minute)
+	enabled, disabled := true, false
+
+	tests := map[string]struct {
+		old  *record
+		new  *record
+		want []FieldChange
+	}{
+		"success: pointer time change": {
+			old:  &record{Seen: &seen},
+			new:  &record{Seen: &later},
+			want: []FieldChange{{Field: "seen", Old: "2025-03-01T09:30:00Z", New: "2025-03-01T09:31:00Z"}},
+		},
+		"success: pointer time same instant is unchanged": {
+			old: &record{Seen: &seen},
+			new: &record{Seen: &seenElsewhere},
+		},
+		"success: pointer time set": {
+			old:  &record{},
+			new:  &record{Seen: &seen},
+			want: []FieldChange{{Field: "seen", New: "2025-03-01T09:30:00Z"}},
+		},
+		"success: pointer bool change": {
+			old:  &record{Enabled: &enabled},
+			new:  &record{Enabled: &disabled},
+			want: []FieldChange{{Field: "enabled", Old: "true", New: "false"}},
+		},
+		"success: pointer bool cleared": {
+			old:  &record{Enabled: &disabled},
+			new:  &record{},
+			want: []FieldChange{{Field: "enabled", Old: "false"}},
+		},
+		"success: untagged field is ignored": {
+			old: &record{Ignored: "a"},
+			new: &record{Ignored: "b"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			got := diffStruct(nil, "", reflect.ValueOf(tt.old), reflect.ValueOf(tt.new))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("diffStruct mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDiffOrgDevices(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	device := func(status OrgDeviceAttributesStatus, related string) *OrgDevice {
+		d := &OrgDevice{
+			ID:         "DEVICE1",
+			Type:       "orgDevices",
+			Attributes: &OrgDeviceAttributes{Status: status},
+		}
+		if related != "" {
+			d.Relationships = &OrgDeviceRelationships{
+				AssignedServer: &OrgDeviceRelationshipsAssignedServer{
+					Links: &RelationshipLinks{Related: related},
+				},
+			}
+		}
+		return d
+	}
+
+	tests := map[string]struct {
+		old  *OrgDevice
+		new  *OrgDevice
+		want []FieldChange
+	}{
+		"success: attribute and relationship changes": {
+			old: device(StatusUnAssigned, ""),
+			new: device(StatusAssigned, "https://example.test/v1/orgDevices/DEVICE1/assignedServer"),
+			want: []FieldChange{
+				{Field: "status", Old: "UNASSIGNED", New: "ASSIGNED"},
+				{Field: "relationships.assignedServer.links.related", New: "https://example.test/v1/orgDevices/DEVICE1/assignedServer"},
+			},
+		},
+		"success: relationship link change": {
+			old:  device(StatusAssigned, "https://example.test/a"),
+			new:  device(StatusAssigned, "https://example.test/b"),
+			want: []FieldChange{{Field: "relationships.assignedServer.links.related", Old: "https://example.test/a", New: "https://example.test/b"}},
+		},
+		"success: nil old reports all added": {
+			new: device(StatusAssigned, "https://example.test/a"),
+			want: []FieldChange{
+				{Field: "status", New: "ASSIGNED"},
+				{Field: "relationships.assignedServer.links.related", New: "https://example.test/a"},
+			},
+		},
+		"success: nil new reports all removed": {
+			old:  device(StatusAssigned, ""),
+			want: []FieldChange{{Field: "status", Old: "ASSIGNED"}},
+		},
+		"success: unchanged": {
+			old: device(StatusAssigned, "https://example.test/a"),
+			new: device(StatusAssigned, "https://example.test/a"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			got := DiffOrgDevices(tt.old, tt.new)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("DiffOrgDevices mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import "fmt"
+
+//go:generate go run ./internal/cmd/enumgen -output enum_gen.go
+
+// UnknownValueError is returned by the Parse functions of the string enum
+// types, such as [ParseOrgDeviceAttributesStatus], for a value that is not
+// one of the type's declared constants. Apple may introduce new values before
+// this package declares them.
+type UnknownValueError struct {
+	// Type is the name of the enum type, such as "OrgDeviceAttributesStatus".
+	Type string
+
+	// Value is the unrecognized value.
+	Value string
+}
+
+func (e *UnknownValueError) Error() string {
+	return fmt.Sprintf("unknown %s value %q", e.Type, e.Value)
+}
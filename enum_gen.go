@@ -0,0 +1,427 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by enumgen. DO NOT EDIT.
+
+package abm
+
+// Known reports whether v is a declared AppleCareCoveragePaymentType value.
+func (v AppleCareCoveragePaymentType) Known() bool {
+	switch v {
+	case AppleCareCoveragePaymentTypeABESubscription, AppleCareCoveragePaymentTypePaidUpFront, AppleCareCoveragePaymentTypeSubscription, AppleCareCoveragePaymentTypeNone:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsKnown reports whether v is a declared AppleCareCoveragePaymentType value, like [AppleCareCoveragePaymentType.Known].
+func (v AppleCareCoveragePaymentType) IsKnown() bool {
+	return v.Known()
+}
+
+// AppleCareCoveragePaymentTypeValues returns every declared AppleCareCoveragePaymentType value in declaration order.
+func AppleCareCoveragePaymentTypeValues() []AppleCareCoveragePaymentType {
+	return []AppleCareCoveragePaymentType{
+		AppleCareCoveragePaymentTypeABESubscription,
+		AppleCareCoveragePaymentTypePaidUpFront,
+		AppleCareCoveragePaymentTypeSubscription,
+		AppleCareCoveragePaymentTypeNone,
+	}
+}
+
+// ParseAppleCareCoveragePaymentType converts s to AppleCareCoveragePaymentType, returning an [*UnknownValueError] when s is not a declared value.
+func ParseAppleCareCoveragePaymentType(s string) (AppleCareCoveragePaymentType, error) {
+	if v := AppleCareCoveragePaymentType(s); v.Known() {
+		return v, nil
+	}
+
+	return "", &UnknownValueError{Type: "AppleCareCoveragePaymentType", Value: s}
+}
+
+// Known reports whether v is a declared AppleCareCoverageStatus value.
+func (v AppleCareCoverageStatus) Known() bool {
+	switch v {
+	case AppleCareCoverageStatusActive, AppleCareCoverageStatusInactive:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsKnown reports whether v is a declared AppleCareCoverageStatus value, like [AppleCareCoverageStatus.Known].
+func (v AppleCareCoverageStatus) IsKnown() bool {
+	return v.Known()
+}
+
+// AppleCareCoverageStatusValues returns every declared AppleCareCoverageStatus value in declaration order.
+func AppleCareCoverageStatusValues() []AppleCareCoverageStatus {
+	return []AppleCareCoverageStatus{
+		AppleCareCoverageStatusActive,
+		AppleCareCoverageStatusInactive,
+	}
+}
+
+// ParseAppleCareCoverageStatus converts s to AppleCareCoverageStatus, returning an [*UnknownValueError] when s is not a declared value.
+func ParseAppleCareCoverageStatus(s string) (AppleCareCoverageStatus, error) {
+	if v := AppleCareCoverageStatus(s); v.Known() {
+		return v, nil
+	}
+
+	return "", &UnknownValueError{Type: "AppleCareCoverageStatus", Value: s}
+}
+
+// Known reports whether v is a declared DateField value.
+func (v DateField) Known() bool {
+	switch v {
+	case DateFieldOrderDateTime, DateFieldAddedToOrgDateTime, DateFieldUpdatedDateTime:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsKnown reports whether v is a declared DateField value, like [DateField.Known].
+func (v DateField) IsKnown() bool {
+	return v.Known()
+}
+
+// DateFieldValues returns every declared DateField value in declaration order.
+func DateFieldValues() []DateField {
+	return []DateField{
+		DateFieldOrderDateTime,
+		DateFieldAddedToOrgDateTime,
+		DateFieldUpdatedDateTime,
+	}
+}
+
+// ParseDateField converts s to DateField, returning an [*UnknownValueError] when s is not a declared value.
+func ParseDateField(s string) (DateField, error) {
+	if v := DateField(s); v.Known() {
+		return v, nil
+	}
+
+	return "", &UnknownValueError{Type: "DateField", Value: s}
+}
+
+// Known reports whether v is a declared ErrorCode value.
+func (v ErrorCode) Known() bool {
+	switch v {
+	case ErrorCodeParameterInvalid, ErrorCodeParameterIllegal, ErrorCodeEntity, ErrorCodeEntityNotFound, ErrorCodeEntityInvalid, ErrorCodeNotAuthorized, ErrorCodeForbidden, ErrorCodeNotFound, ErrorCodeConflict, ErrorCodeRateLimitExceeded, ErrorCodeUnexpected, ErrorCodeServiceUnavailable:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsKnown reports whether v is a declared ErrorCode value, like [ErrorCode.Known].
+func (v ErrorCode) IsKnown() bool {
+	return v.Known()
+}
+
+// ErrorCodeValues returns every declared ErrorCode value in declaration order.
+func ErrorCodeValues() []ErrorCode {
+	return []ErrorCode{
+		ErrorCodeParameterInvalid,
+		ErrorCodeParameterIllegal,
+		ErrorCodeEntity,
+		ErrorCodeEntityNotFound,
+		ErrorCodeEntityInvalid,
+		ErrorCodeNotAuthorized,
+		ErrorCodeForbidden,
+		ErrorCodeNotFound,
+		ErrorCodeConflict,
+		ErrorCodeRateLimitExceeded,
+		ErrorCodeUnexpected,
+		ErrorCodeServiceUnavailable,
+	}
+}
+
+// ParseErrorCode converts s to ErrorCode, returning an [*UnknownValueError] when s is not a declared value.
+func ParseErrorCode(s string) (ErrorCode, error) {
+	if v := ErrorCode(s); v.Known() {
+		return v, nil
+	}
+
+	return "", &UnknownValueError{Type: "ErrorCode", Value: s}
+}
+
+// Known reports whether v is a declared MDMServerType value.
+func (v MDMServerType) Known() bool {
+	switch v {
+	case ServerTypeMDM, ServerTypeAppleConfigurator, ServerTypeAppleBusinessEssentials:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsKnown reports whether v is a declared MDMServerType value, like [MDMServerType.Known].
+func (v MDMServerType) IsKnown() bool {
+	return v.Known()
+}
+
+// MDMServerTypeValues returns every declared MDMServerType value in declaration order.
+func MDMServerTypeValues() []MDMServerType {
+	return []MDMServerType{
+		ServerTypeMDM,
+		ServerTypeAppleConfigurator,
+		ServerTypeAppleBusinessEssentials,
+	}
+}
+
+// ParseMDMServerType converts s to MDMServerType, returning an [*UnknownValueError] when s is not a declared value.
+func ParseMDMServerType(s string) (MDMServerType, error) {
+	if v := MDMServerType(s); v.Known() {
+		return v, nil
+	}
+
+	return "", &UnknownValueError{Type: "MDMServerType", Value: s}
+}
+
+// Known reports whether v is a declared OrgDeviceActivityStatus value.
+func (v OrgDeviceActivityStatus) Known() bool {
+	switch v {
+	case OrgDeviceActivityStatusInProgress, OrgDeviceActivityStatusCompleted, OrgDeviceActivityStatusFailed, OrgDeviceActivityStatusStopped:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsKnown reports whether v is a declared OrgDeviceActivityStatus value, like [OrgDeviceActivityStatus.Known].
+func (v OrgDeviceActivityStatus) IsKnown() bool {
+	return v.Known()
+}
+
+// OrgDeviceActivityStatusValues returns every declared OrgDeviceActivityStatus value in declaration order.
+func OrgDeviceActivityStatusValues() []OrgDeviceActivityStatus {
+	return []OrgDeviceActivityStatus{
+		OrgDeviceActivityStatusInProgress,
+		OrgDeviceActivityStatusCompleted,
+		OrgDeviceActivityStatusFailed,
+		OrgDeviceActivityStatusStopped,
+	}
+}
+
+// ParseOrgDeviceActivityStatus converts s to OrgDeviceActivityStatus, returning an [*UnknownValueError] when s is not a declared value.
+func ParseOrgDeviceActivityStatus(s string) (OrgDeviceActivityStatus, error) {
+	if v := OrgDeviceActivityStatus(s); v.Known() {
+		return v, nil
+	}
+
+	return "", &UnknownValueError{Type: "OrgDeviceActivityStatus", Value: s}
+}
+
+// Known reports whether v is a declared OrgDeviceActivityType value.
+func (v OrgDeviceActivityType) Known() bool {
+	switch v {
+	case OrgDeviceActivityTypeAssignDevices, OrgDeviceActivityTypeUnassignDevices:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsKnown reports whether v is a declared OrgDeviceActivityType value, like [OrgDeviceActivityType.Known].
+func (v OrgDeviceActivityType) IsKnown() bool {
+	return v.Known()
+}
+
+// OrgDeviceActivityTypeValues returns every declared OrgDeviceActivityType value in declaration order.
+func OrgDeviceActivityTypeValues() []OrgDeviceActivityType {
+	return []OrgDeviceActivityType{
+		OrgDeviceActivityTypeAssignDevices,
+		OrgDeviceActivityTypeUnassignDevices,
+	}
+}
+
+// ParseOrgDeviceActivityType converts s to OrgDeviceActivityType, returning an [*UnknownValueError] when s is not a declared value.
+func ParseOrgDeviceActivityType(s string) (OrgDeviceActivityType, error) {
+	if v := OrgDeviceActivityType(s); v.Known() {
+		return v, nil
+	}
+
+	return "", &UnknownValueError{Type: "OrgDeviceActivityType", Value: s}
+}
+
+// Known reports whether v is a declared OrgDeviceAttributesProductFamily value.
+func (v OrgDeviceAttributesProductFamily) Known() bool {
+	switch v {
+	case ProductFamilyIPhone, ProductFamilyIPad, ProductFamilyMac, ProductFamilyAppleTV, ProductFamilyWatch, ProductFamilyVision:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsKnown reports whether v is a declared OrgDeviceAttributesProductFamily value, like [OrgDeviceAttributesProductFamily.Known].
+func (v OrgDeviceAttributesProductFamily) IsKnown() bool {
+	return v.Known()
+}
+
+// OrgDeviceAttributesProductFamilyValues returns every declared OrgDeviceAttributesProductFamily value in declaration order.
+func OrgDeviceAttributesProductFamilyValues() []OrgDeviceAttributesProductFamily {
+	return []OrgDeviceAttributesProductFamily{
+		ProductFamilyIPhone,
+		ProductFamilyIPad,
+		ProductFamilyMac,
+		ProductFamilyAppleTV,
+		ProductFamilyWatch,
+		ProductFamilyVision,
+	}
+}
+
+// ParseOrgDeviceAttributesProductFamily converts s to OrgDeviceAttributesProductFamily, returning an [*UnknownValueError] when s is not a declared value.
+func ParseOrgDeviceAttributesProductFamily(s string) (OrgDeviceAttributesProductFamily, error) {
+	if v := OrgDeviceAttributesProductFamily(s); v.Known() {
+		return v, nil
+	}
+
+	return "", &UnknownValueError{Type: "OrgDeviceAttributesProductFamily", Value: s}
+}
+
+// Known reports whether v is a declared OrgDeviceAttributesPurchaseSourceType value.
+func (v OrgDeviceAttributesPurchaseSourceType) Known() bool {
+	switch v {
+	case PurchaseSourceTypeApple, PurchaseSourceTypeReseller, PurchaseSourceTypeManuallyAdded:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsKnown reports whether v is a declared OrgDeviceAttributesPurchaseSourceType value, like [OrgDeviceAttributesPurchaseSourceType.Known].
+func (v OrgDeviceAttributesPurchaseSourceType) IsKnown() bool {
+	return v.Known()
+}
+
+// OrgDeviceAttributesPurchaseSourceTypeValues returns every declared OrgDeviceAttributesPurchaseSourceType value in declaration order.
+func OrgDeviceAttributesPurchaseSourceTypeValues() []OrgDeviceAttributesPurchaseSourceType {
+	return []OrgDeviceAttributesPurchaseSourceType{
+		PurchaseSourceTypeApple,
+		PurchaseSourceTypeReseller,
+		PurchaseSourceTypeManuallyAdded,
+	}
+}
+
+// ParseOrgDeviceAttributesPurchaseSourceType converts s to OrgDeviceAttributesPurchaseSourceType, returning an [*UnknownValueError] when s is not a declared value.
+func ParseOrgDeviceAttributesPurchaseSourceType(s string) (OrgDeviceAttributesPurchaseSourceType, error) {
+	if v := OrgDeviceAttributesPurchaseSourceType(s); v.Known() {
+		return v, nil
+	}
+
+	return "", &UnknownValueError{Type: "OrgDeviceAttributesPurchaseSourceType", Value: s}
+}
+
+// Known reports whether v is a declared OrgDeviceAttributesStatus value.
+func (v OrgDeviceAttributesStatus) Known() bool {
+	switch v {
+	case StatusAssigned, StatusUnAssigned:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsKnown reports whether v is a declared OrgDeviceAttributesStatus value, like [OrgDeviceAttributesStatus.Known].
+func (v OrgDeviceAttributesStatus) IsKnown() bool {
+	return v.Known()
+}
+
+// OrgDeviceAttributesStatusValues returns every declared OrgDeviceAttributesStatus value in declaration order.
+func OrgDeviceAttributesStatusValues() []OrgDeviceAttributesStatus {
+	return []OrgDeviceAttributesStatus{
+		StatusAssigned,
+		StatusUnAssigned,
+	}
+}
+
+// ParseOrgDeviceAttributesStatus converts s to OrgDeviceAttributesStatus, returning an [*UnknownValueError] when s is not a declared value.
+func ParseOrgDeviceAttributesStatus(s string) (OrgDeviceAttributesStatus, error) {
+	if v := OrgDeviceAttributesStatus(s); v.Known() {
+		return v, nil
+	}
+
+	return "", &UnknownValueError{Type: "OrgDeviceAttributesStatus", Value: s}
+}
+
+// Known reports whether v is a declared OrgDeviceDisposition value.
+func (v OrgDeviceDisposition) Known() bool {
+	switch v {
+	case DispositionFound, DispositionNotInOrganization, DispositionReleased, DispositionAccessDenied:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsKnown reports whether v is a declared OrgDeviceDisposition value, like [OrgDeviceDisposition.Known].
+func (v OrgDeviceDisposition) IsKnown() bool {
+	return v.Known()
+}
+
+// OrgDeviceDispositionValues returns every declared OrgDeviceDisposition value in declaration order.
+func OrgDeviceDispositionValues() []OrgDeviceDisposition {
+	return []OrgDeviceDisposition{
+		DispositionFound,
+		DispositionNotInOrganization,
+		DispositionReleased,
+		DispositionAccessDenied,
+	}
+}
+
+// ParseOrgDeviceDisposition converts s to OrgDeviceDisposition, returning an [*UnknownValueError] when s is not a declared value.
+func ParseOrgDeviceDisposition(s string) (OrgDeviceDisposition, error) {
+	if v := OrgDeviceDisposition(s); v.Known() {
+		return v, nil
+	}
+
+	return "", &UnknownValueError{Type: "OrgDeviceDisposition", Value: s}
+}
+
+// Known reports whether v is a declared ServiceFamily value.
+func (v ServiceFamily) Known() bool {
+	switch v {
+	case ServiceFamilyUnknown, ServiceFamilyBusiness, ServiceFamilySchool:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsKnown reports whether v is a declared ServiceFamily value, like [ServiceFamily.Known].
+func (v ServiceFamily) IsKnown() bool {
+	return v.Known()
+}
+
+// ServiceFamilyValues returns every declared ServiceFamily value in declaration order.
+func ServiceFamilyValues() []ServiceFamily {
+	return []ServiceFamily{
+		ServiceFamilyUnknown,
+		ServiceFamilyBusiness,
+		ServiceFamilySchool,
+	}
+}
+
+// ParseServiceFamily converts s to ServiceFamily, returning an [*UnknownValueError] when s is not a declared value.
+func ParseServiceFamily(s string) (ServiceFamily, error) {
+	if v := ServiceFamily(s); v.Known() {
+		return v, nil
+	}
+
+	return "", &UnknownValueError{Type: "ServiceFamily", Value: s}
+}
@@ -0,0 +1,290 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// enumHelpers exposes the generated helpers of every string enum type as strings.
+var enumHelpers = map[string]struct {
+	values  func() []string
+	known   func(string) bool
+	isKnown func(string) bool
+	parse   func(string) (string, error)
+}{
+	"AppleCareCoveragePaymentType": {
+		values:  func() []string { return enumStrings(AppleCareCoveragePaymentTypeValues()) },
+		known:   func(s string) bool { return AppleCareCoveragePaymentType(s).Known() },
+		isKnown: func(s string) bool { return AppleCareCoveragePaymentType(s).IsKnown() },
+		parse:   func(s string) (string, error) { v, err := ParseAppleCareCoveragePaymentType(s); return string(v), err },
+	},
+	"AppleCareCoverageStatus": {
+		values:  func() []string { return enumStrings(AppleCareCoverageStatusValues()) },
+		known:   func(s string) bool { return AppleCareCoverageStatus(s).Known() },
+		isKnown: func(s string) bool { return AppleCareCoverageStatus(s).IsKnown() },
+		parse:   func(s string) (string, error) { v, err := ParseAppleCareCoverageStatus(s); return string(v), err },
+	},
+	"DateField": {
+		values:  func() []string { return enumStrings(DateFieldValues()) },
+		known:   func(s string) bool { return DateField(s).Known() },
+		isKnown: func(s string) bool { return DateField(s).IsKnown() },
+		parse:   func(s string) (string, error) { v, err := ParseDateField(s); return string(v), err },
+	},
+	"ErrorCode": {
+		values:  func() []string { return enumStrings(ErrorCodeValues()) },
+		known:   func(s string) bool { return ErrorCode(s).Known() },
+		isKnown: func(s string) bool { return ErrorCode(s).IsKnown() },
+		parse:   func(s string) (string, error) { v, err := ParseErrorCode(s); return string(v), err },
+	},
+	"MDMServerType": {
+		values:  func() []string { return enumStrings(MDMServerTypeValues()) },
+		known:   func(s string) bool { return MDMServerType(s).Known() },
+		isKnown: func(s string) bool { return MDMServerType(s).IsKnown() },
+		parse:   func(s string) (string, error) { v, err := ParseMDMServerType(s); return string(v), err },
+	},
+	"OrgDeviceActivityStatus": {
+		values:  func() []string { return enumStrings(OrgDeviceActivityStatusValues()) },
+		known:   func(s string) bool { return OrgDeviceActivityStatus(s).Known() },
+		isKnown: func(s string) bool { return OrgDeviceActivityStatus(s).IsKnown() },
+		parse:   func(s string) (string, error) { v, err := ParseOrgDeviceActivityStatus(s); return string(v), err },
+	},
+	"OrgDeviceActivityType": {
+		values:  func() []string { return enumStrings(OrgDeviceActivityTypeValues()) },
+		known:   func(s string) bool { return OrgDeviceActivityType(s).Known() },
+		isKnown: func(s string) bool { return OrgDeviceActivityType(s).IsKnown() },
+		parse:   func(s string) (string, error) { v, err := ParseOrgDeviceActivityType(s); return string(v), err },
+	},
+	"OrgDeviceAttributesProductFamily": {
+		values:  func() []string { return enumStrings(OrgDeviceAttributesProductFamilyValues()) },
+		known:   func(s string) bool { return OrgDeviceAttributesProductFamily(s).Known() },
+		isKnown: func(s string) bool { return OrgDeviceAttributesProductFamily(s).IsKnown() },
+		parse: func(s string) (string, error) {
+			v, err := ParseOrgDeviceAttributesProductFamily(s)
+			return string(v), err
+		},
+	},
+	"OrgDeviceAttributesPurchaseSourceType": {
+		values:  func() []string { return enumStrings(OrgDeviceAttributesPurchaseSourceTypeValues()) },
+		known:   func(s string) bool { return OrgDeviceAttributesPurchaseSourceType(s).Known() },
+		isKnown: func(s string) bool { return OrgDeviceAttributesPurchaseSourceType(s).IsKnown() },
+		parse: func(s string) (string, error) {
+			v, err := ParseOrgDeviceAttributesPurchaseSourceType(s)
+			return string(v), err
+		},
+	},
+	"OrgDeviceAttributesStatus": {
+		values:  func() []string { return enumStrings(OrgDeviceAttributesStatusValues()) },
+		known:   func(s string) bool { return OrgDeviceAttributesStatus(s).Known() },
+		isKnown: func(s string) bool { return OrgDeviceAttributesStatus(s).IsKnown() },
+		parse:   func(s string) (string, error) { v, err := ParseOrgDeviceAttributesStatus(s); return string(v), err },
+	},
+	"OrgDeviceDisposition": {
+		values:  func() []string { return enumStrings(OrgDeviceDispositionValues()) },
+		known:   func(s string) bool { return OrgDeviceDisposition(s).Known() },
+		isKnown: func(s string) bool { return OrgDeviceDisposition(s).IsKnown() },
+		parse:   func(s string) (string, error) { v, err := ParseOrgDeviceDisposition(s); return string(v), err },
+	},
+	"ServiceFamily": {
+		values:  func() []string { return enumStrings(ServiceFamilyValues()) },
+		known:   func(s string) bool { return ServiceFamily(s).Known() },
+		isKnown: func(s string) bool { return ServiceFamily(s).IsKnown() },
+		parse:   func(s string) (string, error) { v, err := ParseServiceFamily(s); return string(v), err },
+	},
+}
+
+func enumStrings[T ~string](values []T) []string {
+	strs := make([]string, len(values))
+	for i, v := range values {
+		strs[i] = string(v)
+	}
+
+	return strs
+}
+
+// declaredEnumConstants returns the values of the exported constants of each
+// exported string type declared in the package sources, in declaration order.
+func declaredEnumConstants(t *testing.T) map[string][]string {
+	t.Helper()
+
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatalf("glob package files: %v", err)
+	}
+
+	fset := token.NewFileSet()
+	enumTypes := make(map[string]bool)
+	var specs []*ast.ValueSpec
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, nil, parser.SkipObjectResolution)
+		if err != nil {
+			t.Fatalf("parse %s: %v", name, err)
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.TypeSpec:
+				if ident, ok := n.Type.(*ast.Ident); ok && ident.Name == "string" && n.Name.IsExported() {
+					enumTypes[n.Name.Name] = true
+				}
+			case *ast.GenDecl:
+				if n.Tok == token.CONST {
+					for _, spec := range n.Specs {
+						specs = append(specs, spec.(*ast.ValueSpec))
+					}
+				}
+			}
+			return true
+		})
+	}
+
+	declared := make(map[string][]string)
+	for _, spec := range specs {
+		ident, ok := spec.Type.(*ast.Ident)
+		if !ok || !enumTypes[ident.Name] {
+			continue
+		}
+		for i, name := range spec.Names {
+			if !name.IsExported() {
+				continue
+			}
+			lit, ok := spec.Values[i].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				t.Fatalf("constant %s is not a string literal", name.Name)
+			}
+			value, err := strconv.Unquote(lit.Value)
+			if err != nil {
+				t.Fatalf("unquote constant %s: %v", name.Name, err)
+			}
+			declared[ident.Name] = append(declared[ident.Name], value)
+		}
+	}
+
+	return declared
+}
+
+func TestEnumValuesMatchDeclarations(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	declared := declaredEnumConstants(t)
+	if diff := cmp.Diff(slices.Sorted(maps.Keys(declared)), slices.Sorted(maps.Keys(enumHelpers))); diff != "" {
+		t.Fatalf("enum types mismatch; run go generate and update enumHelpers (-declared +helpers):\n%s", diff)
+	}
+
+	for name, want := range declared {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			if diff := cmp.Diff(want, enumHelpers[name].values()); diff != "" {
+				t.Fatalf("%sValues mismatch; run go generate (-declared +values):\n%s", name, diff)
+			}
+		})
+	}
+}
+
+func TestEnumKnownAndParse(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	for name, helpers := range enumHelpers {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			values := helpers.values()
+			if len(values) == 0 {
+				t.Fatal("no values")
+			}
+			for _, value := range values {
+				if !helpers.known(value) {
+					t.Fatalf("Known(%q) = false, want true", value)
+				}
+				if !helpers.isKnown(value) {
+					t.Fatalf("IsKnown(%q) = false, want true", value)
+				}
+				got, err := helpers.parse(value)
+				if err != nil {
+					t.Fatalf("Parse(%q) returned error: %v", value, err)
+				}
+				if diff := cmp.Diff(value, got); diff != "" {
+					t.Fatalf("Parse(%q) mismatch (-want +got):\n%s", value, diff)
+				}
+			}
+
+			// Values returns a copy the caller may modify.
+			values[0] = "MODIFIED"
+			if helpers.values()[0] == "MODIFIED" {
+				t.Fatal("Values returned a shared slice")
+			}
+
+			for _, unknown := range []string{"", "NEW_VALUE_FROM_APPLE", strings.ToLower(values[1%len(values)]) + " "} {
+				if helpers.known(unknown) {
+					t.Fatalf("Known(%q) = true, want false", unknown)
+				}
+				if helpers.isKnown(unknown) {
+					t.Fatalf("IsKnown(%q) = true, want false", unknown)
+				}
+				got, err := helpers.parse(unknown)
+				var unknownErr *UnknownValueError
+				if !errors.As(err, &unknownErr) {
+					t.Fatalf("Parse(%q) error = %v, want *UnknownValueError", unknown, err)
+				}
+				if diff := cmp.Diff(&UnknownValueError{Type: name, Value: unknown}, unknownErr); diff != "" {
+					t.Fatalf("UnknownValueError mismatch (-want +got):\n%s", diff)
+				}
+				if got != "" {
+					t.Fatalf("Parse(%q) = %q, want empty", unknown, got)
+				}
+			}
+		})
+	}
+}
+
+func TestUnknownValueError_Error(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	_, err := ParseOrgDeviceAttributesStatus("PENDING")
+	if diff := cmp.Diff(`unknown OrgDeviceAttributesStatus value "PENDING"`, err.Error()); diff != "" {
+		t.Fatalf("error message mismatch (-want +got):\n%s", diff)
+	}
+}
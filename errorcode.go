@@ -0,0 +1,157 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrorCode is a machine-readable error code in [ErrorResponseError.Code].
+// Apple documents a finite set of codes, declared below; a code outside the
+// set is kept verbatim and has no classification.
+type ErrorCode string
+
+// Documented error codes.
+const (
+	ErrorCodeParameterInvalid   ErrorCode = "PARAMETER_ERROR.INVALID"
+	ErrorCodeParameterIllegal   ErrorCode = "PARAMETER_ERROR.ILLEGAL"
+	ErrorCodeEntity             ErrorCode = "ENTITY_ERROR"
+	ErrorCodeEntityNotFound     ErrorCode = "ENTITY_ERROR.NOT_FOUND"
+	ErrorCodeEntityInvalid      ErrorCode = "ENTITY_ERROR.ATTRIBUTE.INVALID"
+	ErrorCodeNotAuthorized      ErrorCode = "NOT_AUTHORIZED"
+	ErrorCodeForbidden          ErrorCode = "FORBIDDEN_ERROR"
+	ErrorCodeNotFound           ErrorCode = "NOT_FOUND"
+	ErrorCodeConflict           ErrorCode = "CONFLICT_ERROR"
+	ErrorCodeRateLimitExceeded  ErrorCode = "RATE_LIMIT_EXCEEDED"
+	ErrorCodeUnexpected         ErrorCode = "UNEXPECTED_ERROR"
+	ErrorCodeServiceUnavailable ErrorCode = "SERVICE_UNAVAILABLE"
+)
+
+// ErrorCodeClass describes how an [ErrorCode] should be handled.
+type ErrorCodeClass struct {
+	// Retryable reports whether the same request may succeed when sent again later.
+	Retryable bool
+
+	// UserFixable reports whether the request itself is at fault and the
+	// caller can correct it, for example by fixing a parameter.
+	UserFixable bool
+
+	// CredentialRelated reports whether the credentials are missing,
+	// invalid, or lack the permission for the request.
+	CredentialRelated bool
+}
+
+// errorCodeClasses classifies the documented error codes.
+var errorCodeClasses = map[ErrorCode]ErrorCodeClass{
+	ErrorCodeParameterInvalid:   {UserFixable: true},
+	ErrorCodeParameterIllegal:   {UserFixable: true},
+	ErrorCodeEntity:             {UserFixable: true},
+	ErrorCodeEntityNotFound:     {UserFixable: true},
+	ErrorCodeEntityInvalid:      {UserFixable: true},
+	ErrorCodeNotAuthorized:      {UserFixable: true, CredentialRelated: true},
+	ErrorCodeForbidden:          {UserFixable: true, CredentialRelated: true},
+	ErrorCodeNotFound:           {UserFixable: true},
+	ErrorCodeConflict:           {UserFixable: true},
+	ErrorCodeRateLimitExceeded:  {Retryable: true},
+	ErrorCodeUnexpected:         {Retryable: true},
+	ErrorCodeServiceUnavailable: {Retryable: true},
+}
+
+// Class returns the classification of c. An undocumented code returns the
+// zero ErrorCodeClass, which is conservatively not retryable.
+func (c ErrorCode) Class() ErrorCodeClass {
+	return errorCodeClasses[c]
+}
+
+// ErrorCodes returns the codes of the error objects in e.Response, in order
+// and verbatim, skipping empty codes. It returns nil when e is nil.
+func (e *APIError) ErrorCodes() []ErrorCode {
+	if e == nil {
+		return nil
+	}
+
+	var codes []ErrorCode
+	for _, item := range e.Response.Errors {
+		if item.Code != "" {
+			codes = append(codes, ErrorCode(item.Code))
+		}
+	}
+
+	return codes
+}
+
+// codeClass combines the classifications of the documented codes in e. The
+// combination is retryable only when every documented code is, and user
+// fixable or credential related when any documented code is. ok is false when
+// e carries no documented code.
+func (e *APIError) codeClass() (class ErrorCodeClass, ok bool) {
+	class.Retryable = true
+	for _, code := range e.ErrorCodes() {
+		if !code.Known() {
+			continue
+		}
+		ok = true
+		codeClass := code.Class()
+		class.Retryable = class.Retryable && codeClass.Retryable
+		class.UserFixable = class.UserFixable || codeClass.UserFixable
+		class.CredentialRelated = class.CredentialRelated || codeClass.CredentialRelated
+	}
+	if !ok {
+		return ErrorCodeClass{}, false
+	}
+
+	return class, true
+}
+
+// IsRetryable reports whether err is an [*APIError] for a request that may
+// succeed when sent again later.
+//
+// The documented error codes in the response win over the HTTP status, since
+// they are more specific: a 500 response with a PARAMETER_ERROR.INVALID code
+// is not retryable, and a 400 response with a RATE_LIMIT_EXCEEDED code is.
+// When the response carries no documented code, a 429 or 5xx status is
+// retryable.
+func IsRetryable(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if class, ok := apiErr.codeClass(); ok {
+		return class.Retryable
+	}
+
+	return retryableStatus(apiErr.StatusCode)
+}
+
+// IsPermissionDenied reports whether err is an [*APIError] caused by missing,
+// invalid, or insufficient credentials.
+//
+// As with [IsRetryable], documented error codes win over the HTTP status.
+// When the response carries no documented code, a 401 or 403 status is a
+// permission error.
+func IsPermissionDenied(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if class, ok := apiErr.codeClass(); ok {
+		return class.CredentialRelated
+	}
+
+	return apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden
+}
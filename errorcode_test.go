@@ -0,0 +1,216 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestErrorCode_Class(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	userFixable := ErrorCodeClass{UserFixable: true}
+	credential := ErrorCodeClass{UserFixable: true, CredentialRelated: true}
+	retryable := ErrorCodeClass{Retryable: true}
+
+	tests := map[string]struct {
+		code ErrorCode
+		want ErrorCodeClass
+	}{
+		"success: parameter invalid":        {code: ErrorCodeParameterInvalid, want: userFixable},
+		"success: parameter illegal":        {code: ErrorCodeParameterIllegal, want: userFixable},
+		"success: entity error":             {code: ErrorCodeEntity, want: userFixable},
+		"success: entity not found":         {code: ErrorCodeEntityNotFound, want: userFixable},
+		"success: entity attribute invalid": {code: ErrorCodeEntityInvalid, want: userFixable},
+		"success: not authorized":           {code: ErrorCodeNotAuthorized, want: credential},
+		"success: forbidden":                {code: ErrorCodeForbidden, want: credential},
+		"success: not found":                {code: ErrorCodeNotFound, want: userFixable},
+		"success: conflict":                 {code: ErrorCodeConflict, want: userFixable},
+		"success: rate limit exceeded":      {code: ErrorCodeRateLimitExceeded, want: retryable},
+		"success: unexpected error":         {code: ErrorCodeUnexpected, want: retryable},
+		"success: service unavailable":      {code: ErrorCodeServiceUnavailable, want: retryable},
+		"success: unknown code":             {code: "SOMETHING_NEW", want: ErrorCodeClass{}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, tt.code.Class()); diff != "" {
+				t.Fatalf("Class mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	for _, code := range ErrorCodeValues() {
+		if _, ok := errorCodeClasses[code]; !ok {
+			t.Errorf("documented code %q has no classification", code)
+		}
+	}
+}
+
+func TestAPIError_ErrorCodes(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		err  *APIError
+		want []ErrorCode
+	}{
+		"success: codes in order and verbatim": {
+			err: &APIError{Response: ErrorResponse{Errors: []ErrorResponseError{
+				{Code: "PARAMETER_ERROR.INVALID"},
+				{Code: ""},
+				{Code: "Something_New"},
+			}}},
+			want: []ErrorCode{ErrorCodeParameterInvalid, "Something_New"},
+		},
+		"success: no error objects": {
+			err:  &APIError{StatusCode: http.StatusInternalServerError},
+			want: nil,
+		},
+		"success: nil error": {
+			err:  nil,
+			want: nil,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, tt.err.ErrorCodes()); diff != "" {
+				t.Fatalf("ErrorCodes mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsRetryableAndIsPermissionDenied(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	apiError := func(status int, codes ...string) error {
+		items := make([]ErrorResponseError, len(codes))
+		for i, code := range codes {
+			items[i] = ErrorResponseError{Code: code}
+		}
+		return &APIError{StatusCode: status, Response: ErrorResponse{Errors: items}}
+	}
+
+	type classification struct {
+		Retryable        bool
+		PermissionDenied bool
+	}
+
+	tests := map[string]struct {
+		err  error
+		want classification
+	}{
+		"success: status 503 without code": {
+			err:  apiError(http.StatusServiceUnavailable),
+			want: classification{Retryable: true},
+		},
+		"success: status 429 without code": {
+			err:  apiError(http.StatusTooManyRequests),
+			want: classification{Retryable: true},
+		},
+		"success: status 403 without code": {
+			err:  apiError(http.StatusForbidden),
+			want: classification{PermissionDenied: true},
+		},
+		"success: status 400 without code": {
+			err:  apiError(http.StatusBadRequest),
+			want: classification{},
+		},
+		"success: code wins over retryable status": {
+			err:  apiError(http.StatusInternalServerError, string(ErrorCodeParameterInvalid)),
+			want: classification{},
+		},
+		"success: code wins over non-retryable status": {
+			err:  apiError(http.StatusBadRequest, string(ErrorCodeRateLimitExceeded)),
+			want: classification{Retryable: true},
+		},
+		"success: credential code wins over status": {
+			err:  apiError(http.StatusBadRequest, string(ErrorCodeNotAuthorized)),
+			want: classification{PermissionDenied: true},
+		},
+		"success: non-credential code wins over 403 status": {
+			err:  apiError(http.StatusForbidden, string(ErrorCodeEntity)),
+			want: classification{},
+		},
+		"success: unknown code falls back to status": {
+			err:  apiError(http.StatusServiceUnavailable, "SOMETHING_NEW"),
+			want: classification{Retryable: true},
+		},
+		"success: unknown code ignored next to documented code": {
+			err:  apiError(http.StatusServiceUnavailable, "SOMETHING_NEW", string(ErrorCodeConflict)),
+			want: classification{},
+		},
+		"success: mixed codes are not retryable": {
+			err:  apiError(http.StatusTooManyRequests, string(ErrorCodeRateLimitExceeded), string(ErrorCodeParameterIllegal)),
+			want: classification{},
+		},
+		"success: wrapped api error": {
+			err:  fmt.Errorf("list devices: %w", apiError(http.StatusUnauthorized)),
+			want: classification{PermissionDenied: true},
+		},
+		"success: non api error": {
+			err:  errors.New("connection reset"),
+			want: classification{},
+		},
+		"success: nil error": {
+			err:  nil,
+			want: classification{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			got := classification{
+				Retryable:        IsRetryable(tt.err),
+				PermissionDenied: IsPermissionDenied(tt.err),
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("classification mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
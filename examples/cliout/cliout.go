@@ -0,0 +1,202 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cliout defines the stable machine-readable output of the example CLI.
+//
+// The CLI never marshals the abm response types directly. Responses are mapped
+// into the output types of this package, whose JSON field names are part of
+// the CLI's contract: fields may be added in future versions, but existing
+// fields are never renamed, removed, or changed in meaning. Changes to the abm
+// types therefore cannot silently change the CLI output.
+package cliout
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+
+	"github.com/zchee/abm"
+)
+
+// Envelope is the top-level document written by the CLI.
+type Envelope struct {
+	// Endpoint is the API operation that produced the output, such as "orgDevices".
+	Endpoint string `json:"endpoint"`
+
+	// Data lists the returned resources. It is an empty array, never null,
+	// when there are no resources or the request failed.
+	Data []Device `json:"data"`
+
+	// Pagination describes how to fetch further results. It is null on failure.
+	Pagination *Pagination `json:"pagination"`
+
+	// Error describes the failure, or is null on success.
+	Error *Error `json:"error"`
+}
+
+// Device is the stable representation of an organization device.
+// Unset attributes are emitted as empty strings or empty arrays.
+type Device struct {
+	ID                 string   `json:"id"`
+	SerialNumber       string   `json:"serialNumber"`
+	PartNumber         string   `json:"partNumber"`
+	Status             string   `json:"status"`
+	ProductFamily      string   `json:"productFamily"`
+	ProductType        string   `json:"productType"`
+	DeviceModel        string   `json:"deviceModel"`
+	DeviceCapacity     string   `json:"deviceCapacity"`
+	Color              string   `json:"color"`
+	OrderNumber        string   `json:"orderNumber"`
+	PurchaseSourceType string   `json:"purchaseSourceType"`
+	PurchaseSourceID   string   `json:"purchaseSourceId"`
+	IMEI               []string `json:"imei"`
+	AddedToOrg         string   `json:"addedToOrg"`
+	ReleasedFromOrg    string   `json:"releasedFromOrg"`
+	Updated            string   `json:"updated"`
+}
+
+// Pagination is the stable representation of paging information.
+type Pagination struct {
+	// Next is the URL of the next page, or empty on the last page.
+	Next string `json:"next"`
+
+	// Limit is the page size reported by the API, or 0 when unknown.
+	Limit int `json:"limit"`
+
+	// Total is the total number of resources reported by the API, or 0 when unknown.
+	Total int `json:"total"`
+}
+
+// Error is the stable representation of a failure.
+type Error struct {
+	Message string `json:"message"`
+
+	// StatusCode is the HTTP status code of an API error, or 0 for other failures.
+	StatusCode int `json:"statusCode"`
+
+	// Code is the first API error code, such as "NOT_FOUND", if any.
+	Code string `json:"code"`
+}
+
+// FromOrgDevices maps an org devices response into an envelope.
+func FromOrgDevices(endpoint string, response *abm.OrgDevicesResponse) Envelope {
+	env := Envelope{
+		Endpoint:   endpoint,
+		Data:       []Device{},
+		Pagination: &Pagination{},
+	}
+	if response == nil {
+		return env
+	}
+
+	for _, device := range response.Data {
+		env.Data = append(env.Data, fromOrgDevice(device))
+	}
+	env.Pagination.Next = response.Links.Next
+	if response.Meta != nil {
+		env.Pagination.Limit = response.Meta.Paging.Limit
+		env.Pagination.Total = response.Meta.Paging.Total
+	}
+
+	return env
+}
+
+// FromError maps a failure into an envelope.
+func FromError(endpoint string, err error) Envelope {
+	env := Envelope{
+		Endpoint: endpoint,
+		Data:     []Device{},
+		Error: &Error{
+			Message: err.Error(),
+		},
+	}
+
+	var apiErr *abm.APIError
+	if errors.As(err, &apiErr) {
+		env.Error.StatusCode = apiErr.StatusCode
+		if len(apiErr.Response.Errors) > 0 {
+			env.Error.Code = apiErr.Response.Errors[0].Code
+		}
+	}
+
+	return env
+}
+
+func fromOrgDevice(device abm.OrgDevice) Device {
+	out := Device{
+		ID:   device.ID,
+		IMEI: []string{},
+	}
+
+	attrs := device.Attributes
+	if attrs == nil {
+		return out
+	}
+
+	out.SerialNumber = attrs.SerialNumber
+	out.PartNumber = attrs.PartNumber
+	out.Status = attrs.Status.String()
+	out.ProductFamily = attrs.ProductFamily.String()
+	out.ProductType = attrs.ProductType
+	out.DeviceModel = attrs.DeviceModel
+	out.DeviceCapacity = attrs.DeviceCapacity
+	out.Color = attrs.Color
+	out.OrderNumber = attrs.OrderNumber
+	out.PurchaseSourceType = string(attrs.PurchaseSourceType)
+	out.PurchaseSourceID = attrs.PurchaseSourceID
+	out.IMEI = append(out.IMEI, attrs.IMEI...)
+	out.AddedToOrg = formatTime(attrs.AddedToOrgDateTime)
+	out.ReleasedFromOrg = formatTime(attrs.ReleasedFromOrgDateTime)
+	out.Updated = formatTime(attrs.UpdatedDateTime)
+
+	return out
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Write writes env to w as indented JSON followed by a newline.
+func Write(w io.Writer, env Envelope) error {
+	if err := json.MarshalWrite(w, env, jsontext.WithIndent("  ")); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+
+	return nil
+}
+
+// WriteIDs writes the ID of each resource in env to w, one per line,
+// suitable for piping into xargs.
+func WriteIDs(w io.Writer, env Envelope) error {
+	for _, device := range env.Data {
+		if _, err := fmt.Fprintln(w, device.ID); err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,205 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cliout
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/zchee/abm"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestWriteGolden(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	devices := &abm.OrgDevicesResponse{
+		Data: []abm.OrgDevice{
+			{
+				ID:   "device-1",
+				Type: "orgDevices",
+				Attributes: &abm.OrgDeviceAttributes{
+					SerialNumber:       "SERIAL-1",
+					PartNumber:         "MTV43LL/A",
+					Status:             abm.StatusAssigned,
+					ProductFamily:      abm.ProductFamilyIPhone,
+					ProductType:        "iPhone16,1",
+					DeviceModel:        "iPhone 15 Pro",
+					DeviceCapacity:     "256GB",
+					Color:              "BLACK",
+					OrderNumber:        "ORDER-1",
+					PurchaseSourceType: abm.PurchaseSourceTypeApple,
+					PurchaseSourceID:   "1234",
+					IMEI:               []string{"356789012345678"},
+					AddedToOrgDateTime: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+					UpdatedDateTime:    time.Date(2025, 2, 3, 4, 5, 6, 0, time.UTC),
+				},
+			},
+			{
+				ID:   "device-2",
+				Type: "orgDevices",
+			},
+		},
+		Links: abm.PagedDocumentLinks{
+			Self: "https://api-business.apple.com/v1/orgDevices",
+			Next: "https://api-business.apple.com/v1/orgDevices?cursor=abc",
+		},
+		Meta: &abm.PagingInformation{
+			Paging: abm.PagingInformationPaging{Limit: 2, Total: 3},
+		},
+	}
+
+	tests := map[string]struct {
+		env     Envelope
+		idsOnly bool
+	}{
+		"devices": {
+			env: FromOrgDevices("orgDevices", devices),
+		},
+		"devices_ids": {
+			env:     FromOrgDevices("orgDevices", devices),
+			idsOnly: true,
+		},
+		"empty": {
+			env: FromOrgDevices("orgDevices", &abm.OrgDevicesResponse{}),
+		},
+		"api_error": {
+			env: FromError("orgDevices", fmt.Errorf("get devices: %w", &abm.APIError{
+				StatusCode: 403,
+				Response: abm.ErrorResponse{Errors: []abm.ErrorResponseError{{
+					Code:   "FORBIDDEN",
+					Detail: "not allowed",
+				}}},
+			})),
+		},
+		"error": {
+			env: FromError("orgDevices", errors.New("client ID is required")),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			var buf bytes.Buffer
+			var err error
+			if tt.idsOnly {
+				err = WriteIDs(&buf, tt.env)
+			} else {
+				err = Write(&buf, tt.env)
+			}
+			if err != nil {
+				t.Fatalf("write returned error: %v", err)
+			}
+
+			golden := filepath.Join("testdata", name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+					t.Fatalf("update golden file: %v", err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("read golden file: %v", err)
+			}
+			if diff := cmp.Diff(string(want), buf.String()); diff != "" {
+				t.Fatalf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestOutputTypesIndependentOfAPITypes proves that renaming or retagging the
+// abm response types cannot change the CLI output: the output types never
+// embed or reference abm types, so every emitted JSON name is pinned here.
+func TestOutputTypesIndependentOfAPITypes(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	abmPkgPath := reflect.TypeFor[abm.OrgDevice]().PkgPath()
+
+	var names []string
+	var walk func(prefix string, typ reflect.Type)
+	walk = func(prefix string, typ reflect.Type) {
+		for typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Slice {
+			typ = typ.Elem()
+		}
+		if typ.PkgPath() == abmPkgPath {
+			t.Fatalf("output field %q uses abm type %s", prefix, typ)
+		}
+		if typ.Kind() != reflect.Struct {
+			return
+		}
+		for field := range typ.Fields() {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			names = append(names, prefix+name)
+			walk(prefix+name+".", field.Type)
+		}
+	}
+	walk("", reflect.TypeFor[Envelope]())
+
+	want := []string{
+		"endpoint",
+		"data",
+		"data.id",
+		"data.serialNumber",
+		"data.partNumber",
+		"data.status",
+		"data.productFamily",
+		"data.productType",
+		"data.deviceModel",
+		"data.deviceCapacity",
+		"data.color",
+		"data.orderNumber",
+		"data.purchaseSourceType",
+		"data.purchaseSourceId",
+		"data.imei",
+		"data.addedToOrg",
+		"data.releasedFromOrg",
+		"data.updated",
+		"pagination",
+		"pagination.next",
+		"pagination.limit",
+		"pagination.total",
+		"error",
+		"error.message",
+		"error.statusCode",
+		"error.code",
+	}
+	if diff := cmp.Diff(want, names); diff != "" {
+		t.Fatalf("stable output schema changed (-want +got):\n%s", diff)
+	}
+}
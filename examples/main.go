@@ -19,27 +19,31 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
 
-	"github.com/go-json-experiment/json"
-	"github.com/go-json-experiment/json/jsontext"
-
 	"github.com/zchee/abm"
+	"github.com/zchee/abm/examples/cliout"
 )
 
+const endpoint = "orgDevices"
+
 var (
 	clientID       string
 	keyID          string
 	privateKeyPath string
+	idsOnly        bool
 )
 
 func init() {
 	flag.StringVar(&clientID, "client-id", "", "ABM client id")
 	flag.StringVar(&keyID, "key-id", "", "ABM key id")
 	flag.StringVar(&privateKeyPath, "private-key", "", "path to private-key filepath, or raw private-key data")
+	flag.BoolVar(&idsOnly, "ids-only", false, "print one resource ID per line instead of the JSON envelope")
+	flag.BoolVar(&idsOnly, "quiet", false, "alias for -ids-only")
 }
 
 func main() {
@@ -58,33 +62,49 @@ func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
-	assertion, err := abm.NewAssertion(ctx, clientID, keyID, privateKeyPath)
+	out, err := fetchOrgDevices(ctx)
+	if err != nil {
+		if idsOnly {
+			fmt.Fprintln(os.Stderr, err)
+		} else if writeErr := cliout.Write(os.Stdout, cliout.FromError(endpoint, err)); writeErr != nil {
+			log.Print(writeErr)
+		}
+		cancel()
+		os.Exit(1)
+	}
+
+	env := cliout.FromOrgDevices(endpoint, out)
+	if idsOnly {
+		err = cliout.WriteIDs(os.Stdout, env)
+	} else {
+		err = cliout.Write(os.Stdout, env)
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
+}
+
+func fetchOrgDevices(ctx context.Context) (*abm.OrgDevicesResponse, error) {
+	assertion, err := abm.NewAssertion(ctx, clientID, keyID, privateKeyPath)
+	if err != nil {
+		return nil, err
+	}
 
 	ts, err := abm.NewTokenSource(ctx, nil, clientID, assertion, "")
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	client, err := abm.NewClient(nil, ts)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
-	out, err := client.GetOrgDevices(ctx, &abm.GetOrgDevicesOptions{
+	return client.OrgDevices().List(ctx, &abm.GetOrgDevicesOptions{
 		Fields: []string{
 			"partNumber",
 			"serialNumber",
 		},
 		Limit: 100,
 	})
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	if err := json.MarshalWrite(os.Stdout, out, jsontext.WithIndent("  ")); err != nil {
-		log.Fatal(err)
-	}
 }
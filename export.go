@@ -0,0 +1,240 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// defaultExportFlushEvery is the number of rows written between flushes of the CSV output.
+const defaultExportFlushEvery = 1000
+
+// ExportOptions contains optional parameters for [Client.ExportOrgDevicesCSV].
+type ExportOptions struct {
+	// Fields selects the org-device attributes exported as CSV columns, in
+	// order, using their API names such as "serialNumber". The device ID is
+	// always exported as the first column. Defaults to all attributes.
+	Fields []string
+
+	// Limit is the number of devices fetched per page. Defaults to the
+	// client's crawl page size, see [WithCrawlPageSize].
+	Limit int
+
+	// FlushEvery is the number of rows written between flushes of w.
+	// Defaults to 1000.
+	FlushEvery int
+}
+
+// orgDeviceExportFields lists the exportable org-device attributes in their default column order.
+var orgDeviceExportFields = []string{
+	"serialNumber",
+	"addedToOrgDateTime",
+	"releasedFromOrgDateTime",
+	"updatedDateTime",
+	"status",
+	"deviceModel",
+	"productFamily",
+	"productType",
+	"deviceCapacity",
+	"color",
+	"partNumber",
+	"orderNumber",
+	"orderDateTime",
+	"purchaseSourceType",
+	"purchaseSourceId",
+	"imei",
+	"meid",
+	"eid",
+	"wifiMacAddress",
+	"bluetoothMacAddress",
+	"ethernetMacAddress",
+}
+
+// orgDeviceExportColumns maps each exportable attribute to its CSV cell formatter.
+var orgDeviceExportColumns = map[string]func(*OrgDeviceAttributes) string{
+	"serialNumber":            func(a *OrgDeviceAttributes) string { return a.SerialNumber },
+	"addedToOrgDateTime":      func(a *OrgDeviceAttributes) string { return formatExportTime(a.AddedToOrgDateTime) },
+	"releasedFromOrgDateTime": func(a *OrgDeviceAttributes) string { return formatExportTime(a.ReleasedFromOrgDateTime) },
+	"updatedDateTime":         func(a *OrgDeviceAttributes) string { return formatExportTime(a.UpdatedDateTime) },
+	"status":                  func(a *OrgDeviceAttributes) string { return a.Status.String() },
+	"deviceModel":             func(a *OrgDeviceAttributes) string { return a.DeviceModel },
+	"productFamily":           func(a *OrgDeviceAttributes) string { return a.ProductFamily.String() },
+	"productType":             func(a *OrgDeviceAttributes) string { return a.ProductType },
+	"deviceCapacity":          func(a *OrgDeviceAttributes) string { return a.DeviceCapacity },
+	"color":                   func(a *OrgDeviceAttributes) string { return a.Color },
+	"partNumber":              func(a *OrgDeviceAttributes) string { return a.PartNumber },
+	"orderNumber":             func(a *OrgDeviceAttributes) string { return a.OrderNumber },
+	"orderDateTime":           func(a *OrgDeviceAttributes) string { return formatExportTime(a.OrderDateTime) },
+	"purchaseSourceType":      func(a *OrgDeviceAttributes) string { return a.PurchaseSourceType.String() },
+	"purchaseSourceId":        func(a *OrgDeviceAttributes) string { return a.PurchaseSourceID },
+	"imei":                    func(a *OrgDeviceAttributes) string { return strings.Join(a.IMEI, ";") },
+	"meid":                    func(a *OrgDeviceAttributes) string { return strings.Join(a.MEID, ";") },
+	"eid":                     func(a *OrgDeviceAttributes) string { return a.EID },
+	"wifiMacAddress":          func(a *OrgDeviceAttributes) string { return strings.Join(a.WifiMacAddress, ";") },
+	"bluetoothMacAddress":     func(a *OrgDeviceAttributes) string { return strings.Join(a.BluetoothMacAddress, ";") },
+	"ethernetMacAddress":      func(a *OrgDeviceAttributes) string { return strings.Join(a.EthernetMacAddress, ";") },
+}
+
+// ExportOrgDevicesCSV writes all organization devices to w as CSV, one row per
+// device, and returns the number of device rows written.
+//
+// Devices are written page by page as they are fetched, so memory usage stays
+// bounded by the page size regardless of the fleet size. Multi-valued
+// attributes such as IMEI are joined with ";" and times are formatted as RFC 3339.
+// When an error occurs mid-export, the rows written so far are flushed and
+// counted in the returned row count.
+func (c *Client) ExportOrgDevicesCSV(ctx context.Context, w io.Writer, options *ExportOptions) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if w == nil {
+		return 0, fmt.Errorf("writer is required")
+	}
+	if options == nil {
+		options = &ExportOptions{}
+	}
+
+	fields := options.Fields
+	if len(fields) == 0 {
+		fields = orgDeviceExportFields
+	}
+	columns, err := orgDeviceCSVColumns(fields)
+	if err != nil {
+		return 0, err
+	}
+
+	flushEvery := options.FlushEvery
+	if flushEvery <= 0 {
+		flushEvery = defaultExportFlushEvery
+	}
+
+	query, err := buildFieldsAndLimitQuery("fields[orgDevices]", fields, c.crawlLimit(options.Limit))
+	if err != nil {
+		return 0, err
+	}
+
+	cw := csv.NewWriter(w)
+	record := make([]string, 0, len(fields)+1)
+	record = append(append(record, "id"), fields...)
+	if err := cw.Write(record); err != nil {
+		return 0, fmt.Errorf("write csv header: %w", err)
+	}
+
+	rows := 0
+	for page, err := range crawlPages(ctx, c, orgDevicesPath, query, func(r *OrgDevicesResponse) string { return r.Links.Next }) {
+		if err != nil {
+			cw.Flush()
+			return rows, err
+		}
+
+		for _, device := range page.Data {
+			if err := writeOrgDeviceCSVRow(cw, record, columns, device); err != nil {
+				return rows, err
+			}
+			rows++
+
+			if rows%flushEvery == 0 {
+				cw.Flush()
+				if err := cw.Error(); err != nil {
+					return rows, fmt.Errorf("flush csv: %w", err)
+				}
+			}
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return rows, fmt.Errorf("flush csv: %w", err)
+	}
+
+	return rows, nil
+}
+
+// WriteOrgDevicesCSV writes devices to w as CSV rows in the format of
+// [Client.ExportOrgDevicesCSV], for callers that crawl the devices themselves.
+// Each row is the device ID followed by the attributes named by columns, which
+// defaults to all attributes. It writes neither the header row, which is "id"
+// followed by columns, nor flushes w. Devices with nil attributes are written
+// with empty attribute cells.
+func WriteOrgDevicesCSV(w *csv.Writer, devices []OrgDevice, columns []string) error {
+	if w == nil {
+		return fmt.Errorf("writer is required")
+	}
+	if len(columns) == 0 {
+		columns = orgDeviceExportFields
+	}
+	formatters, err := orgDeviceCSVColumns(columns)
+	if err != nil {
+		return err
+	}
+
+	record := make([]string, len(columns)+1)
+	for _, device := range devices {
+		if err := writeOrgDeviceCSVRow(w, record, formatters, device); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// orgDeviceCSVColumns returns the cell formatters of the exportable attributes
+// named by fields.
+func orgDeviceCSVColumns(fields []string) ([]func(*OrgDeviceAttributes) string, error) {
+	columns := make([]func(*OrgDeviceAttributes) string, len(fields))
+	for i, field := range fields {
+		column, ok := orgDeviceExportColumns[field]
+		if !ok {
+			return nil, fmt.Errorf("unsupported export field %q", field)
+		}
+		columns[i] = column
+	}
+
+	return columns, nil
+}
+
+// writeOrgDeviceCSVRow writes the row of device to cw, using record, which
+// has one more element than columns, as scratch space.
+func writeOrgDeviceCSVRow(cw *csv.Writer, record []string, columns []func(*OrgDeviceAttributes) string, device OrgDevice) error {
+	attributes := device.Attributes
+	if attributes == nil {
+		attributes = &OrgDeviceAttributes{}
+	}
+
+	record[0] = device.ID
+	for i, column := range columns {
+		record[i+1] = column(attributes)
+	}
+	if err := cw.Write(record); err != nil {
+		return fmt.Errorf("write csv row: %w", err)
+	}
+
+	return nil
+}
+
+func formatExportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
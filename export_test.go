@@ -0,0 +1,286 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// newOrgDevicesFleetServer serves pageCount pages of pageSize org devices,
+// linked through relative next links.
+func newOrgDevicesFleetServer(tb testing.TB, pageCount, pageSize int) *httptest.Server {
+	tb.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pageNumber := 1
+		if page := r.URL.Query().Get("page"); page != "" {
+			parsed, err := strconv.Atoi(page)
+			if err != nil || parsed < 1 || parsed > pageCount {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			pageNumber = parsed
+		}
+
+		nextLink := ""
+		if pageNumber < pageCount {
+			nextLink = fmt.Sprintf("/v1/orgDevices?page=%d", pageNumber+1)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(buildOrgDevicesPageJSON(pageNumber, pageSize, nextLink))
+	}))
+	tb.Cleanup(server.Close)
+
+	return server
+}
+
+func TestClient_ExportOrgDevicesCSV(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	const (
+		pageCount = 10
+		pageSize  = 25
+	)
+
+	server := newOrgDevicesFleetServer(t, pageCount, pageSize)
+	client := testClientForServer(t, server)
+
+	var buf bytes.Buffer
+	rows, err := client.ExportOrgDevicesCSV(ctx, &buf, &ExportOptions{
+		Fields:     []string{"serialNumber", "partNumber", "status", "orderDateTime", "imei"},
+		FlushEvery: 7,
+	})
+	if err != nil {
+		t.Fatalf("ExportOrgDevicesCSV returned error: %v", err)
+	}
+	if diff := cmp.Diff(pageCount*pageSize, rows); diff != "" {
+		t.Fatalf("row count mismatch (-want +got):\n%s", diff)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("read exported csv: %v", err)
+	}
+	if diff := cmp.Diff(pageCount*pageSize+1, len(records)); diff != "" {
+		t.Fatalf("record count mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"id", "serialNumber", "partNumber", "status", "orderDateTime", "imei"}, records[0]); diff != "" {
+		t.Fatalf("header mismatch (-want +got):\n%s", diff)
+	}
+
+	wantFirst := []string{"device-1-1", "SER-1-1", "PART-0001-00001", "ASSIGNED", "2026-01-02T03:04:05Z", "123456789012345;123456789012346"}
+	if diff := cmp.Diff(wantFirst, records[1]); diff != "" {
+		t.Fatalf("first row mismatch (-want +got):\n%s", diff)
+	}
+	wantLast := []string{"device-10-25", "SER-10-25", "PART-0010-00025", "ASSIGNED", "2026-01-02T03:04:05Z", "123456789012345;123456789012346"}
+	if diff := cmp.Diff(wantLast, records[len(records)-1]); diff != "" {
+		t.Fatalf("last row mismatch (-want +got):\n%s", diff)
+	}
+
+	for i, record := range records[1:] {
+		wantID := fmt.Sprintf("device-%d-%d", i/pageSize+1, i%pageSize+1)
+		if diff := cmp.Diff(wantID, record[0]); diff != "" {
+			t.Fatalf("row %d id mismatch (-want +got):\n%s", i, diff)
+		}
+	}
+}
+
+func TestClient_ExportOrgDevicesCSVErrors(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		nilWriter bool
+		options   *ExportOptions
+		failPage  int
+		wantRows  int
+		wantErr   string
+	}{
+		"error: nil writer": {
+			nilWriter: true,
+			wantErr:   "writer is required",
+		},
+		"error: unsupported field": {
+			options: &ExportOptions{Fields: []string{"serialNumber", "owner"}},
+			wantErr: `unsupported export field "owner"`,
+		},
+		"error: invalid limit": {
+			options: &ExportOptions{Limit: maxPageLimit + 1},
+			wantErr: "limit 1001 is out of range [0, 1000]",
+		},
+		"error: page failure keeps written rows": {
+			failPage: 3,
+			wantRows: 20,
+			wantErr:  "abm api error: status=500",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			var requests int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests == tt.failPage {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(buildOrgDevicesPageJSON(requests, 10, fmt.Sprintf("/v1/orgDevices?page=%d", requests+1)))
+			}))
+			t.Cleanup(server.Close)
+			client := testClientForServer(t, server)
+
+			var buf bytes.Buffer
+			var w io.Writer = &buf
+			if tt.nilWriter {
+				w = nil
+			}
+
+			rows, err := client.ExportOrgDevicesCSV(ctx, w, tt.options)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if diff := cmp.Diff(tt.wantRows, rows); diff != "" {
+				t.Fatalf("row count mismatch (-want +got):\n%s", diff)
+			}
+			if tt.wantRows > 0 {
+				if got := strings.Count(buf.String(), "\n"); got != tt.wantRows+1 {
+					t.Fatalf("flushed line count mismatch: got=%d want=%d", got, tt.wantRows+1)
+				}
+			}
+		})
+	}
+}
+
+// errWriter fails every write.
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestClient_ExportOrgDevicesCSVWriteError(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	server := newOrgDevicesFleetServer(t, 1, 5)
+	client := testClientForServer(t, server)
+
+	_, err := client.ExportOrgDevicesCSV(ctx, errWriter{}, nil)
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("expected write error, got %v", err)
+	}
+}
+
+func TestWriteOrgDevicesCSV(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	devices := []OrgDevice{
+		{ID: "device-1", Attributes: &OrgDeviceAttributes{
+			SerialNumber:   "SER-1",
+			Status:         StatusAssigned,
+			ProductFamily:  ProductFamilyMac,
+			OrderDateTime:  time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC),
+			WifiMacAddress: []string{"00:11:22:33:44:55", "00:11:22:33:44:56"},
+		}},
+		{ID: "device-2"},
+	}
+
+	tests := map[string]struct {
+		columns []string
+		want    [][]string
+		wantErr string
+	}{
+		"success: selected columns": {
+			columns: []string{"serialNumber", "status", "productFamily", "orderDateTime", "wifiMacAddress"},
+			want: [][]string{
+				{"device-1", "SER-1", "ASSIGNED", "Mac", "2026-01-02T03:04:05Z", "00:11:22:33:44:55;00:11:22:33:44:56"},
+				{"device-2", "", "", "", "", ""},
+			},
+		},
+		"success: default columns": {
+			columns: nil,
+			want: [][]string{
+				{"device-1", "SER-1", "", "", "", "ASSIGNED", "", "Mac", "", "", "", "", "", "2026-01-02T03:04:05Z", "", "", "", "", "", "00:11:22:33:44:55;00:11:22:33:44:56", "", ""},
+				append([]string{"device-2"}, make([]string, len(orgDeviceExportFields))...),
+			},
+		},
+		"error: unsupported column": {
+			columns: []string{"serialNumber", "nickname"},
+			wantErr: `unsupported export field "nickname"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			var buf bytes.Buffer
+			cw := csv.NewWriter(&buf)
+			err := WriteOrgDevicesCSV(cw, devices, tt.columns)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("WriteOrgDevicesCSV error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WriteOrgDevicesCSV returned error: %v", err)
+			}
+			cw.Flush()
+
+			records, err := csv.NewReader(&buf).ReadAll()
+			if err != nil {
+				t.Fatalf("read written csv: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, records); diff != "" {
+				t.Fatalf("records mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// failoverReprobeInterval is how long a failover token source uses the
+// secondary source before it tries the primary source again.
+const failoverReprobeInterval = 5 * time.Minute
+
+// failoverTokenSource is the token source returned by [NewFailoverTokenSource].
+type failoverTokenSource struct {
+	primary   oauth2.TokenSource
+	secondary oauth2.TokenSource
+	classify  func(error) bool
+
+	reprobeInterval time.Duration
+	now             func() time.Time
+
+	mu sync.Mutex
+	// failedOver reports whether tokens come from the secondary source.
+	failedOver bool
+	// probedAt is when the primary source last failed.
+	probedAt time.Time
+	// probing reports whether a call is re-probing the primary source, so
+	// concurrent calls do not probe it at the same time.
+	probing bool
+}
+
+// NewFailoverTokenSource returns a token source that takes tokens from
+// primary and switches to secondary when primary fails with a credential
+// error, for rotating the API key without downtime. classify reports whether
+// an error is a credential error; when it is nil, [IsTokenCredentialError] is
+// used. Other failures of primary, such as network errors, are returned
+// without failing over.
+//
+// The switch is remembered, and primary is tried again once every five
+// minutes; the first token it returns switches back. The returned source is
+// safe for concurrent use. It does not cache tokens itself, so primary and
+// secondary should, as those returned by [NewTokenSource] do.
+func NewFailoverTokenSource(primary, secondary oauth2.TokenSource, classify func(error) bool) (oauth2.TokenSource, error) {
+	if primary == nil {
+		return nil, fmt.Errorf("primary token source is required")
+	}
+	if secondary == nil {
+		return nil, fmt.Errorf("secondary token source is required")
+	}
+	if classify == nil {
+		classify = IsTokenCredentialError
+	}
+
+	return &failoverTokenSource{
+		primary:         primary,
+		secondary:       secondary,
+		classify:        classify,
+		reprobeInterval: failoverReprobeInterval,
+		now:             time.Now,
+	}, nil
+}
+
+// Token implements [oauth2.TokenSource].
+func (s *failoverTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	failedOver := s.failedOver
+	probe := failedOver && !s.probing && s.now().Sub(s.probedAt) >= s.reprobeInterval
+	if probe {
+		s.probing = true
+	}
+	s.mu.Unlock()
+
+	var primaryErr error
+	if !failedOver || probe {
+		token, err := s.primary.Token()
+		credentialErr := err != nil && s.classify(err)
+
+		s.mu.Lock()
+		if probe {
+			s.probing = false
+		}
+		switch {
+		case err == nil:
+			s.failedOver = false
+		case credentialErr || probe:
+			s.failedOver, s.probedAt = true, s.now()
+		}
+		s.mu.Unlock()
+
+		if err == nil {
+			return token, nil
+		}
+		if !credentialErr && !probe {
+			return nil, err
+		}
+		primaryErr = err
+	}
+
+	token, err := s.secondary.Token()
+	if err != nil {
+		if primaryErr != nil {
+			return nil, errors.Join(fmt.Errorf("primary token source: %w", primaryErr), fmt.Errorf("secondary token source: %w", err))
+		}
+		return nil, fmt.Errorf("secondary token source: %w", err)
+	}
+
+	return token, nil
+}
+
+// IsTokenCredentialError reports whether err is a token endpoint rejection
+// of the client's credentials: an invalid_client, unauthorized_client, or
+// invalid_grant error, or a 401 Unauthorized response. Such errors do not go
+// away on retry, unlike network errors and server errors.
+func IsTokenCredentialError(err error) bool {
+	var retrieveErr *oauth2.RetrieveError
+	if !errors.As(err, &retrieveErr) {
+		return false
+	}
+
+	switch retrieveErr.ErrorCode {
+	case "invalid_client", "unauthorized_client", "invalid_grant":
+		return true
+	}
+
+	return retrieveErr.Response != nil && retrieveErr.Response.StatusCode == http.StatusUnauthorized
+}
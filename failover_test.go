@@ -0,0 +1,329 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/oauth2"
+)
+
+// fakeTokenEndpoint is a token endpoint that issues accessToken while it is
+// healthy and rejects the client with invalid_client otherwise.
+type fakeTokenEndpoint struct {
+	server   *httptest.Server
+	healthy  atomic.Bool
+	requests atomic.Int32
+}
+
+func newFakeTokenEndpoint(t *testing.T, accessToken string, healthy bool) *fakeTokenEndpoint {
+	t.Helper()
+
+	endpoint := &fakeTokenEndpoint{}
+	endpoint.healthy.Store(healthy)
+	endpoint.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpoint.requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		if !endpoint.healthy.Load() {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"invalid_client"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"access_token":%q,"token_type":"Bearer","expires_in":3600}`, accessToken)
+	}))
+	t.Cleanup(endpoint.server.Close)
+
+	return endpoint
+}
+
+func (e *fakeTokenEndpoint) tokenSource(t *testing.T) oauth2.TokenSource {
+	t.Helper()
+
+	source, err := NewTokenSource(t.Context(), e.server.Client(), "client-id", "assertion", ScopeBusinessAPI, WithTokenURL(e.server.URL), WithTokenMaxRetries(0))
+	if err != nil {
+		t.Fatalf("NewTokenSource returned error: %v", err)
+	}
+
+	return source
+}
+
+func TestFailoverTokenSource(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	type step struct {
+		advance        time.Duration
+		primaryHealthy bool
+		wantToken      string
+		wantErr        bool
+	}
+
+	tests := map[string]struct {
+		primaryHealthy     bool
+		primaryUnreachable bool
+		steps              []step
+		wantPrimary        int32
+		wantSecondary      int32
+	}{
+		"success: primary healthy": {
+			primaryHealthy: true,
+			steps: []step{
+				{primaryHealthy: true, wantToken: "primary"},
+				{primaryHealthy: true, wantToken: "primary"},
+			},
+			wantPrimary:   1,
+			wantSecondary: 0,
+		},
+		"success: primary credential dead fails over and sticks": {
+			steps: []step{
+				{wantToken: "secondary"},
+				{advance: time.Minute, wantToken: "secondary"},
+				{advance: time.Minute, primaryHealthy: true, wantToken: "secondary"},
+			},
+			wantPrimary:   1,
+			wantSecondary: 1,
+		},
+		"error: primary network dead does not fail over": {
+			primaryUnreachable: true,
+			steps: []step{
+				{wantErr: true},
+				{wantErr: true},
+			},
+			wantSecondary: 0,
+		},
+		"success: re-probe recovers primary": {
+			steps: []step{
+				{wantToken: "secondary"},
+				{advance: failoverReprobeInterval, wantToken: "secondary"},
+				{advance: time.Minute, primaryHealthy: true, wantToken: "secondary"},
+				{advance: failoverReprobeInterval, primaryHealthy: true, wantToken: "primary"},
+				{primaryHealthy: true, wantToken: "primary"},
+			},
+			wantPrimary:   3,
+			wantSecondary: 1,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			primary := newFakeTokenEndpoint(t, "primary", tt.primaryHealthy)
+			secondary := newFakeTokenEndpoint(t, "secondary", true)
+			primarySource := primary.tokenSource(t)
+			if tt.primaryUnreachable {
+				primary.server.Close()
+			}
+
+			source, err := NewFailoverTokenSource(primarySource, secondary.tokenSource(t), nil)
+			if err != nil {
+				t.Fatalf("NewFailoverTokenSource returned error: %v", err)
+			}
+			now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+			source.(*failoverTokenSource).now = func() time.Time { return now }
+
+			for i, s := range tt.steps {
+				now = now.Add(s.advance)
+				primary.healthy.Store(s.primaryHealthy)
+
+				token, err := source.Token()
+				if s.wantErr {
+					if err == nil {
+						t.Fatalf("step %d: expected error", i)
+					}
+					if IsTokenCredentialError(err) {
+						t.Fatalf("step %d: expected a non-credential error, got %v", i, err)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("step %d: Token returned error: %v", i, err)
+				}
+				if diff := cmp.Diff(s.wantToken, token.AccessToken); diff != "" {
+					t.Fatalf("step %d: access token mismatch (-want +got):\n%s", i, diff)
+				}
+			}
+
+			if !tt.primaryUnreachable {
+				if diff := cmp.Diff(tt.wantPrimary, primary.requests.Load()); diff != "" {
+					t.Fatalf("primary token requests mismatch (-want +got):\n%s", diff)
+				}
+			}
+			if diff := cmp.Diff(tt.wantSecondary, secondary.requests.Load()); diff != "" {
+				t.Fatalf("secondary token requests mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFailoverTokenSourceConcurrent(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	primary := newFakeTokenEndpoint(t, "primary", false)
+	secondary := newFakeTokenEndpoint(t, "secondary", true)
+	source, err := NewFailoverTokenSource(primary.tokenSource(t), secondary.tokenSource(t), nil)
+	if err != nil {
+		t.Fatalf("NewFailoverTokenSource returned error: %v", err)
+	}
+
+	const callers = 16
+	tokens := make([]string, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Go(func() {
+			token, err := source.Token()
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			tokens[i] = token.AccessToken
+		})
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		t.Fatalf("Token returned error: %v", err)
+	}
+	for i, token := range tokens {
+		if diff := cmp.Diff("secondary", token); diff != "" {
+			t.Fatalf("caller %d: access token mismatch (-want +got):\n%s", i, diff)
+		}
+	}
+
+	// Once failed over, the primary source is left alone until the re-probe.
+	before := primary.requests.Load()
+	if _, err := source.Token(); err != nil {
+		t.Fatalf("Token returned error: %v", err)
+	}
+	if diff := cmp.Diff(before, primary.requests.Load()); diff != "" {
+		t.Fatalf("primary token requests mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewFailoverTokenSourceErrors(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	static := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "abc123"})
+	tests := map[string]struct {
+		primary   oauth2.TokenSource
+		secondary oauth2.TokenSource
+	}{
+		"error: nil primary": {
+			secondary: static,
+		},
+		"error: nil secondary": {
+			primary: static,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			if _, err := NewFailoverTokenSource(tt.primary, tt.secondary, nil); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestIsTokenCredentialError(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		err  error
+		want bool
+	}{
+		"success: invalid client": {
+			err: fmt.Errorf("token request: %w", &oauth2.RetrieveError{
+				Response:  &http.Response{StatusCode: http.StatusBadRequest},
+				ErrorCode: "invalid_client",
+			}),
+			want: true,
+		},
+		"success: invalid grant": {
+			err: &oauth2.RetrieveError{
+				Response:  &http.Response{StatusCode: http.StatusBadRequest},
+				ErrorCode: "invalid_grant",
+			},
+			want: true,
+		},
+		"success: unauthorized": {
+			err: &oauth2.RetrieveError{
+				Response: &http.Response{StatusCode: http.StatusUnauthorized},
+			},
+			want: true,
+		},
+		"success: server error": {
+			err: &oauth2.RetrieveError{
+				Response: &http.Response{StatusCode: http.StatusServiceUnavailable},
+			},
+		},
+		"success: network error": {
+			err: &url.Error{
+				Op:  "Post",
+				URL: TokenURL,
+				Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")},
+			},
+		},
+		"success: canceled context": {
+			err: fmt.Errorf("token request: %w", context.Canceled),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, IsTokenCredentialError(tt.err)); diff != "" {
+				t.Fatalf("IsTokenCredentialError mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
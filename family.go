@@ -0,0 +1,168 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/oauth2"
+)
+
+// ServiceFamily identifies the Apple service an API host belongs to.
+type ServiceFamily string
+
+const (
+	// ServiceFamilyUnknown is any host other than the Apple Business Manager
+	// and Apple School Manager API hosts, such as a proxy or test server.
+	ServiceFamilyUnknown ServiceFamily = "unknown"
+
+	// ServiceFamilyBusiness is the Apple Business Manager API.
+	ServiceFamilyBusiness ServiceFamily = "business"
+
+	// ServiceFamilySchool is the Apple School Manager API.
+	ServiceFamilySchool ServiceFamily = "school"
+)
+
+// Apple API hosts per service family.
+const (
+	businessAPIHost = "api-business.apple.com"
+	schoolAPIHost   = "api-school.apple.com"
+)
+
+// ServiceFamilyFromURL returns the service family of the host of rawURL.
+// It returns [ServiceFamilyUnknown] for other hosts and unparsable URLs.
+func ServiceFamilyFromURL(rawURL string) ServiceFamily {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return ServiceFamilyUnknown
+	}
+
+	switch strings.ToLower(u.Hostname()) {
+	case businessAPIHost:
+		return ServiceFamilyBusiness
+	case schoolAPIHost:
+		return ServiceFamilySchool
+	default:
+		return ServiceFamilyUnknown
+	}
+}
+
+// scopeServiceFamily returns the service family whose API scope is in
+// scopes, or [ServiceFamilyUnknown] when neither or both are.
+func scopeServiceFamily(scopes []string) ServiceFamily {
+	business := slices.Contains(scopes, ScopeBusinessAPI)
+	school := slices.Contains(scopes, scopeSchoolAPI)
+	switch {
+	case business && !school:
+		return ServiceFamilyBusiness
+	case school && !business:
+		return ServiceFamilySchool
+	default:
+		return ServiceFamilyUnknown
+	}
+}
+
+// serviceFamilyHint returns a hint explaining an authorization or not-found
+// error caused by using one service family's credentials against another
+// family's host. The Business and School APIs share their paths, so the
+// families are told apart by the token's scope. It returns "" when the
+// families agree or either is unknown, so custom hosts never receive a hint.
+func serviceFamilyHint(hostFamily, tokenFamily ServiceFamily, statusCode int) string {
+	if !hintStatus(statusCode) {
+		return ""
+	}
+
+	if hostFamily == ServiceFamilyUnknown || tokenFamily == ServiceFamilyUnknown || hostFamily == tokenFamily {
+		return ""
+	}
+
+	return "the access token has the " + serviceFamilyName(tokenFamily) + " API scope but the base URL is the " + serviceFamilyName(hostFamily) + " host; check the base URL and the credentials' scope"
+}
+
+// serviceFamilyHint returns the hint for an error response with statusCode,
+// see [serviceFamilyHint]. The token's family is the one recorded when the
+// client last obtained a token; no token is requested just for the hint.
+func (c *Client) serviceFamilyHint(statusCode int) string {
+	if c.serviceFamily == ServiceFamilyUnknown || !hintStatus(statusCode) {
+		return ""
+	}
+
+	return serviceFamilyHint(c.serviceFamily, c.tokenFamily.family(), statusCode)
+}
+
+// tokenFamilySource is an [oauth2.TokenSource] that records the service
+// family of the scope granted with the last token it returned.
+type tokenFamilySource struct {
+	src oauth2.TokenSource
+
+	// last is the family of the last token, nil until a token with a scope
+	// field was returned.
+	last atomic.Pointer[ServiceFamily]
+}
+
+// Token implements [oauth2.TokenSource].
+func (s *tokenFamilySource) Token() (*oauth2.Token, error) {
+	token, err := s.src.Token()
+	if err != nil {
+		return nil, err
+	}
+	if scopes, ok := tokenScopes(token); ok {
+		family := scopeServiceFamily(scopes)
+		s.last.Store(&family)
+	}
+
+	return token, nil
+}
+
+// family returns the recorded service family, or [ServiceFamilyUnknown] when
+// no token with a scope field was returned yet or s is nil.
+func (s *tokenFamilySource) family() ServiceFamily {
+	if s == nil {
+		return ServiceFamilyUnknown
+	}
+	if family := s.last.Load(); family != nil {
+		return *family
+	}
+
+	return ServiceFamilyUnknown
+}
+
+// hintStatus reports whether a response with statusCode may be caused by a
+// service family mismatch.
+func hintStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		return true
+	default:
+		return false
+	}
+}
+
+func serviceFamilyName(family ServiceFamily) string {
+	switch family {
+	case ServiceFamilyBusiness:
+		return "Business"
+	case ServiceFamilySchool:
+		return "School"
+	default:
+		return string(family)
+	}
+}
@@ -0,0 +1,325 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/oauth2"
+)
+
+func TestServiceFamilyFromURL(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		rawURL string
+		want   ServiceFamily
+	}{
+		"success: business host": {
+			rawURL: "https://api-business.apple.com/",
+			want:   ServiceFamilyBusiness,
+		},
+		"success: school host": {
+			rawURL: "https://api-school.apple.com/v1/orgDevices",
+			want:   ServiceFamilySchool,
+		},
+		"success: host with port and mixed case": {
+			rawURL: "https://API-School.apple.com:443/",
+			want:   ServiceFamilySchool,
+		},
+		"success: custom host": {
+			rawURL: "http://127.0.0.1:8080/",
+			want:   ServiceFamilyUnknown,
+		},
+		"success: lookalike host": {
+			rawURL: "https://api-business.apple.com.example.test/",
+			want:   ServiceFamilyUnknown,
+		},
+		"success: invalid url": {
+			rawURL: "://bad-url",
+			want:   ServiceFamilyUnknown,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, ServiceFamilyFromURL(tt.rawURL)); diff != "" {
+				t.Fatalf("ServiceFamilyFromURL mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServiceFamilyHint(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	const (
+		schoolHostHint   = "the access token has the Business API scope but the base URL is the School host; check the base URL and the credentials' scope"
+		businessHostHint = "the access token has the School API scope but the base URL is the Business host; check the base URL and the credentials' scope"
+	)
+
+	tests := map[string]struct {
+		hostFamily  ServiceFamily
+		tokenFamily ServiceFamily
+		statusCode  int
+		want        string
+	}{
+		"success: business token on business host": {
+			hostFamily:  ServiceFamilyBusiness,
+			tokenFamily: ServiceFamilyBusiness,
+			statusCode:  http.StatusUnauthorized,
+		},
+		"success: school token on school host not found": {
+			hostFamily:  ServiceFamilySchool,
+			tokenFamily: ServiceFamilySchool,
+			statusCode:  http.StatusNotFound,
+		},
+		"success: business token on school host unauthorized": {
+			hostFamily:  ServiceFamilySchool,
+			tokenFamily: ServiceFamilyBusiness,
+			statusCode:  http.StatusUnauthorized,
+			want:        schoolHostHint,
+		},
+		"success: business token on school host forbidden": {
+			hostFamily:  ServiceFamilySchool,
+			tokenFamily: ServiceFamilyBusiness,
+			statusCode:  http.StatusForbidden,
+			want:        schoolHostHint,
+		},
+		"success: school token on business host not found": {
+			hostFamily:  ServiceFamilyBusiness,
+			tokenFamily: ServiceFamilySchool,
+			statusCode:  http.StatusNotFound,
+			want:        businessHostHint,
+		},
+		"success: mismatch with other status": {
+			hostFamily:  ServiceFamilySchool,
+			tokenFamily: ServiceFamilyBusiness,
+			statusCode:  http.StatusInternalServerError,
+		},
+		"success: unknown token scope": {
+			hostFamily:  ServiceFamilySchool,
+			tokenFamily: ServiceFamilyUnknown,
+			statusCode:  http.StatusNotFound,
+		},
+		"success: custom host suppresses hint": {
+			hostFamily:  ServiceFamilyUnknown,
+			tokenFamily: ServiceFamilyBusiness,
+			statusCode:  http.StatusUnauthorized,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, serviceFamilyHint(tt.hostFamily, tt.tokenFamily, tt.statusCode)); diff != "" {
+				t.Fatalf("serviceFamilyHint mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestScopeServiceFamily(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		scopes []string
+		want   ServiceFamily
+	}{
+		"success: business scope": {
+			scopes: []string{ScopeBusinessAPI},
+			want:   ServiceFamilyBusiness,
+		},
+		"success: school scope": {
+			scopes: []string{"school.api"},
+			want:   ServiceFamilySchool,
+		},
+		"success: both scopes": {
+			scopes: []string{ScopeBusinessAPI, "school.api"},
+			want:   ServiceFamilyUnknown,
+		},
+		"success: no scope": {
+			want: ServiceFamilyUnknown,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, scopeServiceFamily(tt.scopes)); diff != "" {
+				t.Fatalf("scopeServiceFamily mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_APIErrorServiceFamilyHint(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+
+	httpClient, err := newTLSServerHTTPClient(server)
+	if err != nil {
+		t.Fatalf("newTLSServerHTTPClient returned error: %v", err)
+	}
+
+	tests := map[string]struct {
+		baseURL  string
+		scope    string
+		wantHint bool
+	}{
+		"success: business credentials on school host": {
+			baseURL:  "https://api-school.apple.com/",
+			scope:    ScopeBusinessAPI,
+			wantHint: true,
+		},
+		"success: school credentials on business host": {
+			baseURL:  DefaultAPIBaseURL,
+			scope:    "school.api",
+			wantHint: true,
+		},
+		"success: school credentials on school host": {
+			baseURL: "https://api-school.apple.com/",
+			scope:   "school.api",
+		},
+		"success: business credentials on business host": {
+			baseURL: DefaultAPIBaseURL,
+			scope:   ScopeBusinessAPI,
+		},
+		"success: token without scope": {
+			baseURL: "https://api-school.apple.com/",
+		},
+		"success: custom test host": {
+			baseURL: server.URL,
+			scope:   "school.api",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			token := &oauth2.Token{AccessToken: "test-token"}
+			if tt.scope != "" {
+				token = token.WithExtra(map[string]any{"scope": tt.scope})
+			}
+			client, err := NewClientWithBaseURL(httpClient, oauth2.StaticTokenSource(token), tt.baseURL)
+			if err != nil {
+				t.Fatalf("NewClientWithBaseURL returned error: %v", err)
+			}
+
+			_, err = client.GetOrgDevices(ctx, nil)
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected *APIError, got %v", err)
+			}
+			if diff := cmp.Diff(tt.wantHint, apiErr.Hint != ""); diff != "" {
+				t.Fatalf("hint presence mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantHint, strings.Contains(err.Error(), "hint: ")); diff != "" {
+				t.Fatalf("hint in message mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_APIErrorServiceFamilyHint_NoTokenFetch(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	t.Cleanup(server.Close)
+
+	httpClient, err := newTLSServerHTTPClient(server)
+	if err != nil {
+		t.Fatalf("newTLSServerHTTPClient returned error: %v", err)
+	}
+
+	var calls atomic.Int32
+	token := (&oauth2.Token{AccessToken: "test-token"}).WithExtra(map[string]any{"scope": ScopeBusinessAPI})
+	source := tokenSourceFunc(func() (*oauth2.Token, error) {
+		calls.Add(1)
+		return token, nil
+	})
+	client, err := NewClientWithBaseURL(httpClient, source, "https://api-school.apple.com/")
+	if err != nil {
+		t.Fatalf("NewClientWithBaseURL returned error: %v", err)
+	}
+
+	_, err = client.GetOrgDevices(ctx, nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if apiErr.Hint == "" {
+		t.Fatal("expected a service family hint")
+	}
+	// The hint uses the scope of the token the request was authorized with;
+	// building it must not fetch another token.
+	if diff := cmp.Diff(int32(1), calls.Load()); diff != "" {
+		t.Fatalf("token fetches mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// tokenSourceFunc adapts a function to an [oauth2.TokenSource].
+type tokenSourceFunc func() (*oauth2.Token, error)
+
+// Token implements [oauth2.TokenSource].
+func (f tokenSourceFunc) Token() (*oauth2.Token, error) {
+	return f()
+}
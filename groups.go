@@ -0,0 +1,83 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import "time"
+
+// GroupByProductFamily groups devices by product family, keeping the input
+// order within each group. Devices with nil attributes are grouped under the
+// empty family.
+func GroupByProductFamily(devices []OrgDevice) map[OrgDeviceAttributesProductFamily][]OrgDevice {
+	groups := make(map[OrgDeviceAttributesProductFamily][]OrgDevice)
+	for _, device := range devices {
+		var family OrgDeviceAttributesProductFamily
+		if device.Attributes != nil {
+			family = device.Attributes.ProductFamily
+		}
+		groups[family] = append(groups[family], device)
+	}
+
+	return groups
+}
+
+// GroupByStatus groups devices by assignment status, keeping the input order
+// within each group. Devices with nil attributes are grouped under the empty
+// status.
+func GroupByStatus(devices []OrgDevice) map[OrgDeviceAttributesStatus][]OrgDevice {
+	groups := make(map[OrgDeviceAttributesStatus][]OrgDevice)
+	for _, device := range devices {
+		var status OrgDeviceAttributesStatus
+		if device.Attributes != nil {
+			status = device.Attributes.Status
+		}
+		groups[status] = append(groups[status], device)
+	}
+
+	return groups
+}
+
+// GroupByMDMServerID groups devices by the ID of their assigned MDM server,
+// as reported by [OrgDeviceRelationships.AssignedServerID], keeping the input
+// order within each group. Unassigned devices, and devices whose
+// relationships carry no linkage data, are grouped under "".
+func GroupByMDMServerID(devices []OrgDevice) map[string][]OrgDevice {
+	groups := make(map[string][]OrgDevice)
+	for _, device := range devices {
+		serverID := device.Relationships.AssignedServerID()
+		groups[serverID] = append(groups[serverID], device)
+	}
+
+	return groups
+}
+
+// GroupDevicesByMonth groups devices by the month, formatted as "2006-01",
+// that the date-time attribute named by field falls in when observed in loc,
+// keeping the input order within each group. A nil loc means UTC. Devices
+// without the attribute, or with nil attributes, are grouped under
+// "unknown", as are all devices when field is not a declared [DateField].
+func GroupDevicesByMonth(devices []OrgDevice, field DateField, loc *time.Location) map[string][]OrgDevice {
+	groups := make(map[string][]OrgDevice)
+	for _, device := range devices {
+		month := unknownDateBucket
+		if day := dayIn(device.Attributes.dateTime(field), loc); !day.IsZero() {
+			month = day.Format("2006-01")
+		}
+		groups[month] = append(groups[month], device)
+	}
+
+	return groups
+}
@@ -0,0 +1,297 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-json-experiment/json"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGroupByProductFamily(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	iphone1 := OrgDevice{ID: "iphone-1", Attributes: &OrgDeviceAttributes{ProductFamily: ProductFamilyIPhone}}
+	iphone2 := OrgDevice{ID: "iphone-2", Attributes: &OrgDeviceAttributes{ProductFamily: ProductFamilyIPhone}}
+	mac := OrgDevice{ID: "mac-1", Attributes: &OrgDeviceAttributes{ProductFamily: ProductFamilyMac}}
+	bare := OrgDevice{ID: "bare-1"}
+
+	tests := map[string]struct {
+		devices []OrgDevice
+		want    map[OrgDeviceAttributesProductFamily][]OrgDevice
+	}{
+		"success: grouped in input order": {
+			devices: []OrgDevice{iphone1, mac, iphone2},
+			want: map[OrgDeviceAttributesProductFamily][]OrgDevice{
+				ProductFamilyIPhone: {iphone1, iphone2},
+				ProductFamilyMac:    {mac},
+			},
+		},
+		"success: nil attributes grouped separately": {
+			devices: []OrgDevice{bare, iphone1},
+			want: map[OrgDeviceAttributesProductFamily][]OrgDevice{
+				"":                  {bare},
+				ProductFamilyIPhone: {iphone1},
+			},
+		},
+		"success: no devices": {
+			devices: nil,
+			want:    map[OrgDeviceAttributesProductFamily][]OrgDevice{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			got := GroupByProductFamily(tt.devices)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("groups mismatch (-want +got):\n%s", diff)
+			}
+
+			total := 0
+			for _, group := range got {
+				total += len(group)
+			}
+			if diff := cmp.Diff(len(tt.devices), total); diff != "" {
+				t.Fatalf("total count mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGroupByStatus(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	assigned := OrgDevice{ID: "assigned-1", Attributes: &OrgDeviceAttributes{Status: StatusAssigned}}
+	unassigned1 := OrgDevice{ID: "unassigned-1", Attributes: &OrgDeviceAttributes{Status: StatusUnAssigned}}
+	unassigned2 := OrgDevice{ID: "unassigned-2", Attributes: &OrgDeviceAttributes{Status: StatusUnAssigned}}
+	bare := OrgDevice{ID: "bare-1"}
+
+	tests := map[string]struct {
+		devices []OrgDevice
+		want    map[OrgDeviceAttributesStatus][]OrgDevice
+	}{
+		"success: every status is a key": {
+			devices: []OrgDevice{unassigned1, assigned, bare, unassigned2},
+			want: map[OrgDeviceAttributesStatus][]OrgDevice{
+				StatusAssigned:   {assigned},
+				StatusUnAssigned: {unassigned1, unassigned2},
+				"":               {bare},
+			},
+		},
+		"success: no devices": {
+			devices: nil,
+			want:    map[OrgDeviceAttributesStatus][]OrgDevice{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			got := GroupByStatus(tt.devices)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("groups mismatch (-want +got):\n%s", diff)
+			}
+
+			total := 0
+			for _, group := range got {
+				total += len(group)
+			}
+			if diff := cmp.Diff(len(tt.devices), total); diff != "" {
+				t.Fatalf("total count mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	got := GroupByStatus([]OrgDevice{assigned, unassigned1, bare})
+	for _, status := range append(OrgDeviceAttributesStatusValues(), "") {
+		if _, ok := got[status]; !ok {
+			t.Errorf("status %q is not a key of the groups", status)
+		}
+	}
+}
+
+func TestGroupByMDMServerID(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	var assigned OrgDevice
+	if err := json.Unmarshal([]byte(`{
+		"id": "assigned-1",
+		"type": "orgDevices",
+		"relationships": {
+			"assignedServer": {
+				"links": {"related": "https://api-business.apple.com/v1/orgDevices/assigned-1/assignedServer"},
+				"data": {"id": "mdm-1", "type": "mdmServers"}
+			}
+		}
+	}`), &assigned); err != nil {
+		t.Fatalf("decode device: %v", err)
+	}
+	if diff := cmp.Diff("mdm-1", assigned.Relationships.AssignedServerID()); diff != "" {
+		t.Fatalf("AssignedServerID mismatch (-want +got):\n%s", diff)
+	}
+
+	linksOnly := OrgDevice{ID: "links-1", Relationships: &OrgDeviceRelationships{
+		AssignedServer: &OrgDeviceRelationshipsAssignedServer{Links: &RelationshipLinks{Related: "https://api-business.apple.com/v1/orgDevices/links-1/assignedServer"}},
+	}}
+	unassigned := OrgDevice{ID: "unassigned-1", Attributes: &OrgDeviceAttributes{Status: StatusUnAssigned}}
+
+	tests := map[string]struct {
+		devices []OrgDevice
+		want    map[string][]OrgDevice
+	}{
+		"success: grouped by server ID": {
+			devices: []OrgDevice{unassigned, assigned, linksOnly},
+			want: map[string][]OrgDevice{
+				"mdm-1": {assigned},
+				"":      {unassigned, linksOnly},
+			},
+		},
+		"success: no devices": {
+			devices: nil,
+			want:    map[string][]OrgDevice{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			got := GroupByMDMServerID(tt.devices)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("groups mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGroupDevicesByMonth(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skipf("load time zone: %v", err)
+	}
+
+	// Ordered on the evening of 31 January in Los Angeles, which is already
+	// 1 February in UTC.
+	boundary := OrgDevice{ID: "boundary-1", Attributes: &OrgDeviceAttributes{
+		OrderDateTime:      time.Date(2025, time.February, 1, 5, 0, 0, 0, time.UTC),
+		AddedToOrgDateTime: time.Date(2025, time.March, 15, 12, 0, 0, 0, time.UTC),
+	}}
+	midMonth := OrgDevice{ID: "mid-1", Attributes: &OrgDeviceAttributes{
+		OrderDateTime:   time.Date(2025, time.February, 14, 12, 0, 0, 0, time.UTC),
+		UpdatedDateTime: time.Date(2025, time.April, 1, 1, 0, 0, 0, time.UTC),
+	}}
+	noDate := OrgDevice{ID: "no-date-1", Attributes: &OrgDeviceAttributes{}}
+	noAttributes := OrgDevice{ID: "no-attributes-1"}
+	devices := []OrgDevice{boundary, midMonth, noDate, noAttributes}
+
+	tests := map[string]struct {
+		field DateField
+		loc   *time.Location
+		want  map[string][]OrgDevice
+	}{
+		"success: order date in UTC": {
+			field: DateFieldOrderDateTime,
+			loc:   time.UTC,
+			want: map[string][]OrgDevice{
+				"2025-02": {boundary, midMonth},
+				"unknown": {noDate, noAttributes},
+			},
+		},
+		"success: order date in Los Angeles": {
+			field: DateFieldOrderDateTime,
+			loc:   la,
+			want: map[string][]OrgDevice{
+				"2025-01": {boundary},
+				"2025-02": {midMonth},
+				"unknown": {noDate, noAttributes},
+			},
+		},
+		"success: added date": {
+			field: DateFieldAddedToOrgDateTime,
+			loc:   la,
+			want: map[string][]OrgDevice{
+				"2025-03": {boundary},
+				"unknown": {midMonth, noDate, noAttributes},
+			},
+		},
+		"success: updated date with nil location": {
+			field: DateFieldUpdatedDateTime,
+			loc:   nil,
+			want: map[string][]OrgDevice{
+				"2025-04": {midMonth},
+				"unknown": {boundary, noDate, noAttributes},
+			},
+		},
+		"success: updated date in Los Angeles": {
+			field: DateFieldUpdatedDateTime,
+			loc:   la,
+			want: map[string][]OrgDevice{
+				"2025-03": {midMonth},
+				"unknown": {boundary, noDate, noAttributes},
+			},
+		},
+		"success: undeclared field": {
+			field: DateField("releasedDateTime"),
+			loc:   time.UTC,
+			want: map[string][]OrgDevice{
+				"unknown": devices,
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			got := GroupDevicesByMonth(devices, tt.field, tt.loc)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("groups mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
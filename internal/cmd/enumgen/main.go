@@ -0,0 +1,200 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Command enumgen generates the Known, IsKnown, Values, and Parse helpers of
+// the string enum types of package abm.
+//
+// An enum type is an exported type whose underlying type is string. Its
+// values are the exported constants declared with that type, in declaration
+// order; those declarations are the single source of truth for the helpers.
+//
+// Usage:
+//
+//	go run ./internal/cmd/enumgen [-dir dir] [-output file]
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// enum is a string enum type and its constant names in declaration order.
+type enum struct {
+	Name      string
+	Constants []string
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("enumgen: ")
+
+	dir := flag.String("dir", ".", "package directory")
+	output := flag.String("output", "enum_gen.go", "output file name, relative to -dir")
+	flag.Parse()
+
+	src, err := generate(*dir, *output)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(*dir, *output), src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate returns the formatted source of the helpers for the enums of the
+// package in dir, ignoring test files and the previously generated output.
+func generate(dir, output string) ([]byte, error) {
+	enums, err := parseEnums(dir, output)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := licenseHeader(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	buf.WriteString("\n// Code generated by enumgen. DO NOT EDIT.\n\npackage abm\n")
+	for _, e := range enums {
+		writeEnum(&buf, e)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated source: %w", err)
+	}
+
+	return src, nil
+}
+
+// parseEnums returns the enums declared in the package in dir, sorted by name.
+func parseEnums(dir, output string) ([]enum, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	files = slices.DeleteFunc(files, func(name string) bool {
+		base := filepath.Base(name)
+		return strings.HasSuffix(base, "_test.go") || base == output
+	})
+
+	fset := token.NewFileSet()
+	types := make(map[string]*enum)
+	var constants []*ast.ValueSpec
+	for _, name := range files {
+		file, err := parser.ParseFile(fset, name, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", name, err)
+		}
+		if file.Name.Name != "abm" {
+			continue
+		}
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					if ident, ok := spec.Type.(*ast.Ident); ok && ident.Name == "string" && spec.Name.IsExported() && !spec.Assign.IsValid() {
+						types[spec.Name.Name] = &enum{Name: spec.Name.Name}
+					}
+				case *ast.ValueSpec:
+					if gen.Tok == token.CONST {
+						constants = append(constants, spec)
+					}
+				}
+			}
+		}
+	}
+
+	for _, spec := range constants {
+		ident, ok := spec.Type.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		e, ok := types[ident.Name]
+		if !ok {
+			continue
+		}
+		for _, name := range spec.Names {
+			if name.IsExported() {
+				e.Constants = append(e.Constants, name.Name)
+			}
+		}
+	}
+
+	enums := make([]enum, 0, len(types))
+	for _, e := range types {
+		if len(e.Constants) > 0 {
+			enums = append(enums, *e)
+		}
+	}
+	slices.SortFunc(enums, func(a, b enum) int { return cmp.Compare(a.Name, b.Name) })
+
+	return enums, nil
+}
+
+// licenseHeader returns the license comment that starts doc.go in dir.
+func licenseHeader(dir string) (string, error) {
+	doc, err := os.ReadFile(filepath.Join(dir, "doc.go"))
+	if err != nil {
+		return "", fmt.Errorf("read license header: %w", err)
+	}
+
+	header, _, ok := strings.Cut(string(doc), "\n\n")
+	if !ok || !strings.HasPrefix(header, "//") {
+		return "", fmt.Errorf("doc.go does not start with a license header")
+	}
+
+	return header + "\n", nil
+}
+
+func writeEnum(buf *bytes.Buffer, e enum) {
+	fmt.Fprintf(buf, "\n// Known reports whether v is a declared %s value.\n", e.Name)
+	fmt.Fprintf(buf, "func (v %s) Known() bool {\n", e.Name)
+	fmt.Fprintf(buf, "\tswitch v {\n\tcase %s:\n\t\treturn true\n\tdefault:\n\t\treturn false\n\t}\n}\n", strings.Join(e.Constants, ", "))
+
+	fmt.Fprintf(buf, "\n// IsKnown reports whether v is a declared %s value, like [%s.Known].\n", e.Name, e.Name)
+	fmt.Fprintf(buf, "func (v %s) IsKnown() bool {\n\treturn v.Known()\n}\n", e.Name)
+
+	fmt.Fprintf(buf, "\n// %sValues returns every declared %s value in declaration order.\n", e.Name, e.Name)
+	fmt.Fprintf(buf, "func %sValues() []%s {\n", e.Name, e.Name)
+	fmt.Fprintf(buf, "\treturn []%s{\n", e.Name)
+	for _, c := range e.Constants {
+		fmt.Fprintf(buf, "\t\t%s,\n", c)
+	}
+	buf.WriteString("\t}\n}\n")
+
+	fmt.Fprintf(buf, "\n// Parse%s converts s to %s, returning an [*UnknownValueError] when s is not a declared value.\n", e.Name, e.Name)
+	fmt.Fprintf(buf, "func Parse%s(s string) (%s, error) {\n", e.Name, e.Name)
+	fmt.Fprintf(buf, "\tif v := %s(s); v.Known() {\n\t\treturn v, nil\n\t}\n\n", e.Name)
+	fmt.Fprintf(buf, "\treturn \"\", &UnknownValueError{Type: %q, Value: s}\n}\n", e.Name)
+}
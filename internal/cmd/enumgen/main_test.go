@@ -0,0 +1,90 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGenerateUpToDate(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	dir := filepath.Join("..", "..", "..")
+	got, err := generate(dir, "enum_gen.go")
+	if err != nil {
+		t.Fatalf("generate returned error: %v", err)
+	}
+
+	want, err := os.ReadFile(filepath.Join(dir, "enum_gen.go"))
+	if err != nil {
+		t.Fatalf("read enum_gen.go: %v", err)
+	}
+	if diff := cmp.Diff(string(want), string(got)); diff != "" {
+		t.Fatalf("enum_gen.go is stale; run go generate (-committed +generated):\n%s", diff)
+	}
+}
+
+func TestParseEnums(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	dir := t.TempDir()
+	src := `package abm
+
+type Color string
+
+const (
+	ColorRed  Color = "RED"
+	colorPink Color = "PINK"
+	ColorBlue Color = "BLUE"
+)
+
+type Alias = string
+
+type Empty string
+
+type count int
+
+const ColorGreen Color = "GREEN"
+`
+	if err := os.WriteFile(filepath.Join(dir, "colors.go"), []byte(src), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "colors_test.go"), []byte("package abm\n\nconst ColorTest Color = \"TEST\"\n"), 0o644); err != nil {
+		t.Fatalf("write test source: %v", err)
+	}
+
+	got, err := parseEnums(dir, "enum_gen.go")
+	if err != nil {
+		t.Fatalf("parseEnums returned error: %v", err)
+	}
+	want := []enum{
+		{Name: "Color", Constants: []string{"ColorRed", "ColorBlue", "ColorGreen"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("enums mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// OrgDeviceDisposition is the outcome of looking up an organization device
+// with [Client.LookupOrgDevice].
+type OrgDeviceDisposition string
+
+const (
+	// DispositionFound means the device belongs to the organization.
+	DispositionFound OrgDeviceDisposition = "FOUND"
+
+	// DispositionNotInOrganization means the API does not know the device in
+	// the organization, because it was never added to it.
+	DispositionNotInOrganization OrgDeviceDisposition = "NOT_IN_ORGANIZATION"
+
+	// DispositionReleased means the device was released from the
+	// organization, as reported by [OrgDeviceAttributes.WasReleasedFromOrg].
+	// A device whose release is scheduled in the future is still
+	// [DispositionFound].
+	DispositionReleased OrgDeviceDisposition = "RELEASED"
+
+	// DispositionAccessDenied means the credentials may not see the device.
+	DispositionAccessDenied OrgDeviceDisposition = "ACCESS_DENIED"
+)
+
+// OrgDeviceLookup is the result of [Client.LookupOrgDevice].
+type OrgDeviceLookup struct {
+	// ID is the looked up device ID.
+	ID string
+
+	Disposition OrgDeviceDisposition
+
+	// Device is the device for [DispositionFound] and [DispositionReleased],
+	// and nil otherwise.
+	Device *OrgDevice
+
+	// Err is the API's error for [DispositionNotInOrganization] and
+	// [DispositionAccessDenied], and nil otherwise.
+	Err *APIError
+}
+
+// LookupOrgDevice gets the organization device and tells apart why it may be
+// unavailable, for support tooling that explains a failed lookup to users.
+//
+// A found device whose release date is set is [DispositionReleased]; a 404
+// Not Found with a not-found error code is [DispositionNotInOrganization];
+// and a 403 Forbidden is [DispositionAccessDenied]. Any other failure, such
+// as a transport error or a 5xx response, is returned as an error.
+func (c *Client) LookupOrgDevice(ctx context.Context, orgDeviceID string) (*OrgDeviceLookup, error) {
+	response, err := c.OrgDevices().Get(ctx, orgDeviceID, nil)
+	if err == nil {
+		disposition, _ := orgDeviceDisposition(http.StatusOK, "", response.Data.Attributes)
+		return &OrgDeviceLookup{ID: orgDeviceID, Disposition: disposition, Device: &response.Data}, nil
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return nil, err
+	}
+	var code ErrorCode
+	if codes := apiErr.ErrorCodes(); len(codes) > 0 {
+		code = codes[0]
+	}
+	disposition, ok := orgDeviceDisposition(apiErr.StatusCode, code, nil)
+	if !ok {
+		return nil, err
+	}
+
+	return &OrgDeviceLookup{ID: orgDeviceID, Disposition: disposition, Err: apiErr}, nil
+}
+
+// orgDeviceDisposition maps the status code, first error code, and device
+// attributes of a device lookup response to its disposition. ok is false
+// when the response has none, and the lookup failed.
+func orgDeviceDisposition(statusCode int, code ErrorCode, attributes *OrgDeviceAttributes) (disposition OrgDeviceDisposition, ok bool) {
+	switch statusCode {
+	case http.StatusOK:
+		if attributes.WasReleasedFromOrg() {
+			return DispositionReleased, true
+		}
+		return DispositionFound, true
+	case http.StatusNotFound:
+		// A 404 without a not-found code, such as one for a wrong base URL,
+		// says nothing about the device.
+		if code == ErrorCodeNotFound || code == ErrorCodeEntityNotFound {
+			return DispositionNotInOrganization, true
+		}
+		return "", false
+	case http.StatusForbidden:
+		return DispositionAccessDenied, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOrgDeviceDisposition(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	released := &OrgDeviceAttributes{ReleasedFromOrgDateTime: time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)}
+	scheduled := &OrgDeviceAttributes{ReleasedFromOrgDateTime: time.Now().Add(24 * time.Hour)}
+	active := &OrgDeviceAttributes{Status: StatusAssigned}
+
+	tests := map[string]struct {
+		statusCode      int
+		code            ErrorCode
+		attributes      *OrgDeviceAttributes
+		wantDisposition OrgDeviceDisposition
+		wantOK          bool
+	}{
+		"success: 200 active device":                   {statusCode: http.StatusOK, attributes: active, wantDisposition: DispositionFound, wantOK: true},
+		"success: 200 without attributes":              {statusCode: http.StatusOK, wantDisposition: DispositionFound, wantOK: true},
+		"success: 200 released device":                 {statusCode: http.StatusOK, attributes: released, wantDisposition: DispositionReleased, wantOK: true},
+		"success: 200 release scheduled in the future": {statusCode: http.StatusOK, attributes: scheduled, wantDisposition: DispositionFound, wantOK: true},
+		"success: 404 NOT_FOUND":                       {statusCode: http.StatusNotFound, code: ErrorCodeNotFound, wantDisposition: DispositionNotInOrganization, wantOK: true},
+		"success: 404 ENTITY_ERROR.NOT_FOUND":          {statusCode: http.StatusNotFound, code: ErrorCodeEntityNotFound, wantDisposition: DispositionNotInOrganization, wantOK: true},
+		"success: 403 FORBIDDEN_ERROR":                 {statusCode: http.StatusForbidden, code: ErrorCodeForbidden, wantDisposition: DispositionAccessDenied, wantOK: true},
+		"success: 403 without code":                    {statusCode: http.StatusForbidden, wantDisposition: DispositionAccessDenied, wantOK: true},
+		"success: 403 ignores attributes":              {statusCode: http.StatusForbidden, attributes: released, wantDisposition: DispositionAccessDenied, wantOK: true},
+		"error: 404 without code":                      {statusCode: http.StatusNotFound},
+		"error: 404 with another code":                 {statusCode: http.StatusNotFound, code: ErrorCodeParameterInvalid},
+		"error: 401 NOT_AUTHORIZED":                    {statusCode: http.StatusUnauthorized, code: ErrorCodeNotAuthorized},
+		"error: 400 PARAMETER_ERROR.INVALID":           {statusCode: http.StatusBadRequest, code: ErrorCodeParameterInvalid},
+		"error: 429 RATE_LIMIT_EXCEEDED":               {statusCode: http.StatusTooManyRequests, code: ErrorCodeRateLimitExceeded},
+		"error: 500 UNEXPECTED_ERROR":                  {statusCode: http.StatusInternalServerError, code: ErrorCodeUnexpected},
+		"error: 503 SERVICE_UNAVAILABLE":               {statusCode: http.StatusServiceUnavailable, code: ErrorCodeServiceUnavailable},
+		"error: 410 Gone is not a documented response": {statusCode: http.StatusGone},
+		"error: no response":                           {statusCode: 0},
+		"error: 404 CONFLICT_ERROR ignores attributes": {statusCode: http.StatusNotFound, code: ErrorCodeConflict, attributes: released},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			disposition, ok := orgDeviceDisposition(tt.statusCode, tt.code, tt.attributes)
+			if diff := cmp.Diff(tt.wantOK, ok); diff != "" {
+				t.Fatalf("ok mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantDisposition, disposition); diff != "" {
+				t.Fatalf("disposition mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_LookupOrgDevice(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		statusCode      int
+		body            string
+		closeConn       bool
+		wantDisposition OrgDeviceDisposition
+		wantDevice      bool
+		wantAPIErr      bool
+		wantErr         bool
+	}{
+		"success: found": {
+			statusCode:      http.StatusOK,
+			body:            `{"data":{"id":"device-1","type":"orgDevices","attributes":{"serialNumber":"SER-1","status":"ASSIGNED"}},"links":{"self":"/v1/orgDevices/device-1"}}`,
+			wantDisposition: DispositionFound,
+			wantDevice:      true,
+		},
+		"success: released": {
+			statusCode:      http.StatusOK,
+			body:            `{"data":{"id":"device-1","type":"orgDevices","attributes":{"serialNumber":"SER-1","releasedFromOrgDateTime":"2025-05-01T00:00:00Z"}},"links":{"self":"/v1/orgDevices/device-1"}}`,
+			wantDisposition: DispositionReleased,
+			wantDevice:      true,
+		},
+		"success: not in organization": {
+			statusCode:      http.StatusNotFound,
+			body:            `{"errors":[{"status":"404","code":"NOT_FOUND","title":"The specified resource does not exist","detail":"There is no resource of type 'orgDevices' with id 'device-1'"}]}`,
+			wantDisposition: DispositionNotInOrganization,
+			wantAPIErr:      true,
+		},
+		"success: access denied": {
+			statusCode:      http.StatusForbidden,
+			body:            `{"errors":[{"status":"403","code":"FORBIDDEN_ERROR","title":"Forbidden","detail":"not allowed"}]}`,
+			wantDisposition: DispositionAccessDenied,
+			wantAPIErr:      true,
+		},
+		"error: server error": {
+			statusCode: http.StatusInternalServerError,
+			body:       `{"errors":[{"status":"500","code":"UNEXPECTED_ERROR","title":"boom","detail":"boom"}]}`,
+			wantErr:    true,
+		},
+		"error: 404 without a not-found code": {
+			statusCode: http.StatusNotFound,
+			body:       `404 page not found`,
+			wantErr:    true,
+		},
+		"error: transport failure": {
+			closeConn: true,
+			wantErr:   true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.closeConn {
+					conn, _, err := w.(http.Hijacker).Hijack()
+					if err == nil {
+						conn.Close()
+					}
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.statusCode)
+				fmt.Fprint(w, tt.body)
+			}))
+			t.Cleanup(server.Close)
+			client := testClientForServer(t, server)
+
+			got, err := client.LookupOrgDevice(ctx, "device-1")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("LookupOrgDevice returned %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LookupOrgDevice returned error: %v", err)
+			}
+
+			if diff := cmp.Diff("device-1", got.ID); diff != "" {
+				t.Fatalf("ID mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantDisposition, got.Disposition); diff != "" {
+				t.Fatalf("disposition mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantDevice, got.Device != nil); diff != "" {
+				t.Fatalf("device presence mismatch (-want +got):\n%s", diff)
+			}
+			if tt.wantDevice {
+				if diff := cmp.Diff("SER-1", got.Device.Attributes.SerialNumber); diff != "" {
+					t.Fatalf("serial number mismatch (-want +got):\n%s", diff)
+				}
+			}
+			if diff := cmp.Diff(tt.wantAPIErr, got.Err != nil); diff != "" {
+				t.Fatalf("API error presence mismatch (-want +got):\n%s", diff)
+			}
+			if tt.wantAPIErr {
+				if diff := cmp.Diff(tt.statusCode, got.Err.StatusCode); diff != "" {
+					t.Fatalf("API error status mismatch (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"cmp"
+	"slices"
+)
+
+// WithStableOrdering sorts the resources returned by list requests and
+// all-pages helpers, whose order the API does not keep stable across pages
+// and runs, into a deterministic order after fetching, which keeps golden
+// and snapshot tests of callers stable. It applies to [OrgDevicesService.List],
+// [OrgDevicesService.ListRaw], [OrgDevicesService.AppleCareCoverage],
+// [MDMServersService.List], [MDMServersService.DeviceLinkages] without a
+// Sort option, [Client.FetchOrgDevices], and [Client.GetMDMServerSummaries],
+// using [SortOrgDevices], [SortAppleCareCoverages], [SortMDMServers], and
+// [SortMDMServerDeviceLinkages]. The raw body returned by ListRaw, iterators,
+// and single-resource responses are left as the API returned them.
+func WithStableOrdering() ClientOption {
+	return func(o *clientOptions) {
+		o.stableOrdering = true
+	}
+}
+
+// CompareOrgDevices orders devices by serial number, then by ID. Devices
+// with nil attributes come after all others and are ordered by ID.
+func CompareOrgDevices(a, b OrgDevice) int {
+	if c := compareNilLast(a.Attributes == nil, b.Attributes == nil); c != 0 || a.Attributes == nil {
+		return cmp.Or(c, cmp.Compare(a.ID, b.ID))
+	}
+
+	return cmp.Or(
+		cmp.Compare(a.Attributes.SerialNumber, b.Attributes.SerialNumber),
+		cmp.Compare(a.ID, b.ID),
+	)
+}
+
+// SortOrgDevices sorts devices in place with [CompareOrgDevices], keeping
+// the order of equal devices.
+func SortOrgDevices(devices []OrgDevice) {
+	slices.SortStableFunc(devices, CompareOrgDevices)
+}
+
+// CompareMDMServers orders servers by name, then by ID. Servers with nil
+// attributes come after all others and are ordered by ID.
+func CompareMDMServers(a, b MDMServer) int {
+	if c := compareNilLast(a.Attributes == nil, b.Attributes == nil); c != 0 || a.Attributes == nil {
+		return cmp.Or(c, cmp.Compare(a.ID, b.ID))
+	}
+
+	return cmp.Or(
+		cmp.Compare(a.Attributes.ServerName, b.Attributes.ServerName),
+		cmp.Compare(a.ID, b.ID),
+	)
+}
+
+// SortMDMServers sorts servers in place with [CompareMDMServers], keeping
+// the order of equal servers.
+func SortMDMServers(servers []MDMServer) {
+	slices.SortStableFunc(servers, CompareMDMServers)
+}
+
+// CompareMDMServerDeviceLinkages orders device linkages by ID.
+func CompareMDMServerDeviceLinkages(a, b MDMServerDevicesLinkageData) int {
+	return cmp.Compare(a.ID, b.ID)
+}
+
+// SortMDMServerDeviceLinkages sorts linkages in place with
+// [CompareMDMServerDeviceLinkages], keeping the order of equal linkages.
+func SortMDMServerDeviceLinkages(linkages []MDMServerDevicesLinkageData) {
+	slices.SortStableFunc(linkages, CompareMDMServerDeviceLinkages)
+}
+
+// CompareAppleCareCoverages orders coverages by start date-time, then by ID,
+// with coverages without a start first. Coverages with nil attributes come
+// after all others and are ordered by ID.
+func CompareAppleCareCoverages(a, b AppleCareCoverage) int {
+	if c := compareNilLast(a.Attributes == nil, b.Attributes == nil); c != 0 || a.Attributes == nil {
+		return cmp.Or(c, cmp.Compare(a.ID, b.ID))
+	}
+
+	return cmp.Or(
+		a.Attributes.StartDateTime.Compare(b.Attributes.StartDateTime),
+		cmp.Compare(a.ID, b.ID),
+	)
+}
+
+// SortAppleCareCoverages sorts coverages in place with
+// [CompareAppleCareCoverages], keeping the order of equal coverages.
+func SortAppleCareCoverages(coverages []AppleCareCoverage) {
+	slices.SortStableFunc(coverages, CompareAppleCareCoverages)
+}
+
+// compareMDMServerSummaries orders summaries like [CompareMDMServers].
+func compareMDMServerSummaries(a, b MDMServerSummary) int {
+	return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID))
+}
+
+// compareNilLast orders a present value before a nil one, given whether each is nil.
+func compareNilLast(aNil, bNil bool) int {
+	switch {
+	case aNil == bNil:
+		return 0
+	case aNil:
+		return 1
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSortOrgDevices(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	devices := []OrgDevice{
+		{ID: "nil-b"},
+		{ID: "b", Attributes: &OrgDeviceAttributes{SerialNumber: "SER-2"}},
+		{ID: "nil-a"},
+		{ID: "c", Attributes: &OrgDeviceAttributes{SerialNumber: "SER-1"}},
+		{ID: "a", Attributes: &OrgDeviceAttributes{SerialNumber: "SER-2"}},
+		{ID: "d", Attributes: &OrgDeviceAttributes{}},
+	}
+	SortOrgDevices(devices)
+
+	var got []string
+	for _, device := range devices {
+		got = append(got, device.ID)
+	}
+	if diff := cmp.Diff([]string{"d", "c", "a", "b", "nil-a", "nil-b"}, got); diff != "" {
+		t.Fatalf("order mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSortMDMServers(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	servers := []MDMServer{
+		{ID: "nil-1"},
+		{ID: "2", Attributes: &MDMServerAttributes{ServerName: "Zeta"}},
+		{ID: "3", Attributes: &MDMServerAttributes{ServerName: "Alpha"}},
+		{ID: "1", Attributes: &MDMServerAttributes{ServerName: "Zeta"}},
+	}
+	SortMDMServers(servers)
+
+	var got []string
+	for _, server := range servers {
+		got = append(got, server.ID)
+	}
+	if diff := cmp.Diff([]string{"3", "1", "2", "nil-1"}, got); diff != "" {
+		t.Fatalf("order mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSortMDMServerDeviceLinkages(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	linkages := []MDMServerDevicesLinkageData{{ID: "c"}, {ID: "a"}, {ID: "b"}}
+	SortMDMServerDeviceLinkages(linkages)
+
+	want := []MDMServerDevicesLinkageData{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	if diff := cmp.Diff(want, linkages); diff != "" {
+		t.Fatalf("order mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSortAppleCareCoverages(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	earlier := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	later := earlier.AddDate(1, 0, 0)
+	coverages := []AppleCareCoverage{
+		{ID: "nil-1"},
+		{ID: "later", Attributes: &AppleCareCoverageAttributes{StartDateTime: later}},
+		{ID: "earlier-b", Attributes: &AppleCareCoverageAttributes{StartDateTime: earlier}},
+		{ID: "earlier-a", Attributes: &AppleCareCoverageAttributes{StartDateTime: earlier}},
+		{ID: "no-start", Attributes: &AppleCareCoverageAttributes{}},
+	}
+	SortAppleCareCoverages(coverages)
+
+	var got []string
+	for _, coverage := range coverages {
+		got = append(got, coverage.ID)
+	}
+	if diff := cmp.Diff([]string{"no-start", "earlier-a", "earlier-b", "later", "nil-1"}, got); diff != "" {
+		t.Fatalf("order mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWithStableOrdering(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	const (
+		devicesPage = `{"data":[
+			{"id":"b","type":"orgDevices","attributes":{"serialNumber":"SER-2"}},
+			{"id":"nil-1","type":"orgDevices"},
+			{"id":"a","type":"orgDevices","attributes":{"serialNumber":"SER-1"}}
+		],"links":{"self":"/v1/orgDevices"}}`
+		deviceBody = `{"data":{"id":"b","type":"orgDevices","attributes":{"serialNumber":"SER-2"}},"links":{"self":"/v1/orgDevices/b"}}`
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v1/orgDevices/b" {
+			fmt.Fprint(w, deviceBody)
+			return
+		}
+		fmt.Fprint(w, devicesPage)
+	}))
+	t.Cleanup(server.Close)
+
+	tests := map[string]struct {
+		opts []ClientOption
+		want []string
+	}{
+		"success: stable ordering": {
+			opts: []ClientOption{WithStableOrdering()},
+			want: []string{"a", "b", "nil-1"},
+		},
+		"success: API order by default": {
+			opts: nil,
+			want: []string{"b", "nil-1", "a"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			client := testClientForServer(t, server, tt.opts...)
+
+			listed, err := client.OrgDevices().List(ctx, nil)
+			if err != nil {
+				t.Fatalf("List returned error: %v", err)
+			}
+			fetched, err := client.FetchOrgDevices(ctx, nil)
+			if err != nil {
+				t.Fatalf("FetchOrgDevices returned error: %v", err)
+			}
+			for label, devices := range map[string][]OrgDevice{"List": listed.Data, "FetchOrgDevices": fetched.Devices} {
+				var got []string
+				for _, device := range devices {
+					got = append(got, device.ID)
+				}
+				if diff := cmp.Diff(tt.want, got); diff != "" {
+					t.Fatalf("%s order mismatch (-want +got):\n%s", label, diff)
+				}
+			}
+
+			single, err := client.OrgDevices().Get(ctx, "b", nil)
+			if err != nil {
+				t.Fatalf("Get returned error: %v", err)
+			}
+			want := OrgDevice{ID: "b", Type: "orgDevices", Attributes: &OrgDeviceAttributes{SerialNumber: "SER-2"}}
+			if diff := cmp.Diff(want, single.Data); diff != "" {
+				t.Fatalf("single resource mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -18,24 +18,250 @@ package abm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"iter"
 	"net/http"
 	"net/url"
+	"reflect"
+	"regexp"
 	"strings"
+	"time"
 )
 
 // maxPages is the maximum number of pages the iterator will fetch before stopping,
 // matching the ABM API hard limit of 1000 pages.
 const maxPages = 1000
 
+// ErrMaxItemsReached is returned together with the collected items when a crawl
+// stops early because it reached the bound set by [WithMaxItems].
+var ErrMaxItemsReached = errors.New("maximum number of items reached")
+
+// ErrNextLinkVersionMismatch is matched, with [errors.Is], by a
+// [*NextLinkVersionMismatchError].
+var ErrNextLinkVersionMismatch = errors.New("next link API version mismatch")
+
+// NextLinkVersionMismatchError is returned, wrapped in a [*PageError], when a
+// page's next link carries a different API version segment than the crawl's
+// first request, such as "/v2/orgDevices" for a crawl of "/v1/orgDevices".
+// The next page is not requested, since its payload may have a different
+// shape. See [WithAllowNextLinkVersionMismatch] and
+// [AllowNextLinkVersionMismatch] to follow such links anyway.
+type NextLinkVersionMismatchError struct {
+	// RequestPath is the path of the crawl's first request.
+	RequestPath string
+
+	// NextPath is the path of the rejected next link.
+	NextPath string
+}
+
+func (e *NextLinkVersionMismatchError) Error() string {
+	return fmt.Sprintf("%v: next link path %q, request path %q", ErrNextLinkVersionMismatch, e.NextPath, e.RequestPath)
+}
+
+// Is reports whether target is [ErrNextLinkVersionMismatch].
+func (e *NextLinkVersionMismatchError) Is(target error) bool {
+	return target == ErrNextLinkVersionMismatch
+}
+
+// apiVersionSegment matches an API version path segment such as "v1".
+var apiVersionSegment = regexp.MustCompile(`^v[0-9]+$`)
+
+// checkNextLinkVersion returns a [*NextLinkVersionMismatchError] when the
+// first API version segments of the paths of request and next differ. Paths
+// without a version segment are not checked.
+func checkNextLinkVersion(request *url.URL, next string) error {
+	if next == "" {
+		return nil
+	}
+	nextURL, err := url.Parse(next)
+	if err != nil {
+		return fmt.Errorf("parse next links url: %w", err)
+	}
+
+	requestVersion, nextVersion := pathAPIVersion(request.Path), pathAPIVersion(nextURL.Path)
+	if requestVersion == "" || nextVersion == "" || requestVersion == nextVersion {
+		return nil
+	}
+
+	return &NextLinkVersionMismatchError{RequestPath: request.Path, NextPath: nextURL.Path}
+}
+
+// pathAPIVersion returns the first API version segment of path, or "".
+func pathAPIVersion(path string) string {
+	for segment := range strings.SplitSeq(path, "/") {
+		if apiVersionSegment.MatchString(segment) {
+			return segment
+		}
+	}
+	return ""
+}
+
+// PageError is returned by pagination loops, such as [PageIterator] and the
+// all-pages client helpers, when fetching or decoding a page fails. It records
+// where the crawl stopped so an operator can decide whether to retry.
+type PageError struct {
+	// PageIndex is the zero-based index of the failed page.
+	PageIndex int
+
+	// RequestURL is the URL of the failed page, including its query.
+	RequestURL string
+
+	// ItemsFetchedSoFar is the number of items on the pages fetched before
+	// the failed one.
+	ItemsFetchedSoFar int
+
+	// Err is the underlying error, such as an [*APIError] or a context error.
+	Err error
+}
+
+func (e *PageError) Error() string {
+	return fmt.Sprintf("page %d (%s, %d items fetched so far): %v", e.PageIndex, e.RequestURL, e.ItemsFetchedSoFar, e.Err)
+}
+
+func (e *PageError) Unwrap() error {
+	return e.Err
+}
+
+// pageItemCount returns the number of items on a page: the length of page
+// itself if it is a slice, or of its Data field if it is a response struct.
+func pageItemCount(page any) int {
+	v := reflect.Indirect(reflect.ValueOf(page))
+	if v.Kind() == reflect.Struct {
+		v = v.FieldByName("Data")
+	}
+	if v.Kind() != reflect.Slice {
+		return 0
+	}
+
+	return v.Len()
+}
+
+// defaultCrawlPageSize is the page size crawls request unless the caller
+// sets a limit or [WithCrawlPageSize] says otherwise. The API's own default
+// page size is much smaller, which multiplies the number of requests.
+const defaultCrawlPageSize = 100
+
+// WithCrawlPageSize sets the page size, the limit query parameter, that
+// multi-page crawls such as [Client.FetchOrgDevicePartNumbers],
+// [Client.FetchOrgDevices], [Client.ExportOrgDevicesCSV], and
+// [Client.OrgDevicesUpdatedSince] request when the caller does not set a
+// limit. It defaults to 100, and must be at most 1000. Zero omits the limit
+// so the API's default page size applies. Single-page methods such as
+// [OrgDevicesService.List] never add a limit.
+func WithCrawlPageSize(n int) ClientOption {
+	return func(o *clientOptions) {
+		o.crawlPageSize = &n
+	}
+}
+
+// crawlLimit returns limit, or the client's crawl page size when limit is zero.
+func (c *Client) crawlLimit(limit int) int {
+	if limit != 0 {
+		return limit
+	}
+
+	return c.crawlPageSize
+}
+
+// CrawlDepthHook is called by a [Client] created with [WithCrawlDepthHook]
+// when a multi-page crawl ends. path is the API path of the first page, such
+// as "v1/orgDevices", and pages is the number of pages fetched successfully.
+// It is called however the crawl ends: after the last page, on an error, or
+// when the caller stops consuming the pages early. It may be called
+// concurrently when the client is used from several goroutines.
+type CrawlDepthHook func(path string, pages int)
+
+// WithCrawlDepthHook sets a hook reporting the depth of each multi-page crawl
+// the client performs, for example to build a histogram for tuning page sizes
+// and [WithCrawlRetryBudget].
+func WithCrawlDepthHook(hook CrawlDepthHook) ClientOption {
+	return func(o *clientOptions) {
+		o.crawlDepthHook = hook
+	}
+}
+
+// CrawlOption configures crawler helpers that follow pagination, such as
+// [Client.FetchOrgDevicePartNumbers].
+type CrawlOption func(*crawlOptions)
+
+type crawlOptions struct {
+	maxItems                     int
+	tokenScanner                 bool
+	forceRefresh                 bool
+	allowNextLinkVersionMismatch bool
+}
+
+// WithMaxItems bounds the total number of items a crawl collects. When more
+// than n items are available, the crawl stops after collecting n of them and
+// returns them together with [ErrMaxItemsReached]. A value <= 0 disables the bound.
+func WithMaxItems(n int) CrawlOption {
+	return func(o *crawlOptions) {
+		o.maxItems = n
+	}
+}
+
+// WithTokenScanner makes [Client.FetchOrgDevicePartNumbers] read part numbers
+// straight from the JSON tokens of each page instead of decoding every device
+// into an [OrgDevice]. The result is the same, with far fewer allocations on
+// large organizations, but fields other than the part numbers and the next
+// link are not type-checked.
+func WithTokenScanner() CrawlOption {
+	return func(o *crawlOptions) {
+		o.tokenScanner = true
+	}
+}
+
+// ForceRefresh makes [Client.FetchOrgDevices] fetch the devices from the API
+// even when the snapshot cache set up with [WithSnapshotCache] holds a fresh
+// enough snapshot. The fetched devices still replace the cached snapshot.
+func ForceRefresh() CrawlOption {
+	return func(o *crawlOptions) {
+		o.forceRefresh = true
+	}
+}
+
+// AllowNextLinkVersionMismatch makes [PageIterator] and
+// [Client.FetchOrgDevicePartNumbers] follow next links whose API version
+// segment differs from the first request's, instead of failing with a
+// [*NextLinkVersionMismatchError]. Pages from another API version are decoded
+// as is, so they may fail to decode.
+func AllowNextLinkVersionMismatch() CrawlOption {
+	return func(o *crawlOptions) {
+		o.allowNextLinkVersionMismatch = true
+	}
+}
+
+// WithAllowNextLinkVersionMismatch makes every multi-page crawl of the client
+// follow next links whose API version segment differs from the first
+// request's, see [AllowNextLinkVersionMismatch].
+func WithAllowNextLinkVersionMismatch() ClientOption {
+	return func(o *clientOptions) {
+		o.allowNextLinkVersionMismatch = true
+	}
+}
+
+func newCrawlOptions(opts []CrawlOption) crawlOptions {
+	var o crawlOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	return o
+}
+
 // PageDecoderFunc is a function that decodes a paginated API response payload into type T and returns the next link.
 type PageDecoderFunc[T any] func(payload []byte) (T, string, error)
 
 // PageIterator iterates paginated API responses from the given baseURL using the provided HTTP client and decoder function.
-func PageIterator[T any](ctx context.Context, client *http.Client, decoder PageDecoderFunc[T], baseURL string) iter.Seq2[T, error] {
+// It stops with a [*NextLinkVersionMismatchError] when a next link changes the
+// API version of baseURL, unless opts include [AllowNextLinkVersionMismatch].
+func PageIterator[T any](ctx context.Context, client *http.Client, decoder PageDecoderFunc[T], baseURL string, opts ...CrawlOption) iter.Seq2[T, error] {
 	var zero T
+	crawl := newCrawlOptions(opts)
 
 	return func(yield func(T, error) bool) {
 		if err := ctx.Err(); err != nil {
@@ -43,56 +269,202 @@ func PageIterator[T any](ctx context.Context, client *http.Client, decoder PageD
 			return
 		}
 
+		trace := attemptTraceFrom(ctx)
 		nextURL := baseURL
+		var firstURL *url.URL
+		items := 0
 		for page := 0; nextURL != ""; page++ {
+			fail := func(err error) {
+				yield(zero, &PageError{PageIndex: page, RequestURL: nextURL, ItemsFetchedSoFar: items, Err: err})
+			}
+
 			if err := ctx.Err(); err != nil {
-				yield(zero, err)
+				fail(err)
 				return
 			}
 
 			if page >= maxPages {
-				yield(zero, fmt.Errorf("pagination exceeded %d pages", maxPages))
+				fail(fmt.Errorf("pagination exceeded %d pages", maxPages))
 				return
 			}
 
 			req, err := http.NewRequestWithContext(ctx, http.MethodGet, nextURL, http.NoBody)
 			if err != nil {
-				yield(zero, fmt.Errorf("build paginated request: %w", err))
+				fail(fmt.Errorf("build paginated request: %w", err))
 				return
 			}
+			if firstURL == nil {
+				firstURL = req.URL
+			}
 
+			var start time.Time
+			if trace != nil {
+				start = time.Now()
+			}
 			resp, err := client.Do(req)
 			if err != nil {
-				yield(zero, fmt.Errorf("paginated request: %w", err))
+				err = fmt.Errorf("paginated request: %w", err)
+				if trace != nil {
+					trace.record(http.MethodGet, nextURL, start, nil, err, "")
+				}
+				fail(err)
 				return
 			}
 
-			payload, readErr := io.ReadAll(resp.Body)
+			payload, err := io.ReadAll(resp.Body)
 			resp.Body.Close()
-			if readErr != nil {
-				yield(zero, fmt.Errorf("read response: %w", readErr))
-				return
+			if err != nil {
+				err = fmt.Errorf("read response: %w", err)
+			} else if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+				err = fmt.Errorf("request failed: status=%s body=%s", resp.Status, strings.TrimSpace(string(payload)))
+			}
+			if trace != nil {
+				trace.record(http.MethodGet, nextURL, start, resp, err, "")
 			}
-			if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
-				yield(zero, fmt.Errorf("request failed: status=%s body=%s", resp.Status, strings.TrimSpace(string(payload))))
+			if err != nil {
+				fail(err)
 				return
 			}
 
 			data, nextLink, err := decoder(payload)
 			if err != nil {
-				yield(zero, err)
+				fail(err)
 				return
 			}
 
+			items += pageItemCount(data)
 			if !yield(data, nil) {
 				return
 			}
 
-			nextURL, err = resolveNextURL(req.URL, nextLink)
+			link, err := resolveNextURL(req.URL, nextLink)
 			if err != nil {
-				yield(zero, err)
+				fail(err)
 				return
 			}
+			if !crawl.allowNextLinkVersionMismatch {
+				if err := checkNextLinkVersion(firstURL, link); err != nil {
+					fail(err)
+					return
+				}
+			}
+			nextURL = link
+		}
+	}
+}
+
+// CollectPages drains seq, typically a [PageIterator] whose pages are slices,
+// and returns all page elements in order. It stops at and returns the first
+// error, along with the elements collected before it.
+func CollectPages[T any](seq iter.Seq2[[]T, error]) ([]T, error) {
+	var all []T
+	for page, err := range seq {
+		if err != nil {
+			return all, err
+		}
+		all = append(all, page...)
+	}
+
+	return all, nil
+}
+
+// crawlPages fetches the first page of path and follows each page's next link
+// through c, so every page request is authorized and honors the client's
+// [RetryPolicy] and crawl retry budget. next extracts the next link from a
+// decoded page. Next links must stay within the client's base URL and, unless
+// the client was created with [WithAllowNextLinkVersionMismatch] or opts
+// include [AllowNextLinkVersionMismatch], keep the API version of the first
+// request.
+func crawlPages[T any](ctx context.Context, c *Client, path string, query url.Values, next func(*T) string, opts ...CrawlOption) iter.Seq2[*T, error] {
+	firstURL, err := c.buildURL(path, query)
+	if err != nil {
+		return func(yield func(*T, error) bool) {
+			yield(nil, err)
+		}
+	}
+
+	return crawlPagesFrom(ctx, c, path, firstURL, next, opts...)
+}
+
+// crawlPagesFrom is like [crawlPages] but fetches the first page from the
+// already built URL firstPageURL. path names the crawl for the
+// [CrawlDepthHook].
+func crawlPagesFrom[T any](ctx context.Context, c *Client, path, firstPageURL string, next func(*T) string, opts ...CrawlOption) iter.Seq2[*T, error] {
+	allowVersionMismatch := c.allowNextLinkVersionMismatch || newCrawlOptions(opts).allowNextLinkVersionMismatch
+
+	return func(yield func(*T, error) bool) {
+		if err := ctx.Err(); err != nil {
+			yield(nil, err)
+			return
+		}
+
+		nextURL := firstPageURL
+		firstURL, err := url.Parse(nextURL)
+		if err != nil {
+			yield(nil, fmt.Errorf("parse page url: %w", err))
+			return
+		}
+
+		budget := newRetryBudget(c.crawlRetryBudget)
+		items, fetched := 0, 0
+		if c.crawlDepthHook != nil {
+			defer func() { c.crawlDepthHook(path, fetched) }()
+		}
+		for page := 0; nextURL != ""; page++ {
+			fail := func(err error) {
+				yield(nil, &PageError{PageIndex: page, RequestURL: nextURL, ItemsFetchedSoFar: items, Err: err})
+			}
+
+			if err := ctx.Err(); err != nil {
+				fail(err)
+				return
+			}
+
+			if page >= maxPages {
+				fail(fmt.Errorf("pagination exceeded %d pages", maxPages))
+				return
+			}
+
+			response := new(T)
+			if err := c.doJSONExchange(ctx, http.MethodGet, nextURL, &exchange{budget: budget}, nil, response, http.StatusOK); err != nil {
+				fail(err)
+				return
+			}
+			fetched++
+
+			items += pageItemCount(response)
+			if !yield(response, nil) {
+				return
+			}
+
+			currentURL, err := url.Parse(nextURL)
+			if err != nil {
+				fail(fmt.Errorf("parse page url: %w", err))
+				return
+			}
+			link, err := resolveNextURL(currentURL, next(response))
+			if err != nil {
+				fail(err)
+				return
+			}
+			if link != "" {
+				resolved, err := url.Parse(link)
+				if err != nil {
+					fail(fmt.Errorf("parse next links url: %w", err))
+					return
+				}
+				if !withinBaseURL(c.baseURL, resolved) {
+					fail(fmt.Errorf("next links url %q escapes base URL", link))
+					return
+				}
+				if !allowVersionMismatch {
+					if err := checkNextLinkVersion(firstURL, link); err != nil {
+						fail(err)
+						return
+					}
+				}
+			}
+			nextURL = link
 		}
 	}
 }
@@ -113,3 +485,17 @@ func resolveNextURL(baseURL *url.URL, next string) (string, error) {
 
 	return baseURL.ResolveReference(parsed).String(), nil
 }
+
+// appendPage appends page to items, honoring the maxItems bound.
+// It returns [ErrMaxItemsReached] when page had to be truncated.
+func appendPage[T any](items, page []T, maxItems int) ([]T, error) {
+	if maxItems <= 0 {
+		return append(items, page...), nil
+	}
+
+	if remaining := maxItems - len(items); len(page) > remaining {
+		return append(items, page[:max(remaining, 0)]...), fmt.Errorf("%w: %d", ErrMaxItemsReached, maxItems)
+	}
+
+	return append(items, page...), nil
+}
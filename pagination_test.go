@@ -0,0 +1,564 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-json-experiment/json"
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/oauth2"
+)
+
+// fakePages returns a sequence yielding pages in order, then err if non-nil.
+func fakePages[T any](pages [][]T, err error) iter.Seq2[[]T, error] {
+	return func(yield func([]T, error) bool) {
+		for _, page := range pages {
+			if !yield(page, nil) {
+				return
+			}
+		}
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+func TestCollectPages(t *testing.T) {
+	errPage := errors.New("page 3 failed")
+
+	tests := map[string]struct {
+		seq     iter.Seq2[[]string, error]
+		want    []string
+		wantErr error
+	}{
+		"success: two pages are flattened in order": {
+			seq:  fakePages([][]string{{"a", "b"}, {"c"}}, nil),
+			want: []string{"a", "b", "c"},
+		},
+		"success: empty sequence": {
+			seq:  fakePages[string](nil, nil),
+			want: nil,
+		},
+		"error: first error stops collection": {
+			seq:     fakePages([][]string{{"a", "b"}, {"c"}}, errPage),
+			want:    []string{"a", "b", "c"},
+			wantErr: errPage,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			got, err := CollectPages(tt.seq)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CollectPages error = %v, want %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("CollectPages mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// newFailingPagesServer serves org devices two per page and fails page
+// failPage (zero-based) with a 500 response.
+func newFailingPagesServer(t *testing.T, failPage int) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == failPage {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"errors":[{"status":"500","code":"INTERNAL_ERROR","title":"boom","detail":"boom"}]}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"data":[{"id":"device-%[1]d-a","type":"orgDevices","attributes":{"partNumber":"PART-%[1]d-A"}},{"id":"device-%[1]d-b","type":"orgDevices","attributes":{"partNumber":"PART-%[1]d-B"}}],"links":{"self":"/v1/orgDevices","next":"/v1/orgDevices?page=%[2]d"}}`, page, page+1)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestPageError(t *testing.T) {
+	tests := map[string]struct {
+		failPage         int
+		wantRequestURL   string
+		wantExportPrefix string
+		wantItems        int
+	}{
+		"error: first page fails": {
+			failPage:         0,
+			wantRequestURL:   "/v1/orgDevices?limit=100",
+			wantExportPrefix: "/v1/orgDevices?",
+			wantItems:        0,
+		},
+		"error: third page fails": {
+			failPage:         2,
+			wantRequestURL:   "/v1/orgDevices?page=2",
+			wantExportPrefix: "/v1/orgDevices?page=2",
+			wantItems:        4,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server := newFailingPagesServer(t, tt.failPage)
+			client := testClientForServer(t, server)
+
+			// Client helper built on the client's JSON request pipeline.
+			_, err := client.ExportOrgDevicesCSV(ctx, io.Discard, nil)
+			var pageErr *PageError
+			if !errors.As(err, &pageErr) {
+				t.Fatalf("ExportOrgDevicesCSV error = %v, want *PageError", err)
+			}
+			// The export adds a fields query to the first page only.
+			want := PageError{PageIndex: tt.failPage, ItemsFetchedSoFar: tt.wantItems}
+			got := PageError{PageIndex: pageErr.PageIndex, ItemsFetchedSoFar: pageErr.ItemsFetchedSoFar}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Fatalf("PageError mismatch (-want +got):\n%s", diff)
+			}
+			if !strings.HasPrefix(pageErr.RequestURL, server.URL+tt.wantExportPrefix) {
+				t.Fatalf("PageError.RequestURL = %q, want prefix %q", pageErr.RequestURL, server.URL+tt.wantExportPrefix)
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+				t.Fatalf("ExportOrgDevicesCSV error = %v, want it to wrap a 500 *APIError", err)
+			}
+
+			// Exported PageIterator, via FetchOrgDevicePartNumbers.
+			_, err = client.FetchOrgDevicePartNumbers(ctx)
+			if !errors.As(err, &pageErr) {
+				t.Fatalf("FetchOrgDevicePartNumbers error = %v, want *PageError", err)
+			}
+			got = PageError{PageIndex: pageErr.PageIndex, RequestURL: pageErr.RequestURL, ItemsFetchedSoFar: pageErr.ItemsFetchedSoFar}
+			want.RequestURL = server.URL + tt.wantRequestURL
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Fatalf("PageError mismatch (-want +got):\n%s", diff)
+			}
+			if !strings.Contains(err.Error(), fmt.Sprintf("page %d (", tt.failPage)) || !strings.Contains(err.Error(), "500") {
+				t.Fatalf("FetchOrgDevicePartNumbers error = %q, want page index and status", err)
+			}
+		})
+	}
+}
+
+func TestPageError_UnwrapContext(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	server := newFailingPagesServer(t, -1)
+	client := testClientForServer(t, server)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var err error
+	for _, pageErr := range crawlPages(ctx, client, orgDevicesPath, nil, func(r *OrgDevicesResponse) string { return r.Links.Next }) {
+		if pageErr != nil {
+			err = pageErr
+			break
+		}
+		cancel()
+	}
+
+	var pageErr *PageError
+	if !errors.As(err, &pageErr) || pageErr.PageIndex != 1 || pageErr.ItemsFetchedSoFar != 2 {
+		t.Fatalf("crawl error = %#v, want *PageError for page 1 after 2 items", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("crawl error = %v, want it to wrap context.Canceled", err)
+	}
+}
+
+func TestWithCrawlDepthHook(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	const (
+		pageCount = 4
+		pageSize  = 3
+	)
+
+	type depth struct {
+		Path  string
+		Pages int
+	}
+
+	tests := map[string]struct {
+		failPage int
+		crawl    func(context.Context, *Client) error
+		want     []depth
+		wantErr  bool
+	}{
+		"success: complete crawl": {
+			crawl: func(ctx context.Context, c *Client) error {
+				_, err := c.OrgDeviceCountCrawled(ctx, nil)
+				return err
+			},
+			want: []depth{{Path: orgDevicesPath, Pages: pageCount}},
+		},
+		"success: early termination": {
+			crawl: func(ctx context.Context, c *Client) error {
+				for _, err := range c.OrgDevicesUpdatedSince(ctx, time.Time{}, nil) {
+					return err
+				}
+				return nil
+			},
+			want: []depth{{Path: orgDevicesPath, Pages: 1}},
+		},
+		"success: page iterator crawl": {
+			crawl: func(ctx context.Context, c *Client) error {
+				_, err := c.FetchOrgDevicePartNumbers(ctx)
+				return err
+			},
+			want: []depth{{Path: orgDevicesPath, Pages: pageCount}},
+		},
+		"success: page iterator stopped by max items": {
+			crawl: func(ctx context.Context, c *Client) error {
+				_, err := c.FetchOrgDevicePartNumbers(ctx, WithMaxItems(pageSize+1))
+				if errors.Is(err, ErrMaxItemsReached) {
+					return nil
+				}
+				return err
+			},
+			want: []depth{{Path: orgDevicesPath, Pages: 2}},
+		},
+		"error: failed page": {
+			failPage: 3,
+			crawl: func(ctx context.Context, c *Client) error {
+				_, err := c.OrgDeviceCountCrawled(ctx, nil)
+				return err
+			},
+			want:    []depth{{Path: orgDevicesPath, Pages: 2}},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			var served atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				pageNumber := 1
+				if page := r.URL.Query().Get("page"); page != "" {
+					pageNumber, _ = strconv.Atoi(page)
+				}
+				if pageNumber == tt.failPage {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				served.Add(1)
+
+				nextLink := ""
+				if pageNumber < pageCount {
+					nextLink = fmt.Sprintf("/v1/orgDevices?page=%d", pageNumber+1)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(buildOrgDevicesPageJSON(pageNumber, pageSize, nextLink))
+			}))
+			t.Cleanup(server.Close)
+
+			var (
+				mu  sync.Mutex
+				got []depth
+			)
+			client := testClientForServer(t, server, WithCrawlDepthHook(func(path string, pages int) {
+				mu.Lock()
+				defer mu.Unlock()
+				got = append(got, depth{Path: path, Pages: pages})
+			}))
+
+			if err := tt.crawl(ctx, client); (err != nil) != tt.wantErr {
+				t.Fatalf("crawl error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("reported depths mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(int(served.Load()), got[0].Pages); diff != "" {
+				t.Fatalf("reported depth differs from pages served (-served +reported):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithCrawlPageSize(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	const (
+		fleetSize         = 250
+		serverDefaultSize = 20
+	)
+
+	tests := map[string]struct {
+		opts         []ClientOption
+		crawl        func(context.Context, *Client) (int, error)
+		wantRequests int32
+		wantErr      string
+	}{
+		"success: default page size": {
+			crawl: func(ctx context.Context, c *Client) (int, error) {
+				partNumbers, err := c.FetchOrgDevicePartNumbers(ctx)
+				return len(partNumbers), err
+			},
+			wantRequests: 3,
+		},
+		"success: server default page size": {
+			opts: []ClientOption{WithCrawlPageSize(0)},
+			crawl: func(ctx context.Context, c *Client) (int, error) {
+				partNumbers, err := c.FetchOrgDevicePartNumbers(ctx)
+				return len(partNumbers), err
+			},
+			wantRequests: 13,
+		},
+		"success: configured page size": {
+			opts: []ClientOption{WithCrawlPageSize(maxPageLimit)},
+			crawl: func(ctx context.Context, c *Client) (int, error) {
+				result, err := c.FetchOrgDevices(ctx, nil)
+				if err != nil {
+					return 0, err
+				}
+				return len(result.Devices), nil
+			},
+			wantRequests: 1,
+		},
+		"success: caller limit wins": {
+			crawl: func(ctx context.Context, c *Client) (int, error) {
+				return c.ExportOrgDevicesCSV(ctx, io.Discard, &ExportOptions{Limit: 50})
+			},
+			wantRequests: 5,
+		},
+		"error: page size too large": {
+			opts:    []ClientOption{WithCrawlPageSize(maxPageLimit + 1)},
+			wantErr: "crawl page size: limit 1001 is out of range [0, 1000]",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				query := r.URL.Query()
+				limit, _ := strconv.Atoi(query.Get("limit"))
+				if limit == 0 {
+					limit = serverDefaultSize
+				}
+				offset, _ := strconv.Atoi(query.Get("cursor"))
+				end := min(offset+limit, fleetSize)
+
+				data := make([]OrgDevice, 0, end-offset)
+				for i := offset; i < end; i++ {
+					data = append(data, OrgDevice{ID: fmt.Sprintf("device-%d", i), Type: "orgDevices", Attributes: &OrgDeviceAttributes{PartNumber: "P"}})
+				}
+				response := OrgDevicesResponse{Data: data}
+				if end < fleetSize {
+					next := url.Values{"cursor": {strconv.Itoa(end)}}
+					if query.Has("limit") {
+						next.Set("limit", query.Get("limit"))
+					}
+					response.Links.Next = "/v1/orgDevices?" + next.Encode()
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.MarshalWrite(w, response)
+			}))
+			t.Cleanup(server.Close)
+
+			tokenSource := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token"})
+			client, err := NewClientWithBaseURL(server.Client(), tokenSource, server.URL, tt.opts...)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error mismatch: got=%v want=%q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewClientWithBaseURL returned error: %v", err)
+			}
+
+			n, err := tt.crawl(ctx, client)
+			if err != nil {
+				t.Fatalf("crawl returned error: %v", err)
+			}
+			if diff := cmp.Diff(fleetSize, n); diff != "" {
+				t.Fatalf("item count mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantRequests, requests.Load()); diff != "" {
+				t.Fatalf("request count mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNextLinkVersionMismatch(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	const v1Page = `{"data":[{"id":"device-1","type":"orgDevices","attributes":{"partNumber":"PART-1"}}],"links":{"self":"/v1/orgDevices","next":%q}}`
+
+	tests := map[string]struct {
+		next         string
+		v2Body       string
+		allow        bool
+		wantRequests int64
+		wantDevices  int
+		wantMismatch bool
+		wantDecode   bool
+	}{
+		"success: matching prefix follows": {
+			next:         "/v1/orgDevices?page=2",
+			wantRequests: 2,
+			wantDevices:  2,
+		},
+		"error: mismatched prefix is not requested": {
+			next:         "/v2/orgDevices?page=2",
+			v2Body:       `{"data":[{"id":"device-2","type":"orgDevices"}],"links":{"self":"/v2/orgDevices"}}`,
+			wantRequests: 1,
+			wantMismatch: true,
+		},
+		"success: allowed mismatch decodes the page": {
+			next:         "/v2/orgDevices?page=2",
+			v2Body:       `{"data":[{"id":"device-2","type":"orgDevices"}],"links":{"self":"/v2/orgDevices"}}`,
+			allow:        true,
+			wantRequests: 2,
+			wantDevices:  2,
+		},
+		"error: allowed mismatch fails with the decode error": {
+			next:         "/v2/orgDevices?page=2",
+			v2Body:       `{"data":{"devices":[]}}`,
+			allow:        true,
+			wantRequests: 2,
+			wantDecode:   true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			var requests atomic.Int64
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case strings.HasPrefix(r.URL.Path, "/v2/"):
+					fmt.Fprint(w, tt.v2Body)
+				case r.URL.Query().Get("page") == "2":
+					fmt.Fprint(w, `{"data":[{"id":"device-2","type":"orgDevices","attributes":{"partNumber":"PART-2"}}],"links":{"self":"/v1/orgDevices"}}`)
+				default:
+					fmt.Fprintf(w, v1Page, tt.next)
+				}
+			}))
+			t.Cleanup(server.Close)
+
+			var opts []ClientOption
+			if tt.allow {
+				opts = append(opts, WithAllowNextLinkVersionMismatch())
+			}
+			client := testClientForServer(t, server, opts...)
+
+			check := func(method string, devices int, err error) {
+				t.Helper()
+
+				if diff := cmp.Diff(tt.wantRequests, requests.Swap(0)); diff != "" {
+					t.Fatalf("%s request count mismatch (-want +got):\n%s", method, diff)
+				}
+				if diff := cmp.Diff(tt.wantMismatch, errors.Is(err, ErrNextLinkVersionMismatch)); diff != "" {
+					t.Fatalf("%s error = %v, mismatch (-want +got):\n%s", method, err, diff)
+				}
+				if tt.wantMismatch {
+					var mismatch *NextLinkVersionMismatchError
+					if !errors.As(err, &mismatch) {
+						t.Fatalf("%s error = %v, want *NextLinkVersionMismatchError", method, err)
+					}
+					want := NextLinkVersionMismatchError{RequestPath: "/v1/orgDevices", NextPath: "/v2/orgDevices"}
+					if diff := cmp.Diff(want, *mismatch); diff != "" {
+						t.Fatalf("%s mismatch error (-want +got):\n%s", method, diff)
+					}
+					return
+				}
+				if tt.wantDecode {
+					if err == nil || !strings.Contains(err.Error(), "decode") {
+						t.Fatalf("%s error = %v, want a decode error", method, err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("%s: %v", method, err)
+				}
+				if diff := cmp.Diff(tt.wantDevices, devices); diff != "" {
+					t.Fatalf("%s device count mismatch (-want +got):\n%s", method, diff)
+				}
+			}
+
+			// Client crawl.
+			fetched, err := client.FetchOrgDevices(ctx, nil)
+			var devices int
+			if fetched != nil {
+				devices = len(fetched.Devices)
+			}
+			check("FetchOrgDevices", devices, err)
+
+			// Exported PageIterator, via FetchOrgDevicePartNumbers.
+			partNumbers, err := client.FetchOrgDevicePartNumbers(ctx)
+			check("FetchOrgDevicePartNumbers", len(partNumbers), err)
+		})
+	}
+}
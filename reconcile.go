@@ -0,0 +1,134 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"context"
+	"iter"
+	"maps"
+	"slices"
+)
+
+// ActionKind is the kind of change a reconcile [Action] asks for.
+type ActionKind int
+
+const (
+	// ActionAssign asks for a device to be assigned to the MDM server.
+	ActionAssign ActionKind = iota + 1
+
+	// ActionUnassign asks for a device to be unassigned from the MDM server.
+	ActionUnassign
+)
+
+// String implements [fmt.Stringer].
+func (k ActionKind) String() string {
+	switch k {
+	case ActionAssign:
+		return "assign"
+	case ActionUnassign:
+		return "unassign"
+	default:
+		return "unknown"
+	}
+}
+
+// Action is a single change needed to make an MDM server's device
+// assignments match the desired set.
+type Action struct {
+	Kind     ActionKind
+	DeviceID string
+}
+
+// StreamReconcile compares the devices currently assigned to the MDM server
+// with the desired device IDs and emits the actions needed to reconcile them.
+//
+// The server's current device linkages are crawled first and only their IDs
+// are held in memory, so memory is bounded by the server's current assignment
+// count; the desired sequence is consumed lazily and never materialized. An
+// [ActionAssign] is emitted as soon as a desired device is found to be
+// unassigned, and [ActionUnassign] actions for the remaining current devices
+// follow in ID order once desired is exhausted. Desired IDs should be unique:
+// a repeated ID that is not currently assigned yields repeated assign
+// actions.
+//
+// Both channels are closed when reconciliation finishes. At most one error,
+// including the context error on cancellation, is sent on the error channel,
+// after which no further actions are emitted.
+func (c *Client) StreamReconcile(ctx context.Context, mdmServerID string, desired iter.Seq[string]) (<-chan Action, <-chan error) {
+	actions := make(chan Action)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		defer close(actions)
+
+		if err := c.streamReconcile(ctx, mdmServerID, desired, actions); err != nil {
+			errc <- err
+		}
+	}()
+
+	return actions, errc
+}
+
+func (c *Client) streamReconcile(ctx context.Context, mdmServerID string, desired iter.Seq[string], actions chan<- Action) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	current, err := c.mdmServerDeviceIDs(ctx, mdmServerID)
+	if err != nil {
+		return err
+	}
+
+	emit := func(action Action) error {
+		select {
+		case actions <- action:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	// kept holds desired devices that are already assigned, moved out of
+	// current so it ends up holding exactly the devices to unassign.
+	kept := make(map[string]struct{})
+	for id := range desired {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if _, ok := current[id]; ok {
+			delete(current, id)
+			kept[id] = struct{}{}
+			continue
+		}
+		if _, ok := kept[id]; ok {
+			continue
+		}
+		if err := emit(Action{Kind: ActionAssign, DeviceID: id}); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(current)) {
+		if err := emit(Action{Kind: ActionUnassign, DeviceID: id}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
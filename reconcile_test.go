@@ -0,0 +1,189 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClient_StreamReconcile(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		assignments map[string]string
+		desired     []string
+		want        []Action
+	}{
+		"success: assign and unassign": {
+			assignments: map[string]string{
+				"device-1": "mdm-1",
+				"device-2": "mdm-1",
+				"device-3": "mdm-1",
+				"device-9": "mdm-2",
+			},
+			desired: []string{"device-2", "device-4", "device-9", "device-2"},
+			want: []Action{
+				{Kind: ActionAssign, DeviceID: "device-4"},
+				{Kind: ActionAssign, DeviceID: "device-9"},
+				{Kind: ActionUnassign, DeviceID: "device-1"},
+				{Kind: ActionUnassign, DeviceID: "device-3"},
+			},
+		},
+		"success: already reconciled": {
+			assignments: map[string]string{
+				"device-1": "mdm-1",
+				"device-2": "mdm-1",
+				"device-3": "mdm-1",
+			},
+			desired: []string{"device-3", "device-1", "device-2"},
+		},
+		"success: empty desired unassigns everything": {
+			assignments: map[string]string{
+				"device-2": "mdm-1",
+				"device-1": "mdm-1",
+			},
+			want: []Action{
+				{Kind: ActionUnassign, DeviceID: "device-1"},
+				{Kind: ActionUnassign, DeviceID: "device-2"},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server, _, _, _ := newAssignmentServer(t, tt.assignments)
+			client := testClientForServer(t, server)
+
+			actions, errc := client.StreamReconcile(ctx, "mdm-1", slices.Values(tt.desired))
+
+			var got []Action
+			for action := range actions {
+				got = append(got, action)
+			}
+			if err := <-errc; err != nil {
+				t.Fatalf("StreamReconcile returned error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("actions mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_StreamReconcileErrors(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	t.Run("error: linkage fetch failure", func(t *testing.T) {
+		ctx := t.Context()
+		if err := ctx.Err(); err != nil {
+			t.Fatalf("context error: %v", err)
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		t.Cleanup(server.Close)
+		client := testClientForServer(t, server)
+
+		actions, errc := client.StreamReconcile(ctx, "mdm-1", slices.Values([]string{"device-1"}))
+		for action := range actions {
+			t.Fatalf("unexpected action: %+v", action)
+		}
+		if err := <-errc; err == nil || !strings.Contains(err.Error(), "status=500") {
+			t.Fatalf("expected API error, got %v", err)
+		}
+	})
+
+	t.Run("error: canceled while emitting", func(t *testing.T) {
+		ctx := t.Context()
+		if err := ctx.Err(); err != nil {
+			t.Fatalf("context error: %v", err)
+		}
+
+		server, _, _, _ := newAssignmentServer(t, nil)
+		client := testClientForServer(t, server)
+
+		desired := func(yield func(string) bool) {
+			for i := range 1_000_000 {
+				if !yield(fmt.Sprintf("device-%d", i)) {
+					return
+				}
+			}
+		}
+
+		reconcileCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		actions, errc := client.StreamReconcile(reconcileCtx, "mdm-1", desired)
+
+		<-actions
+		cancel()
+		for range actions {
+		}
+		if err := <-errc; !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	})
+}
+
+func TestActionKind_String(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		kind ActionKind
+		want string
+	}{
+		"success: assign":   {kind: ActionAssign, want: "assign"},
+		"success: unassign": {kind: ActionUnassign, want: "unassign"},
+		"success: unknown":  {kind: 0, want: "unknown"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, tt.kind.String()); diff != "" {
+				t.Fatalf("String mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
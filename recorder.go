@@ -0,0 +1,262 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+// redactedValue replaces the values of sensitive headers in recorded interactions.
+const redactedValue = "REDACTED"
+
+// sensitiveHeaders lists headers whose values are never written to disk.
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization"}
+
+// interaction is a recorded HTTP request/response pair as stored on disk.
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+type recordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitzero"`
+}
+
+type recordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitzero"`
+}
+
+// WithRecorder records every HTTP interaction of the client to dir, one JSON
+// file per request/response pair, named in request order. Authorization and
+// cookie headers are redacted. The recordings can be served back with
+// [NewReplayTransport] for deterministic golden tests.
+func WithRecorder(dir string) ClientOption {
+	return func(o *clientOptions) {
+		o.recordDir = dir
+	}
+}
+
+// recordingTransport is an [http.RoundTripper] that writes each interaction to dir.
+type recordingTransport struct {
+	base http.RoundTripper
+	dir  string
+	seq  atomic.Int64
+}
+
+var _ http.RoundTripper = (*recordingTransport)(nil)
+
+func newRecordingTransport(base http.RoundTripper, dir string) (*recordingTransport, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create recorder directory: %w", err)
+	}
+
+	return &recordingTransport{
+		base: base,
+		dir:  dir,
+	}, nil
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("record request body: %w", err)
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("record response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	record := interaction{
+		Request: recordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: redactHeader(req.Header),
+			Body:   string(reqBody),
+		},
+		Response: recordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     redactHeader(resp.Header),
+			Body:       string(respBody),
+		},
+	}
+	payload, err := json.Marshal(record, json.Deterministic(true), jsontext.WithIndent("\t"))
+	if err != nil {
+		return nil, fmt.Errorf("encode recorded interaction: %w", err)
+	}
+
+	name := fmt.Sprintf("%04d-%s.json", t.seq.Add(1), strings.ToLower(req.Method))
+	if err := os.WriteFile(filepath.Join(t.dir, name), append(payload, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("write recorded interaction: %w", err)
+	}
+
+	return resp, nil
+}
+
+func redactHeader(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+
+	redacted := header.Clone()
+	for _, key := range sensitiveHeaders {
+		if _, ok := redacted[key]; ok {
+			redacted[key] = []string{redactedValue}
+		}
+	}
+
+	return redacted
+}
+
+// ReplayTransport is an [http.RoundTripper] that serves interactions recorded
+// with [WithRecorder] instead of sending requests over the network.
+//
+// A request is answered by the first not yet served interaction with the same
+// method, path, and query; the scheme and host are ignored so recordings can
+// be replayed against any base URL. Each interaction is served at most once.
+type ReplayTransport struct {
+	mu           sync.Mutex
+	interactions []interaction
+	served       []bool
+}
+
+var _ http.RoundTripper = (*ReplayTransport)(nil)
+
+// NewReplayTransport loads the interactions recorded in dir.
+func NewReplayTransport(dir string) (*ReplayTransport, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("list recorded interactions: %w", err)
+	}
+	slices.Sort(names)
+
+	interactions := make([]interaction, 0, len(names))
+	for _, name := range names {
+		payload, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("read recorded interaction: %w", err)
+		}
+
+		var record interaction
+		if err := json.Unmarshal(payload, &record); err != nil {
+			return nil, fmt.Errorf("decode recorded interaction %s: %w", filepath.Base(name), err)
+		}
+		interactions = append(interactions, record)
+	}
+
+	return &ReplayTransport{
+		interactions: interactions,
+		served:       make([]bool, len(interactions)),
+	}, nil
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, record := range t.interactions {
+		if t.served[i] || !matchesRecordedRequest(req, record.Request) {
+			continue
+		}
+		t.served[i] = true
+
+		header := record.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", record.Response.StatusCode, http.StatusText(record.Response.StatusCode)),
+			StatusCode:    record.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(record.Response.Body)),
+			ContentLength: int64(len(record.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("replay: no recorded interaction for %s %s", req.Method, req.URL.RequestURI())
+}
+
+// Remaining returns the number of recorded interactions not yet served.
+func (t *ReplayTransport) Remaining() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	remaining := 0
+	for _, served := range t.served {
+		if !served {
+			remaining++
+		}
+	}
+
+	return remaining
+}
+
+func matchesRecordedRequest(req *http.Request, recorded recordedRequest) bool {
+	if req.Method != recorded.Method {
+		return false
+	}
+
+	recordedURL, err := url.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+
+	return req.URL.EscapedPath() == recordedURL.EscapedPath() && req.URL.RawQuery == recordedURL.RawQuery
+}
@@ -0,0 +1,149 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-json-experiment/json"
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/oauth2"
+)
+
+func TestRecorderReplay(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/orgDevices":
+			fmt.Fprint(w, `{"data":[{"id":"device-1","type":"orgDevices","attributes":{"serialNumber":"SERIAL-1"}}],"links":{"self":"/v1/orgDevices"}}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/orgDeviceActivities":
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"data":{"id":"activity-1","type":"orgDeviceActivities"},"links":{"self":"/v1/orgDeviceActivities/activity-1"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	dir := filepath.Join(t.TempDir(), "golden")
+	recordClient := testClientForServer(t, server, WithRecorder(dir))
+
+	exercise := func(client *Client) (string, string, error) {
+		devices, err := client.GetOrgDevices(ctx, &GetOrgDevicesOptions{Limit: 1})
+		if err != nil {
+			return "", "", err
+		}
+		activity, err := client.CreateOrgDeviceActivity(ctx, newOrgDeviceActivityCreateRequest(OrgDeviceActivityTypeAssignDevices, "server-1", "device-1"))
+		if err != nil {
+			return "", "", err
+		}
+		return devices.Data[0].Attributes.SerialNumber, activity.Data.ID, nil
+	}
+
+	wantSerial, wantActivityID, err := exercise(recordClient)
+	if err != nil {
+		t.Fatalf("recording returned error: %v", err)
+	}
+
+	names, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatalf("list recordings: %v", err)
+	}
+	for i, name := range names {
+		names[i] = filepath.Base(name)
+	}
+	if diff := cmp.Diff([]string{"0001-get.json", "0002-post.json"}, names); diff != "" {
+		t.Fatalf("recording names mismatch (-want +got):\n%s", diff)
+	}
+
+	payload, err := os.ReadFile(filepath.Join(dir, "0002-post.json"))
+	if err != nil {
+		t.Fatalf("read recording: %v", err)
+	}
+	if strings.Contains(string(payload), "test-token") || strings.Contains(string(payload), "session=secret") {
+		t.Fatalf("recording leaks credentials:\n%s", payload)
+	}
+	var record interaction
+	if err := json.Unmarshal(payload, &record); err != nil {
+		t.Fatalf("decode recording: %v", err)
+	}
+	if diff := cmp.Diff([]string{redactedValue}, record.Request.Header["Authorization"]); diff != "" {
+		t.Fatalf("authorization header mismatch (-want +got):\n%s", diff)
+	}
+	if !strings.Contains(record.Request.Body, `"ASSIGN_DEVICES"`) {
+		t.Fatalf("request body not recorded: %q", record.Request.Body)
+	}
+	if diff := cmp.Diff(http.StatusCreated, record.Response.StatusCode); diff != "" {
+		t.Fatalf("status code mismatch (-want +got):\n%s", diff)
+	}
+
+	replay, err := NewReplayTransport(dir)
+	if err != nil {
+		t.Fatalf("NewReplayTransport returned error: %v", err)
+	}
+	tokenSource := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "replay-token"})
+	replayClient, err := NewClient(&http.Client{Transport: replay}, tokenSource)
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	gotSerial, gotActivityID, err := exercise(replayClient)
+	if err != nil {
+		t.Fatalf("replay returned error: %v", err)
+	}
+	if diff := cmp.Diff(wantSerial, gotSerial); diff != "" {
+		t.Fatalf("serial number mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(wantActivityID, gotActivityID); diff != "" {
+		t.Fatalf("activity ID mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(0, replay.Remaining()); diff != "" {
+		t.Fatalf("remaining interactions mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := replayClient.GetOrgDevices(ctx, &GetOrgDevicesOptions{Limit: 1}); err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Fatalf("expected exhausted replay error, got %v", err)
+	}
+}
+
+func TestNewReplayTransportErrors(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "0001-get.json"), []byte("{"), 0o644); err != nil {
+		t.Fatalf("write recording: %v", err)
+	}
+
+	if _, err := NewReplayTransport(dir); err == nil {
+		t.Fatal("expected error for malformed recording")
+	}
+}
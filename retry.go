@@ -0,0 +1,199 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+const (
+	defaultRetryMaxRetries = 3
+	defaultRetryBaseDelay  = 500 * time.Millisecond
+	defaultRetryMaxDelay   = 8 * time.Second
+)
+
+// RetryPolicy controls how [Client] retries GET requests that failed transiently.
+// The zero value disables retries.
+//
+// A request is retried on transport errors, 429 and 5xx responses, and, at
+// most once per request, when a 2xx response body fails to decode. Proxies
+// occasionally truncate responses and a re-fetch usually succeeds; a decode
+// error that persists across the re-fetch is a genuine schema mismatch and is
+// returned to the caller.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries after the initial attempt.
+	MaxRetries int `json:"maxRetries,omitzero" yaml:"maxRetries,omitempty"`
+
+	// BaseDelay is the backoff delay before the first retry. It doubles on
+	// every further retry, capped at MaxDelay, with jitter applied.
+	BaseDelay time.Duration `json:"baseDelay,omitzero" yaml:"baseDelay,omitempty"`
+
+	// MaxDelay caps the backoff delay between retries, including delays
+	// requested by a Retry-After header. Zero retries without waiting.
+	MaxDelay time.Duration `json:"maxDelay,omitzero" yaml:"maxDelay,omitempty"`
+}
+
+// retryPolicyJSON is the JSON form of [RetryPolicy], with the delays encoded
+// as duration strings.
+type retryPolicyJSON struct {
+	MaxRetries int          `json:"maxRetries,omitzero"`
+	BaseDelay  jsonDuration `json:"baseDelay,omitzero"`
+	MaxDelay   jsonDuration `json:"maxDelay,omitzero"`
+}
+
+// MarshalJSONTo implements [json.MarshalerTo].
+func (p RetryPolicy) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return json.MarshalEncode(enc, retryPolicyJSON{
+		MaxRetries: p.MaxRetries,
+		BaseDelay:  jsonDuration(p.BaseDelay),
+		MaxDelay:   jsonDuration(p.MaxDelay),
+	})
+}
+
+// UnmarshalJSONFrom implements [json.UnmarshalerFrom].
+func (p *RetryPolicy) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	var v retryPolicyJSON
+	if err := json.UnmarshalDecode(dec, &v); err != nil {
+		return err
+	}
+
+	*p = RetryPolicy{
+		MaxRetries: v.MaxRetries,
+		BaseDelay:  time.Duration(v.BaseDelay),
+		MaxDelay:   time.Duration(v.MaxDelay),
+	}
+	return nil
+}
+
+// DefaultRetryPolicy returns the recommended retry policy for production use.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: defaultRetryMaxRetries,
+		BaseDelay:  defaultRetryBaseDelay,
+		MaxDelay:   defaultRetryMaxDelay,
+	}
+}
+
+// ErrCrawlRetryBudgetExceeded is returned when a multi-page crawl needs more
+// retries than allowed by [WithCrawlRetryBudget].
+var ErrCrawlRetryBudgetExceeded = errors.New("crawl retry budget exceeded")
+
+// WithCrawlRetryBudget caps the total number of retries, across all pages, of
+// each multi-page crawl the client performs, such as
+// [Client.ExportOrgDevicesCSV]. Without a cap, a flaky connection during a
+// partial outage may retry every page of a long crawl. Once the budget is
+// spent, the next failure that would be retried instead ends the crawl with
+// an error wrapping [ErrCrawlRetryBudgetExceeded]. Zero, the default, means
+// no cap; per-request retries are still bounded by the [RetryPolicy].
+func WithCrawlRetryBudget(n int) ClientOption {
+	return func(o *clientOptions) {
+		o.crawlRetryBudget = n
+	}
+}
+
+// retryBudget counts the retries left for one crawl. It is not safe for
+// concurrent use; a crawl fetches its pages sequentially.
+type retryBudget struct {
+	limit int
+	used  int
+}
+
+// newRetryBudget returns a budget of limit retries, or nil when limit is zero.
+func newRetryBudget(limit int) *retryBudget {
+	if limit <= 0 {
+		return nil
+	}
+
+	return &retryBudget{limit: limit}
+}
+
+// take consumes one retry, reporting false when the budget is spent.
+func (b *retryBudget) take() bool {
+	if b.used >= b.limit {
+		return false
+	}
+	b.used++
+
+	return true
+}
+
+// Retry reasons reported by a single request attempt.
+const (
+	retryReasonTransport = "transport error"
+	retryReasonStatus    = "retryable status"
+	retryReasonDecode    = "decode error"
+)
+
+// retryableStatus reports whether an HTTP status code is worth retrying.
+func retryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+// retryAfter returns the delay requested by a Retry-After header given in
+// seconds, or zero when the header is absent or not in that form.
+func retryAfter(header http.Header) time.Duration {
+	seconds, err := strconv.Atoi(header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+// jitteredBackoff returns the equal-jitter exponential delay before retry attempt+1.
+func jitteredBackoff(attempt int, baseDelay, maxDelay time.Duration) time.Duration {
+	delay := baseDelay << attempt
+	if delay <= 0 || delay > maxDelay {
+		delay = maxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// isContextError reports whether err was caused by context cancellation or deadline.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
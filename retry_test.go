@@ -0,0 +1,263 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClient_RetryPolicy(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	const deviceBody = `{"data":{"type":"orgDevices","id":"device-1","attributes":{"serialNumber":"SERIAL-1"}}}`
+
+	type response struct {
+		status int
+		body   string
+	}
+
+	tests := map[string]struct {
+		policy       *RetryPolicy
+		responses    []response
+		wantRequests int32
+		wantSerial   string
+		wantErr      string
+	}{
+		"success: truncated body is re-fetched": {
+			policy: &RetryPolicy{MaxRetries: 3},
+			responses: []response{
+				{status: http.StatusOK, body: deviceBody[:len(deviceBody)/2]},
+				{status: http.StatusOK, body: deviceBody},
+			},
+			wantRequests: 2,
+			wantSerial:   "SERIAL-1",
+		},
+		"success: server error is retried": {
+			policy: &RetryPolicy{MaxRetries: 3},
+			responses: []response{
+				{status: http.StatusServiceUnavailable},
+				{status: http.StatusTooManyRequests},
+				{status: http.StatusOK, body: deviceBody},
+			},
+			wantRequests: 3,
+			wantSerial:   "SERIAL-1",
+		},
+		"error: schema mismatch is re-fetched only once": {
+			policy: &RetryPolicy{MaxRetries: 3},
+			responses: []response{
+				{status: http.StatusOK, body: `{"data":{"id":1}}`},
+			},
+			wantRequests: 2,
+			wantErr:      "decode response body",
+		},
+		"error: truncated body without retry policy": {
+			responses: []response{
+				{status: http.StatusOK, body: deviceBody[:len(deviceBody)/2]},
+				{status: http.StatusOK, body: deviceBody},
+			},
+			wantRequests: 1,
+			wantErr:      "decode response body",
+		},
+		"error: retries exhausted": {
+			policy: &RetryPolicy{MaxRetries: 2},
+			responses: []response{
+				{status: http.StatusBadGateway},
+			},
+			wantRequests: 3,
+			wantErr:      "abm api error: status=502",
+		},
+		"error: client error is not retried": {
+			policy: &RetryPolicy{MaxRetries: 3},
+			responses: []response{
+				{status: http.StatusNotFound},
+				{status: http.StatusOK, body: deviceBody},
+			},
+			wantRequests: 1,
+			wantErr:      "abm api error: status=404",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(requests.Add(1)) - 1
+				resp := tt.responses[min(n, len(tt.responses)-1)]
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(resp.status)
+				fmt.Fprint(w, resp.body)
+			}))
+			t.Cleanup(server.Close)
+
+			var opts []ClientOption
+			if tt.policy != nil {
+				opts = append(opts, WithRetryPolicy(*tt.policy))
+			}
+			client := testClientForServer(t, server, opts...)
+
+			got, err := client.GetOrgDevice(ctx, "device-1", nil)
+			if diff := cmp.Diff(tt.wantRequests, requests.Load()); diff != "" {
+				t.Fatalf("request count mismatch (-want +got):\n%s", diff)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetOrgDevice returned error: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantSerial, got.Data.Attributes.SerialNumber); diff != "" {
+				t.Fatalf("serial number mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_RetryPolicyNonGET(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	client := testClientForServer(t, server, WithRetryPolicy(RetryPolicy{MaxRetries: 3}))
+
+	_, err := client.CreateOrgDeviceActivity(ctx, newOrgDeviceActivityCreateRequest(OrgDeviceActivityTypeAssignDevices, "server-1", "device-1"))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if diff := cmp.Diff(int32(1), requests.Load()); diff != "" {
+		t.Fatalf("request count mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestClient_CrawlRetryBudget(t *testing.T) {
+	const pages = 6
+
+	tests := map[string]struct {
+		budget       int
+		wantPages    int
+		wantRequests int32
+		wantErr      bool
+	}{
+		"success: no budget retries every page": {
+			budget:       0,
+			wantPages:    pages,
+			wantRequests: 2 * pages,
+		},
+		"success: budget covers every retry": {
+			budget:       pages,
+			wantPages:    pages,
+			wantRequests: 2 * pages,
+		},
+		"error: crawl aborts once the budget is spent": {
+			budget:       3,
+			wantPages:    3,
+			wantRequests: 3*2 + 1,
+			wantErr:      true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			// Every page fails once with 503 before it is served.
+			var requests atomic.Int32
+			var mu sync.Mutex
+			failed := make(map[string]bool)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+
+				mu.Lock()
+				first := !failed[r.URL.RawQuery]
+				failed[r.URL.RawQuery] = true
+				mu.Unlock()
+				if first {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				next := ""
+				if page+1 < pages {
+					next = fmt.Sprintf("/v1/orgDevices?page=%d", page+1)
+				}
+				fmt.Fprintf(w, `{"data":[{"id":"device-%d","type":"orgDevices"}],"links":{"self":"/v1/orgDevices","next":%q}}`, page, next)
+			}))
+			t.Cleanup(server.Close)
+			client := testClientForServer(t, server, WithRetryPolicy(RetryPolicy{MaxRetries: 3}), WithCrawlRetryBudget(tt.budget))
+
+			var gotPages int
+			var err error
+			for _, pageErr := range crawlPages(ctx, client, orgDevicesPath, nil, func(r *OrgDevicesResponse) string { return r.Links.Next }) {
+				if pageErr != nil {
+					err = pageErr
+					break
+				}
+				gotPages++
+			}
+
+			if tt.wantErr {
+				if !errors.Is(err, ErrCrawlRetryBudgetExceeded) {
+					t.Fatalf("crawl error = %v, want ErrCrawlRetryBudgetExceeded", err)
+				}
+				if want := fmt.Sprintf("(%d retries)", tt.budget); !strings.Contains(err.Error(), want) {
+					t.Fatalf("crawl error = %v, want it to name the budget %q", err, want)
+				}
+			} else if err != nil {
+				t.Fatalf("crawl returned error: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantPages, gotPages); diff != "" {
+				t.Fatalf("page count mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantRequests, requests.Load()); diff != "" {
+				t.Fatalf("request count mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
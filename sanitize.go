@@ -0,0 +1,155 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+// SanitizeHook is called by a [Client] created with [WithSanitizeStrings] when
+// a response contained strings that had to be sanitized. method and path
+// identify the request, and fields lists the affected values as JSON Pointers
+// (RFC 6901) into the response body, such as "/data/0/attributes/color".
+// It may be called concurrently when the client is used from several goroutines.
+type SanitizeHook func(method, path string, fields []string)
+
+// WithSanitizeStrings makes the client sanitize strings in decoded responses:
+// invalid UTF-8 is replaced with the Unicode replacement character and NUL
+// bytes are removed, so that corrupt upstream data cannot break consumers such
+// as database loaders. Without it, a response containing invalid UTF-8 fails
+// to decode and NUL bytes are kept as is.
+func WithSanitizeStrings() ClientOption {
+	return func(o *clientOptions) {
+		o.sanitizeStrings = true
+	}
+}
+
+// WithSanitizeHook sets a hook reporting which response fields
+// [WithSanitizeStrings] modified. It has no effect on its own.
+func WithSanitizeHook(hook SanitizeHook) ClientOption {
+	return func(o *clientOptions) {
+		o.sanitizeHook = hook
+	}
+}
+
+// decodeSanitized decodes payload into responseBody, sanitizing strings, and
+// reports the affected fields to the client's sanitize hook.
+func (c *Client) decodeSanitized(method, path string, payload []byte, responseBody any) error {
+	fields, err := unsanitaryStringFields(payload)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(payload, responseBody, jsontext.AllowInvalidUTF8(true)); err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	stripNUL(reflect.ValueOf(responseBody))
+	if c.sanitizeHook != nil {
+		c.sanitizeHook(method, path, fields)
+	}
+
+	return nil
+}
+
+// unsanitaryStringFields returns JSON Pointers to the string values in payload
+// that contain invalid UTF-8 or NUL bytes.
+func unsanitaryStringFields(payload []byte) ([]string, error) {
+	dec := jsontext.NewDecoder(bytes.NewReader(payload), jsontext.AllowInvalidUTF8(true))
+
+	var fields []string
+	for {
+		kind := dec.PeekKind()
+		if kind == '"' && !nextIsObjectName(dec) {
+			value, err := dec.ReadValue()
+			if err != nil {
+				return nil, err
+			}
+			unquoted, err := jsontext.AppendUnquote(nil, value)
+			if err != nil || bytes.IndexByte(unquoted, 0) >= 0 {
+				fields = append(fields, string(dec.StackPointer()))
+			}
+			continue
+		}
+
+		if _, err := dec.ReadToken(); err != nil {
+			if errors.Is(err, io.EOF) {
+				return fields, nil
+			}
+			return nil, err
+		}
+	}
+}
+
+// nextIsObjectName reports whether the next token dec reads is an object member name.
+func nextIsObjectName(dec *jsontext.Decoder) bool {
+	kind, length := dec.StackIndex(dec.StackDepth())
+	return kind == '{' && length%2 == 0
+}
+
+// stripNUL removes NUL bytes from every string reachable from v.
+func stripNUL(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		if s := v.String(); strings.IndexByte(s, 0) >= 0 && v.CanSet() {
+			v.SetString(strings.ReplaceAll(s, "\x00", ""))
+		}
+	case reflect.Pointer:
+		if !v.IsNil() {
+			stripNUL(v.Elem())
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		// Values held in an interface are not addressable; sanitize a copy.
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		stripNUL(elem)
+		if v.CanSet() {
+			v.Set(elem)
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				stripNUL(v.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			stripNUL(v.Index(i))
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(iter.Value().Type()).Elem()
+			elem.Set(iter.Value())
+			stripNUL(elem)
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// corruptOrgDevicesPayload is an orgDevices response whose strings contain
+// invalid UTF-8 (raw \xff and a lone surrogate escape) and NUL bytes.
+const corruptOrgDevicesPayload = `{"data":[` +
+	`{"id":"device-1","type":"orgDevices","attributes":{"serialNumber":"SERIAL-1","color":"SIL` + "\xff" + `VER","imei":["35\u0000123","35456"]}},` +
+	`{"id":"device-2","type":"orgDevices","attributes":{"serialNumber":"SERIAL\u0000-2","deviceModel":"Mac\ud800Book","color":"BLACK"}}` +
+	`],"links":{"self":"https://api-business.apple.com/v1/orgDevices"}}`
+
+func TestClient_SanitizeStrings(t *testing.T) {
+	tests := map[string]struct {
+		opts        []ClientOption
+		wantErrText string
+		wantSerial  []string
+		wantColor   []string
+		wantIMEI    []string
+		wantModel   string
+		wantFields  []string
+	}{
+		"success: invalid UTF-8 is replaced and NUL bytes are stripped": {
+			opts:       []ClientOption{WithSanitizeStrings()},
+			wantSerial: []string{"SERIAL-1", "SERIAL-2"},
+			wantColor:  []string{"SIL�VER", "BLACK"},
+			wantIMEI:   []string{"35123", "35456"},
+			wantModel:  "Mac�Book",
+			wantFields: []string{
+				"/data/0/attributes/color",
+				"/data/0/attributes/imei/0",
+				"/data/1/attributes/serialNumber",
+				"/data/1/attributes/deviceModel",
+			},
+		},
+		"error: invalid UTF-8 fails to decode without the option": {
+			wantErrText: "invalid UTF-8",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, corruptOrgDevicesPayload)
+			}))
+			t.Cleanup(server.Close)
+
+			var mu sync.Mutex
+			var gotFields []string
+			hook := WithSanitizeHook(func(method, path string, fields []string) {
+				mu.Lock()
+				defer mu.Unlock()
+				if method != http.MethodGet || path != "/v1/orgDevices" {
+					t.Errorf("hook called for %s %s, want GET /v1/orgDevices", method, path)
+				}
+				gotFields = append(gotFields, fields...)
+			})
+			client := testClientForServer(t, server, append(tt.opts, hook)...)
+
+			resp, err := client.GetOrgDevices(ctx, nil)
+			if tt.wantErrText != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrText) {
+					t.Fatalf("GetOrgDevices error = %v, want containing %q", err, tt.wantErrText)
+				}
+				if gotFields != nil {
+					t.Fatalf("hook reported %v without WithSanitizeStrings", gotFields)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetOrgDevices returned error: %v", err)
+			}
+
+			var serials, colors []string
+			for _, device := range resp.Data {
+				serials = append(serials, device.Attributes.SerialNumber)
+				colors = append(colors, device.Attributes.Color)
+			}
+			if diff := cmp.Diff(tt.wantSerial, serials); diff != "" {
+				t.Fatalf("serial numbers mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantColor, colors); diff != "" {
+				t.Fatalf("colors mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantIMEI, resp.Data[0].Attributes.IMEI); diff != "" {
+				t.Fatalf("IMEI mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantModel, resp.Data[1].Attributes.DeviceModel); diff != "" {
+				t.Fatalf("device model mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantFields, gotFields); diff != "" {
+				t.Fatalf("sanitized fields mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_SanitizeStringsCleanPayload(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	// The escaped backslash means the color holds a literal `\u0000`, not a NUL.
+	const payload = `{"data":[{"id":"device-1","type":"orgDevices","attributes":{"serialNumber":"SERIAL-1","color":"SILVER �\\u0000","imei":["35123"]}}],"links":{"self":"https://api-business.apple.com/v1/orgDevices"}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, payload)
+	}))
+	t.Cleanup(server.Close)
+
+	var called bool
+	sanitized := testClientForServer(t, server, WithSanitizeStrings(), WithSanitizeHook(func(string, string, []string) { called = true }))
+	plain := testClientForServer(t, server)
+
+	want, err := plain.GetOrgDevices(ctx, nil)
+	if err != nil {
+		t.Fatalf("GetOrgDevices returned error: %v", err)
+	}
+	got, err := sanitized.GetOrgDevices(ctx, nil)
+	if err != nil {
+		t.Fatalf("GetOrgDevices returned error: %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("sanitized response differs for a clean payload (-want +got):\n%s", diff)
+	}
+	if called {
+		t.Fatal("sanitize hook called for a clean payload")
+	}
+}
+
+func TestStripNUL(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	type nested struct {
+		Name   string
+		Tags   [2]string
+		Extra  map[string]any
+		hidden string
+	}
+	got := &nested{
+		Name:   "a\x00b",
+		Tags:   [2]string{"\x00c", "d"},
+		Extra:  map[string]any{"k": "e\x00f", "n": 1, "list": []any{"g\x00"}},
+		hidden: "h\x00",
+	}
+	stripNUL(reflect.ValueOf(got))
+
+	want := &nested{
+		Name:   "ab",
+		Tags:   [2]string{"c", "d"},
+		Extra:  map[string]any{"k": "ef", "n": 1, "list": []any{"g"}},
+		hidden: "h\x00",
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(nested{})); diff != "" {
+		t.Fatalf("stripNUL mismatch (-want +got):\n%s", diff)
+	}
+}
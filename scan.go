@@ -0,0 +1,209 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+// partNumberScanner holds the reusable state of [appendOrgDevicePartNumbers].
+type partNumberScanner struct {
+	buf bytes.Buffer // lets dec parse the payload in place
+	dec jsontext.Decoder
+}
+
+var partNumberScannerPool = sync.Pool{
+	New: func() any { return new(partNumberScanner) },
+}
+
+// scanOrgDevices is a [PageDecoderFunc] equivalent to decodeOrgDevices that
+// reads the part numbers and next link directly from the JSON tokens instead
+// of decoding every device attribute into structs.
+func scanOrgDevices(payload []byte) ([]string, string, error) {
+	partNumbers, next, err := appendOrgDevicePartNumbers(nil, payload)
+	if err != nil {
+		return nil, "", fmt.Errorf("decode org devices response: %w", err)
+	}
+	if partNumbers == nil {
+		partNumbers = []string{}
+	}
+
+	return partNumbers, next, nil
+}
+
+// appendOrgDevicePartNumbers appends the part number of every device in an
+// orgDevices response payload to dst, using "" for a device without one, and
+// returns the extended slice and the next link.
+//
+// Only the JSON syntax and the members it reads are validated: a payload whose
+// other members have the wrong type is accepted, while decodeOrgDevices
+// rejects it.
+func appendOrgDevicePartNumbers(dst []string, payload []byte) ([]string, string, error) {
+	s := partNumberScannerPool.Get().(*partNumberScanner)
+	defer func() {
+		// Drop the references to payload before pooling.
+		s.buf = bytes.Buffer{}
+		s.dec.Reset(&s.buf)
+		partNumberScannerPool.Put(s)
+	}()
+	s.buf = *bytes.NewBuffer(payload)
+	s.dec.Reset(&s.buf)
+	dec := &s.dec
+
+	var next string
+	err := scanObject(dec, func(name []byte) (err error) {
+		switch string(name) {
+		case "data":
+			dst, err = scanDevices(dec, dst)
+		case "links":
+			err = scanObject(dec, func(name []byte) (err error) {
+				if string(name) != "next" {
+					return dec.SkipValue()
+				}
+				next, err = readString(dec)
+				return err
+			})
+		default:
+			err = dec.SkipValue()
+		}
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := dec.ReadToken(); err != io.EOF {
+		if err == nil {
+			err = errors.New("unexpected data after top-level value")
+		}
+		return nil, "", err
+	}
+
+	return dst, next, nil
+}
+
+// scanDevices appends the part numbers of the devices in the array at dec to dst.
+func scanDevices(dec *jsontext.Decoder, dst []string) ([]string, error) {
+	if dec.PeekKind() == 'n' {
+		_, err := dec.ReadToken()
+		return dst, err
+	}
+	if err := expectToken(dec, jsontext.BeginArray); err != nil {
+		return nil, err
+	}
+
+	for dec.PeekKind() != ']' {
+		var partNumber string
+		err := scanObject(dec, func(name []byte) error {
+			if string(name) != "attributes" {
+				return dec.SkipValue()
+			}
+			return scanObject(dec, func(name []byte) (err error) {
+				if string(name) != "partNumber" {
+					return dec.SkipValue()
+				}
+				partNumber, err = readString(dec)
+				return err
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+		dst = append(dst, partNumber)
+	}
+
+	if err := expectToken(dec, jsontext.EndArray); err != nil {
+		return nil, err
+	}
+
+	return dst, nil
+}
+
+// scanObject reads the object, or null, at dec and calls member for each
+// member name; member must consume the member's value. The name is only valid
+// until the next read from dec.
+func scanObject(dec *jsontext.Decoder, member func(name []byte) error) error {
+	if dec.PeekKind() == 'n' {
+		_, err := dec.ReadToken()
+		return err
+	}
+	if err := expectToken(dec, jsontext.BeginObject); err != nil {
+		return err
+	}
+
+	for dec.PeekKind() == '"' {
+		raw, err := dec.ReadValue()
+		if err != nil {
+			return err
+		}
+		name, err := unquote(raw)
+		if err != nil {
+			return err
+		}
+		if err := member(name); err != nil {
+			return err
+		}
+	}
+
+	return expectToken(dec, jsontext.EndObject)
+}
+
+// readString reads the string, or null, at dec.
+func readString(dec *jsontext.Decoder) (string, error) {
+	switch kind := dec.PeekKind(); kind {
+	case 'n':
+		_, err := dec.ReadToken()
+		return "", err
+	case '"':
+		raw, err := dec.ReadValue()
+		if err != nil {
+			return "", err
+		}
+		unquoted, err := unquote(raw)
+		return string(unquoted), err
+	default:
+		return "", fmt.Errorf("unexpected JSON kind %v, want string", kind)
+	}
+}
+
+// unquote returns the contents of the JSON string raw, sharing its memory
+// unless raw contains escape sequences.
+func unquote(raw jsontext.Value) ([]byte, error) {
+	if bytes.IndexByte(raw, '\\') < 0 {
+		return raw[1 : len(raw)-1], nil
+	}
+
+	return jsontext.AppendUnquote(nil, raw)
+}
+
+// expectToken reads the next token and checks that it is want.
+func expectToken(dec *jsontext.Decoder, want jsontext.Token) error {
+	tok, err := dec.ReadToken()
+	if err != nil {
+		return err
+	}
+	if tok.Kind() != want.Kind() {
+		return fmt.Errorf("unexpected JSON kind %v, want %v", tok.Kind(), want.Kind())
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestScanOrgDevicesParity(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		payload string
+		wantErr bool
+	}{
+		"success: generated page": {
+			payload: string(buildOrgDevicesPayload(50, "/v1/orgDevices?page=next")),
+		},
+		"success: missing and null part numbers": {
+			payload: `{"data":[{"id":"1"},{"attributes":null},{"attributes":{"partNumber":null}},{"attributes":{"serialNumber":"SN"}},{"attributes":{"partNumber":"PART-001"}}],"links":{"next":""}}`,
+		},
+		"success: escaped strings": {
+			payload: `{"data":[{"attributes":{"partNumber":"PART-001 \"A\""}}],"links":{"self":"x","next":"\/v1\/orgDevices?page=2"}}`,
+		},
+		"success: escaped member names": {
+			payload: `{"d\u0061ta":[{"attributes":{"partNumber":"PART-001"}}]}`,
+		},
+		"success: unknown members with nested values": {
+			payload: `{"meta":{"paging":{"total":2,"limit":1},"extra":[1,{"a":null}]},"data":[{"type":"orgDevices","relationships":{"assignedServer":{"links":{"self":"x"}}},"attributes":{"productFamily":"Mac","partNumber":"PART-001","imei":["1","2"]}}],"included":[]}`,
+		},
+		"success: null data and links": {
+			payload: `{"data":null,"links":null}`,
+		},
+		"success: empty object": {
+			payload: `{}`,
+		},
+		"success: empty data": {
+			payload: `{"data":[],"links":{"next":"/v1/orgDevices?page=2"}}`,
+		},
+		"error: invalid JSON": {
+			payload: `{"data":[`,
+			wantErr: true,
+		},
+		"error: trailing data": {
+			payload: `{"data":[]} {}`,
+			wantErr: true,
+		},
+		"error: data is not an array": {
+			payload: `{"data":{}}`,
+			wantErr: true,
+		},
+		"error: part number is not a string": {
+			payload: `{"data":[{"attributes":{"partNumber":1}}]}`,
+			wantErr: true,
+		},
+		"error: next link is not a string": {
+			payload: `{"data":[],"links":{"next":true}}`,
+			wantErr: true,
+		},
+		"error: duplicate member name": {
+			payload: `{"data":[],"data":[]}`,
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			want, wantNext, wantErr := decodeOrgDevices([]byte(tt.payload))
+			got, gotNext, gotErr := scanOrgDevices([]byte(tt.payload))
+			if (wantErr != nil) != tt.wantErr {
+				t.Fatalf("decodeOrgDevices error = %v, wantErr %t", wantErr, tt.wantErr)
+			}
+			if (gotErr != nil) != tt.wantErr {
+				t.Fatalf("scanOrgDevices error = %v, wantErr %t", gotErr, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Fatalf("part numbers mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(wantNext, gotNext); diff != "" {
+				t.Fatalf("next link mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/oauth2"
+)
+
+// scopeSchoolAPI is the Apple School Manager API scope.
+const scopeSchoolAPI = "school.api"
+
+// ErrInsufficientScope is returned by [Client.VerifyAccess], and by the first
+// request of a [Client] created with [WithVerifyScopeOnFirstUse], when the
+// access token was not granted the API scope. Use errors.As with
+// [*InsufficientScopeError] for the granted and required scopes.
+var ErrInsufficientScope = errors.New("insufficient access token scope")
+
+// InsufficientScopeError reports an access token without the API scope.
+type InsufficientScopeError struct {
+	// Granted is the scopes granted to the token, or nil when the token
+	// response did not list them and the scope was found missing by a probe
+	// request.
+	Granted []string
+
+	// Required is the scopes the API requires.
+	Required []string
+
+	// Err is the probe request's error, or nil when Granted was checked.
+	Err error
+}
+
+func (e *InsufficientScopeError) Error() string {
+	granted := "unknown (the token response has no scope)"
+	if e.Granted != nil {
+		granted = fmt.Sprintf("%q", e.Granted)
+	}
+	msg := fmt.Sprintf("%v: granted %s, required %q", ErrInsufficientScope, granted, e.Required)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+
+	return msg
+}
+
+// Is reports whether target is [ErrInsufficientScope].
+func (e *InsufficientScopeError) Is(target error) bool {
+	return target == ErrInsufficientScope
+}
+
+func (e *InsufficientScopeError) Unwrap() error {
+	return e.Err
+}
+
+// WithVerifyScopeOnFirstUse makes the client call [Client.VerifyAccess] before
+// its first request, and fail every request with [ErrInsufficientScope] once
+// the token is known to lack the API scope. Without it, a token with the
+// wrong scope only surfaces as a 403 Forbidden from each request.
+func WithVerifyScopeOnFirstUse() ClientOption {
+	return func(o *clientOptions) {
+		o.verifyScope = true
+	}
+}
+
+// scopeVerifier remembers the outcome of the first conclusive scope check.
+type scopeVerifier struct {
+	// done is set once err holds a conclusive outcome; err is not written
+	// after that.
+	done atomic.Bool
+	err  error
+
+	mu sync.Mutex
+	// running is closed when the check in flight ends, and nil when none is.
+	running chan struct{}
+}
+
+// verify returns the remembered outcome, or runs check. Only one check runs
+// at a time; concurrent callers wait for it, or until their ctx is done, and
+// run their own check if it was inconclusive. Transient failures of check,
+// which say nothing about the scope, are not remembered.
+func (v *scopeVerifier) verify(ctx context.Context, check func(context.Context) error) error {
+	for {
+		if v.done.Load() {
+			return v.err
+		}
+
+		v.mu.Lock()
+		if v.done.Load() {
+			v.mu.Unlock()
+			return v.err
+		}
+		if running := v.running; running != nil {
+			v.mu.Unlock()
+			select {
+			case <-ctx.Done():
+				return context.Cause(ctx)
+			case <-running:
+				continue
+			}
+		}
+		running := make(chan struct{})
+		v.running = running
+		v.mu.Unlock()
+
+		err := check(ctx)
+
+		v.mu.Lock()
+		if err == nil || errors.Is(err, ErrInsufficientScope) {
+			v.err = err
+			v.done.Store(true)
+		}
+		v.running = nil
+		close(running)
+		v.mu.Unlock()
+
+		return err
+	}
+}
+
+// verifyScopeOnFirstUse verifies the token's scope when the client was
+// created with [WithVerifyScopeOnFirstUse].
+func (c *Client) verifyScopeOnFirstUse(ctx context.Context) error {
+	if c.scopeVerifier == nil {
+		return nil
+	}
+
+	return c.scopeVerifier.verify(ctx, c.checkScope)
+}
+
+// VerifyAccess checks that the access token was granted the API scope, and
+// returns an [*InsufficientScopeError] when it was not. It reads the scopes
+// from the token response's scope field and, when the token endpoint omits
+// it, sends a cheap probe request and treats a 403 Forbidden as a missing
+// scope. Call it at startup to fail fast on misconfigured credentials.
+func (c *Client) VerifyAccess(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if c.scopeVerifier != nil {
+		return c.scopeVerifier.verify(ctx, c.checkScope)
+	}
+
+	return c.checkScope(ctx)
+}
+
+// WarmUp fetches an access token without sending an API request, so that the
+// first request does not pay for the token exchange. The token is reused by
+// later requests when the client's token source caches tokens, as the one
+// returned by [NewTokenSource] does. WarmUp returns ctx's error as soon as
+// ctx is done, leaving the fetch to finish in the background. Token endpoint
+// failures are wrapped, so [*oauth2.RetrieveError] is available via
+// [errors.As].
+func (c *Client) WarmUp(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := c.tokenSource.Token()
+		errCh <- err
+	}()
+
+	select {
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("get access token: %w", err)
+		}
+		return nil
+	}
+}
+
+// checkScope implements [Client.VerifyAccess].
+func (c *Client) checkScope(ctx context.Context) error {
+	required := []string{ScopeBusinessAPI}
+	if c.serviceFamily == ServiceFamilySchool {
+		required = []string{scopeSchoolAPI}
+	}
+
+	token, err := c.tokenSource.Token()
+	if err != nil {
+		return fmt.Errorf("get access token: %w", err)
+	}
+	if granted, ok := tokenScopes(token); ok {
+		for _, scope := range required {
+			if !slices.Contains(granted, scope) {
+				return &InsufficientScopeError{Granted: granted, Required: required}
+			}
+		}
+		return nil
+	}
+
+	return c.probeScope(ctx, required)
+}
+
+// tokenScopes returns the space-separated scopes of the token response's
+// scope field, and false when the field is absent.
+func tokenScopes(token *oauth2.Token) ([]string, bool) {
+	scope, ok := token.Extra("scope").(string)
+	if !ok {
+		return nil, false
+	}
+
+	return strings.Fields(scope), true
+}
+
+// probeScope requests a single organization device, and returns an
+// [*InsufficientScopeError] when the API answers 403 Forbidden.
+func (c *Client) probeScope(ctx context.Context, required []string) error {
+	query := url.Values{}
+	query.Set("limit", "1")
+	setFieldsQuery(query, "fields[orgDevices]", []string{"serialNumber"})
+	requestURL, err := c.buildURL(orgDevicesPath, query)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := c.requestContext(ctx, 0)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send scope probe request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	payload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read scope probe response body: %w", err)
+	}
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+
+	apiErr := decodeAPIError(resp, payload, c.errorBodyMaxBytes)
+	if resp.StatusCode == http.StatusForbidden {
+		return &InsufficientScopeError{Required: required, Err: apiErr}
+	}
+
+	return fmt.Errorf("scope probe request: %w", apiErr)
+}
@@ -0,0 +1,357 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/oauth2"
+)
+
+func TestClient_VerifyScopeOnFirstUse(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		extra         map[string]any
+		forbidden     bool
+		wantScopeErr  *InsufficientScopeError
+		wantProbe     bool
+		wantRequests  int
+		wantErrSubstr []string
+	}{
+		"success: granted scope proceeds without a probe": {
+			extra:        map[string]any{"scope": "business.api"},
+			wantRequests: 2,
+		},
+		"success: missing scope field falls back to the probe": {
+			extra:        nil,
+			wantProbe:    true,
+			wantRequests: 3,
+		},
+		"error: wrong scope fails before any request": {
+			extra:         map[string]any{"scope": "school.api openid"},
+			wantScopeErr:  &InsufficientScopeError{Granted: []string{"school.api", "openid"}, Required: []string{"business.api"}},
+			wantRequests:  0,
+			wantErrSubstr: []string{`"school.api" "openid"`, `"business.api"`},
+		},
+		"error: missing scope field and forbidden probe": {
+			extra:         nil,
+			forbidden:     true,
+			wantScopeErr:  &InsufficientScopeError{Required: []string{"business.api"}},
+			wantProbe:     true,
+			wantRequests:  1,
+			wantErrSubstr: []string{"token response has no scope", `"business.api"`},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			var requests, probes atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				if r.URL.Query().Get("fields[orgDevices]") == "serialNumber" {
+					probes.Add(1)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if tt.forbidden {
+					w.WriteHeader(http.StatusForbidden)
+					fmt.Fprint(w, `{"errors":[{"status":"403","code":"FORBIDDEN","title":"Forbidden","detail":"scope"}]}`)
+					return
+				}
+				fmt.Fprint(w, `{"data":[],"links":{"self":"https://api-business.apple.com/v1/orgDevices"}}`)
+			}))
+			t.Cleanup(server.Close)
+
+			token := (&oauth2.Token{AccessToken: "test-token"}).WithExtra(tt.extra)
+			client, err := NewClientWithBaseURL(server.Client(), oauth2.StaticTokenSource(token), server.URL, WithVerifyScopeOnFirstUse())
+			if err != nil {
+				t.Fatalf("NewClientWithBaseURL returned error: %v", err)
+			}
+
+			// The second call reuses the outcome of the first check.
+			for range 2 {
+				_, err = client.OrgDevices().List(ctx, nil)
+				if tt.wantScopeErr == nil {
+					if err != nil {
+						t.Fatalf("List returned error: %v", err)
+					}
+					continue
+				}
+
+				if !errors.Is(err, ErrInsufficientScope) {
+					t.Fatalf("List error = %v, want ErrInsufficientScope", err)
+				}
+				var scopeErr *InsufficientScopeError
+				if !errors.As(err, &scopeErr) {
+					t.Fatalf("List error = %T, want *InsufficientScopeError", err)
+				}
+				if diff := cmp.Diff(tt.wantScopeErr.Granted, scopeErr.Granted); diff != "" {
+					t.Fatalf("granted scopes mismatch (-want +got):\n%s", diff)
+				}
+				if diff := cmp.Diff(tt.wantScopeErr.Required, scopeErr.Required); diff != "" {
+					t.Fatalf("required scopes mismatch (-want +got):\n%s", diff)
+				}
+				for _, substr := range tt.wantErrSubstr {
+					if !strings.Contains(err.Error(), substr) {
+						t.Fatalf("error %q does not contain %q", err.Error(), substr)
+					}
+				}
+			}
+
+			if diff := cmp.Diff(tt.wantRequests, int(requests.Load())); diff != "" {
+				t.Fatalf("request count mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantProbe, probes.Load() == 1); diff != "" {
+				t.Fatalf("probe mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_VerifyAccess(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		status  int
+		wantErr error
+	}{
+		"success: probe accepted": {
+			status: http.StatusOK,
+		},
+		"error: probe forbidden": {
+			status:  http.StatusForbidden,
+			wantErr: ErrInsufficientScope,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, `{"data":[]}`)
+			}))
+			t.Cleanup(server.Close)
+			client := testClientForServer(t, server)
+
+			err := client.VerifyAccess(ctx)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("VerifyAccess returned error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyAccess error = %v, want %v", err, tt.wantErr)
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("VerifyAccess error = %v, want the probe's *APIError", err)
+			}
+		})
+	}
+}
+
+func TestClient_WarmUp(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		tokenStatus    int
+		wantErr        bool
+		wantStatusCode int
+	}{
+		"success: token is reused by the first request": {
+			tokenStatus: http.StatusOK,
+		},
+		"error: token endpoint rejects the assertion": {
+			tokenStatus:    http.StatusUnauthorized,
+			wantErr:        true,
+			wantStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			var tokenRequests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/token":
+					tokenRequests.Add(1)
+					if tt.tokenStatus != http.StatusOK {
+						w.WriteHeader(tt.tokenStatus)
+						fmt.Fprint(w, `{"error":"invalid_client"}`)
+						return
+					}
+					fmt.Fprint(w, `{"access_token":"abc123","token_type":"Bearer","expires_in":3600}`)
+				case "/" + orgDevicesPath:
+					if diff := cmp.Diff("Bearer abc123", r.Header.Get("Authorization")); diff != "" {
+						t.Errorf("authorization header mismatch (-want +got):\n%s", diff)
+					}
+					fmt.Fprint(w, `{"data":[]}`)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			t.Cleanup(server.Close)
+
+			source, err := NewTokenSource(ctx, server.Client(), "client-id", "assertion", ScopeBusinessAPI, WithTokenURL(server.URL+"/token"), WithTokenMaxRetries(0))
+			if err != nil {
+				t.Fatalf("NewTokenSource returned error: %v", err)
+			}
+			client, err := NewClientWithBaseURL(server.Client(), source, server.URL)
+			if err != nil {
+				t.Fatalf("NewClientWithBaseURL returned error: %v", err)
+			}
+
+			err = client.WarmUp(ctx)
+			if tt.wantErr {
+				var retrieveErr *oauth2.RetrieveError
+				if !errors.As(err, &retrieveErr) {
+					t.Fatalf("expected *oauth2.RetrieveError, got %v", err)
+				}
+				if diff := cmp.Diff(tt.wantStatusCode, retrieveErr.Response.StatusCode); diff != "" {
+					t.Fatalf("status code mismatch (-want +got):\n%s", diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WarmUp returned error: %v", err)
+			}
+			if diff := cmp.Diff(int32(1), tokenRequests.Load()); diff != "" {
+				t.Fatalf("token requests after WarmUp mismatch (-want +got):\n%s", diff)
+			}
+
+			if _, err := client.OrgDevices().List(ctx, &GetOrgDevicesOptions{Limit: 1}); err != nil {
+				t.Fatalf("List returned error: %v", err)
+			}
+			if diff := cmp.Diff(int32(1), tokenRequests.Load()); diff != "" {
+				t.Fatalf("token requests after List mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_WarmUpCanceled(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"abc123","token_type":"Bearer","expires_in":3600}`)
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	source, err := NewTokenSource(ctx, server.Client(), "client-id", "assertion", ScopeBusinessAPI, WithTokenURL(server.URL+"/token"))
+	if err != nil {
+		t.Fatalf("NewTokenSource returned error: %v", err)
+	}
+	client, err := NewClientWithBaseURL(server.Client(), source, server.URL)
+	if err != nil {
+		t.Fatalf("NewClientWithBaseURL returned error: %v", err)
+	}
+
+	warmCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	if err := client.WarmUp(warmCtx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestScopeVerifier_Verify(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	var v scopeVerifier
+	var checks atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	check := func(context.Context) error {
+		if checks.Add(1) == 1 {
+			close(started)
+			<-release
+		}
+		return nil
+	}
+
+	firstErr := make(chan error, 1)
+	go func() { firstErr <- v.verify(ctx, check) }()
+	<-started
+
+	// A caller waiting for the check in flight gives up when its ctx is done.
+	waitCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := v.verify(waitCtx, check); !errors.Is(err, context.Canceled) {
+		t.Fatalf("waiting verify error = %v, want context.Canceled", err)
+	}
+
+	close(release)
+	if err := <-firstErr; err != nil {
+		t.Fatalf("first verify returned error: %v", err)
+	}
+	if err := v.verify(ctx, check); err != nil {
+		t.Fatalf("verify after success returned error: %v", err)
+	}
+	if diff := cmp.Diff(int32(1), checks.Load()); diff != "" {
+		t.Fatalf("checks mismatch (-want +got):\n%s", diff)
+	}
+}
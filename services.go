@@ -0,0 +1,659 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+// The methods of a [Client] are grouped into services by the resource they
+// act on, in the style of github.com/google/go-github. A service only refers
+// to its client, so it shares the client's transport and options and, like
+// the client, is safe for concurrent use.
+
+// OrgDevicesService provides the organization device endpoints.
+// Obtain one with [Client.OrgDevices].
+type OrgDevicesService struct {
+	client *Client
+}
+
+// MDMServersService provides the device management service endpoints.
+// Obtain one with [Client.MDMServers].
+type MDMServersService struct {
+	client *Client
+}
+
+// ActivitiesService provides the org-device activity endpoints.
+// Obtain one with [Client.Activities].
+type ActivitiesService struct {
+	client *Client
+}
+
+// OrgDevices returns the organization device endpoints of c.
+func (c *Client) OrgDevices() *OrgDevicesService {
+	return &OrgDevicesService{client: c}
+}
+
+// MDMServers returns the device management service endpoints of c.
+func (c *Client) MDMServers() *MDMServersService {
+	return &MDMServersService{client: c}
+}
+
+// Activities returns the org-device activity endpoints of c.
+func (c *Client) Activities() *ActivitiesService {
+	return &ActivitiesService{client: c}
+}
+
+// List gets a page of organization devices.
+func (s *OrgDevicesService) List(ctx context.Context, options *GetOrgDevicesOptions, opts ...CallOption) (*OrgDevicesResponse, error) {
+	c := s.client
+	var fields []string
+	var limit int
+	if options != nil {
+		fields = options.Fields
+		limit = options.Limit
+	}
+
+	query, err := buildFieldsAndLimitQuery("fields[orgDevices]", fields, limit)
+	if err != nil {
+		return nil, err
+	}
+	if err := setOrgDevicesFilterQuery(query, options); err != nil {
+		return nil, err
+	}
+
+	var response OrgDevicesResponse
+	if err := c.doJSONRequest(ctx, http.MethodGet, orgDevicesPath, query, opts, nil, &response, http.StatusOK); err != nil {
+		return nil, err
+	}
+	if c.stableOrdering {
+		SortOrgDevices(response.Data)
+	}
+
+	return &response, nil
+}
+
+// ListRaw is like [OrgDevicesService.List] but also returns the response body
+// exactly as the API sent it.
+func (s *OrgDevicesService) ListRaw(ctx context.Context, options *GetOrgDevicesOptions, opts ...CallOption) (*OrgDevicesResponse, jsontext.Value, error) {
+	c := s.client
+	var fields []string
+	var limit int
+	if options != nil {
+		fields = options.Fields
+		limit = options.Limit
+	}
+
+	query, err := buildFieldsAndLimitQuery("fields[orgDevices]", fields, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := setOrgDevicesFilterQuery(query, options); err != nil {
+		return nil, nil, err
+	}
+
+	var response OrgDevicesResponse
+	raw, err := c.doJSONRequestRaw(ctx, http.MethodGet, orgDevicesPath, query, opts, &response)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.stableOrdering {
+		SortOrgDevices(response.Data)
+	}
+
+	return &response, raw, nil
+}
+
+// Get gets information for a single organization device.
+func (s *OrgDevicesService) Get(ctx context.Context, orgDeviceID string, options *GetOrgDeviceOptions, opts ...CallOption) (*OrgDeviceResponse, error) {
+	c := s.client
+	escapedID, err := validateAndEscapeID("org device ID", orgDeviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	if options != nil {
+		setFieldsQuery(query, "fields[orgDevices]", options.Fields)
+	}
+
+	var response OrgDeviceResponse
+	if err := c.doJSONRequest(ctx, http.MethodGet, joinPath(orgDevicesPath, escapedID), query, opts, nil, &response, http.StatusOK); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// GetRaw is like [OrgDevicesService.Get] but also returns the response body
+// exactly as the API sent it, for example for audit logs that must keep
+// fields this package does not model.
+func (s *OrgDevicesService) GetRaw(ctx context.Context, orgDeviceID string, options *GetOrgDeviceOptions, opts ...CallOption) (*OrgDeviceResponse, jsontext.Value, error) {
+	c := s.client
+	escapedID, err := validateAndEscapeID("org device ID", orgDeviceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query := url.Values{}
+	if options != nil {
+		setFieldsQuery(query, "fields[orgDevices]", options.Fields)
+	}
+
+	var response OrgDeviceResponse
+	raw, err := c.doJSONRequestRaw(ctx, http.MethodGet, joinPath(orgDevicesPath, escapedID), query, opts, &response)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &response, raw, nil
+}
+
+// GetConditional gets a single organization device unless it is unchanged
+// since the response that returned etag. It sends etag in an If-None-Match
+// header when non-empty. When the server responds 304 Not
+// Modified it returns nil, "", false and a nil error, and the caller keeps its
+// cached copy. Otherwise it returns the device, the ETag response header, and
+// true.
+func (s *OrgDevicesService) GetConditional(ctx context.Context, orgDeviceID, etag string, options *GetOrgDeviceOptions, opts ...CallOption) (*OrgDeviceResponse, string, bool, error) {
+	c := s.client
+	escapedID, err := validateAndEscapeID("org device ID", orgDeviceID)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	query := url.Values{}
+	if options != nil {
+		setFieldsQuery(query, "fields[orgDevices]", options.Fields)
+	}
+
+	requestURL, err := c.buildURL(joinPath(orgDevicesPath, escapedID), query)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	ex := newCallExchange(opts)
+	if etag != "" {
+		WithHeader("If-None-Match", etag)(ex)
+	}
+
+	var response OrgDeviceResponse
+	if err := c.doJSONExchange(ctx, http.MethodGet, requestURL, ex, nil, &response, http.StatusOK, http.StatusNotModified); err != nil {
+		return nil, "", false, err
+	}
+	if ex.statusCode == http.StatusNotModified {
+		return nil, "", false, nil
+	}
+
+	return &response, ex.respHeader.Get("ETag"), true, nil
+}
+
+// GetReader gets a single organization device and returns the raw JSON
+// response body without buffering it, for example to feed a streaming JSON
+// parser. The caller must close the returned reader; closing it early
+// discards the rest of a small body so the connection can be reused. A
+// non-2xx response is returned as an [*APIError]. The request is not retried.
+func (s *OrgDevicesService) GetReader(ctx context.Context, orgDeviceID string) (io.ReadCloser, error) {
+	c := s.client
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	escapedID, err := validateAndEscapeID("org device ID", orgDeviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	requestURL, err := c.buildURL(joinPath(orgDevicesPath, escapedID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.verifyScopeOnFirstUse(ctx); err != nil {
+		return nil, err
+	}
+	reqCtx, cancel := c.requestContext(ctx, 0)
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, requestURL, http.NoBody)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+
+	trace := attemptTraceFrom(ctx)
+	var start time.Time
+	if trace != nil {
+		start = time.Now()
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		cancel()
+		err = fmt.Errorf("send request: %w", err)
+		if trace != nil {
+			trace.record(http.MethodGet, requestURL, start, nil, err, "")
+		}
+		return nil, err
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		defer cancel()
+		defer resp.Body.Close()
+
+		payload, err := io.ReadAll(resp.Body)
+		if err != nil {
+			err = fmt.Errorf("read response body: %w", err)
+		} else {
+			apiErr := decodeAPIError(resp, payload, c.errorBodyMaxBytes)
+			apiErr.Hint = c.serviceFamilyHint(resp.StatusCode)
+			err = apiErr
+		}
+		if trace != nil {
+			trace.record(http.MethodGet, requestURL, start, resp, err, "")
+		}
+		return nil, err
+	}
+	if trace != nil {
+		// The body is streamed to the caller, so the duration covers the
+		// response headers only.
+		trace.record(http.MethodGet, requestURL, start, resp, nil, "")
+	}
+
+	return &drainingBody{ReadCloser: resp.Body, cancel: cancel}, nil
+}
+
+// maxDrainBytes and maxDrainTime bound the unread data a [drainingBody]
+// discards on close. A body with more left, or one that stalls, costs its
+// connection instead.
+const (
+	maxDrainBytes = 256 << 10
+	maxDrainTime  = 50 * time.Millisecond
+)
+
+// drainingBody is a response body that discards what the caller left unread
+// before closing it, so that closing a partly read body lets the transport
+// reuse the connection.
+type drainingBody struct {
+	io.ReadCloser
+
+	// cancel, if non-nil, releases the request's context after closing.
+	cancel context.CancelFunc
+}
+
+func (b *drainingBody) Close() error {
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+		io.CopyN(io.Discard, b.ReadCloser, maxDrainBytes)
+	}()
+	select {
+	case <-drained:
+	case <-time.After(maxDrainTime):
+	}
+
+	err := b.ReadCloser.Close()
+	if b.cancel != nil {
+		b.cancel()
+	}
+
+	return err
+}
+
+// AppleCareCoverage gets AppleCare coverage information for a single organization device.
+func (s *OrgDevicesService) AppleCareCoverage(ctx context.Context, orgDeviceID string, options *GetOrgDeviceAppleCareCoverageOptions, opts ...CallOption) (*AppleCareCoverageResponse, error) {
+	c := s.client
+	escapedID, err := validateAndEscapeID("org device ID", orgDeviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []string
+	var limit int
+	if options != nil {
+		fields = options.Fields
+		limit = options.Limit
+	}
+
+	query, err := buildFieldsAndLimitQuery("fields[appleCareCoverage]", fields, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var response AppleCareCoverageResponse
+	path := joinPath(orgDevicesPath, escapedID, "appleCareCoverage")
+	if err := c.doJSONRequest(ctx, http.MethodGet, path, query, opts, nil, &response, http.StatusOK); err != nil {
+		return nil, err
+	}
+	if c.stableOrdering {
+		SortAppleCareCoverages(response.Data)
+	}
+
+	return &response, nil
+}
+
+// AssignedServerLinkage gets assigned device-management service ID linkage for a device.
+func (s *OrgDevicesService) AssignedServerLinkage(ctx context.Context, orgDeviceID string, opts ...CallOption) (*OrgDeviceAssignedServerLinkageResponse, error) {
+	c := s.client
+	escapedID, err := validateAndEscapeID("org device ID", orgDeviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var response OrgDeviceAssignedServerLinkageResponse
+	path := joinPath(orgDevicesPath, escapedID, "relationships", "assignedServer")
+	if err := c.doJSONRequest(ctx, http.MethodGet, path, nil, opts, nil, &response, http.StatusOK); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// AssignedServer gets assigned device-management service information for a device.
+func (s *OrgDevicesService) AssignedServer(ctx context.Context, orgDeviceID string, options *GetOrgDeviceAssignedServerOptions, opts ...CallOption) (*MDMServerResponse, error) {
+	c := s.client
+	escapedID, err := validateAndEscapeID("org device ID", orgDeviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	if options != nil {
+		setFieldsQuery(query, "fields[mdmServers]", options.Fields)
+	}
+
+	var response MDMServerResponse
+	path := joinPath(orgDevicesPath, escapedID, "assignedServer")
+	if err := c.doJSONRequest(ctx, http.MethodGet, path, query, opts, nil, &response, http.StatusOK); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// List gets a page of device management services.
+func (s *MDMServersService) List(ctx context.Context, options *GetMDMServersOptions, opts ...CallOption) (*MDMServersResponse, error) {
+	c := s.client
+	var fields []string
+	var limit int
+	if options != nil {
+		fields = options.Fields
+		limit = options.Limit
+	}
+
+	query, err := buildFieldsAndLimitQuery("fields[mdmServers]", fields, limit)
+	if err != nil {
+		return nil, err
+	}
+	setMDMServersFilterQuery(query, options)
+
+	var response MDMServersResponse
+	if err := c.doJSONRequest(ctx, http.MethodGet, mdmServersPath, query, opts, nil, &response, http.StatusOK); err != nil {
+		return nil, err
+	}
+	if c.stableOrdering {
+		SortMDMServers(response.Data)
+	}
+
+	return &response, nil
+}
+
+// DeviceLinkages gets a page of the org-device IDs linked to a device management service.
+func (s *MDMServersService) DeviceLinkages(ctx context.Context, mdmServerID string, options *GetMDMServerDeviceLinkagesOptions, opts ...CallOption) (*MDMServerDevicesLinkagesResponse, error) {
+	c := s.client
+	escapedID, err := validateAndEscapeID("mdm server ID", mdmServerID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	if options != nil {
+		if options.Cursor != "" && options.Sort != "" {
+			return nil, &OptionConflictError{
+				Options: []string{"Cursor", "Sort"},
+				Reason:  "a cursor keeps the order of the request that returned it",
+			}
+		}
+		if err := setLimitQuery(query, options.Limit); err != nil {
+			return nil, err
+		}
+		if err := setSortQuery(query, options.Sort); err != nil {
+			return nil, err
+		}
+		if options.Cursor != "" {
+			query.Set("cursor", options.Cursor)
+		}
+	}
+
+	var response MDMServerDevicesLinkagesResponse
+	path := joinPath(mdmServersPath, escapedID, "relationships", "devices")
+	if err := c.doJSONRequest(ctx, http.MethodGet, path, query, opts, nil, &response, http.StatusOK); err != nil {
+		return nil, err
+	}
+	// An explicit sort asks for the API's order.
+	if c.stableOrdering && (options == nil || options.Sort == "") {
+		SortMDMServerDeviceLinkages(response.Data)
+	}
+
+	return &response, nil
+}
+
+// ErrMDMServerNotFound is returned by [MDMServersService.Get] when no MDM
+// server has the requested ID.
+var ErrMDMServerNotFound = errors.New("mdm server not found")
+
+// Get gets a single device management service. The API has no endpoint for a
+// single MDM server, so the server list is paged through until the server is
+// found; it returns an error wrapping [ErrMDMServerNotFound] when none matches.
+func (s *MDMServersService) Get(ctx context.Context, mdmServerID string) (*MDMServer, error) {
+	c := s.client
+	if strings.TrimSpace(mdmServerID) == "" {
+		return nil, errMissingID("mdm server ID")
+	}
+
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(maxPageLimit))
+	for page, err := range crawlPages(ctx, c, mdmServersPath, query, func(r *MDMServersResponse) string { return r.Links.Next }) {
+		if err != nil {
+			return nil, err
+		}
+		for i := range page.Data {
+			if page.Data[i].ID == mdmServerID {
+				return &page.Data[i], nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrMDMServerNotFound, mdmServerID)
+}
+
+// Devices yields the org-device linkages of every device assigned to the
+// device management service, following pagination with the largest page size.
+func (s *MDMServersService) Devices(ctx context.Context, mdmServerID string) iter.Seq2[MDMServerDevicesLinkageData, error] {
+	c := s.client
+	return func(yield func(MDMServerDevicesLinkageData, error) bool) {
+		escapedID, err := validateAndEscapeID("mdm server ID", mdmServerID)
+		if err != nil {
+			yield(MDMServerDevicesLinkageData{}, err)
+			return
+		}
+		query := url.Values{}
+		query.Set("limit", strconv.Itoa(maxPageLimit))
+
+		path := joinPath(mdmServersPath, escapedID, "relationships", "devices")
+		for page, err := range crawlPages(ctx, c, path, query, func(r *MDMServerDevicesLinkagesResponse) string { return r.Links.Next }) {
+			if err != nil {
+				yield(MDMServerDevicesLinkageData{}, err)
+				return
+			}
+			for _, linkage := range page.Data {
+				if !yield(linkage, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ContinueDeviceLinkages yields the org-device linkages of an MDM server
+// relationship that follow the ones inlined in rel, paging through the rest
+// with the client's pagination machinery. It yields nothing when
+// [MDMServerRelationshipsDevices.HasMore] is false.
+//
+// The crawl starts from the relationship's self link, or its related link when
+// there is no self link. A relative link is joined onto the client's base URL
+// like a request path; an absolute one must stay within it. With a next
+// cursor in rel's meta.paging, the crawl resumes from the cursor; otherwise it
+// starts over and skips the inlined linkages, which assumes the server returns
+// them in the same order.
+func (c *Client) ContinueDeviceLinkages(ctx context.Context, rel *MDMServerRelationshipsDevices) iter.Seq2[MDMServerRelationshipsDevicesData, error] {
+	return func(yield func(MDMServerRelationshipsDevicesData, error) bool) {
+		if !rel.HasMore() {
+			return
+		}
+
+		link := rel.Links.Self
+		if link == "" {
+			link = rel.Links.Related
+		}
+		firstURL, err := c.resolveRelationshipLink(link)
+		if err != nil {
+			yield(MDMServerRelationshipsDevicesData{}, err)
+			return
+		}
+
+		skip := 0
+		query := firstURL.Query()
+		if cursor := rel.Meta.Paging.NextCursor; cursor != "" {
+			query.Set("cursor", cursor)
+		} else {
+			skip = len(rel.Data)
+		}
+		if !query.Has("limit") {
+			query.Set("limit", strconv.Itoa(maxPageLimit))
+		}
+		firstURL.RawQuery = query.Encode()
+
+		path := strings.TrimPrefix(firstURL.Path, c.baseURL.Path)
+		for page, err := range crawlPagesFrom(ctx, c, path, firstURL.String(), func(r *MDMServerDevicesLinkagesResponse) string { return r.Links.Next }) {
+			if err != nil {
+				yield(MDMServerRelationshipsDevicesData{}, err)
+				return
+			}
+			for _, linkage := range page.Data {
+				if skip > 0 {
+					skip--
+					continue
+				}
+				if !yield(MDMServerRelationshipsDevicesData{ID: linkage.ID, Type: linkage.Type}, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// resolveRelationshipLink resolves a relationship link from a response: a
+// relative link is joined onto the base URL like a request path, and the
+// result must stay within the base URL.
+func (c *Client) resolveRelationshipLink(link string) (*url.URL, error) {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return nil, fmt.Errorf("parse relationship link: %w", err)
+	}
+
+	resolved := parsed
+	if !parsed.IsAbs() {
+		resolved = c.baseURL.JoinPath(parsed.Path)
+		resolved.RawQuery = parsed.RawQuery
+	}
+	resolved.Fragment = ""
+	resolved.RawFragment = ""
+	if !withinBaseURL(c.baseURL, resolved) {
+		return nil, fmt.Errorf("relationship link %q escapes base URL %q", link, c.baseURL.String())
+	}
+
+	return resolved, nil
+}
+
+// Create creates an org-device activity that assigns or unassigns devices.
+// The request is checked with [OrgDeviceActivityCreateRequest.Validate] before it is sent.
+// A request with too many devices fails with a [*TooManyDevicesError], both
+// when rejected locally and when the API rejects it for its device count.
+func (s *ActivitiesService) Create(ctx context.Context, request OrgDeviceActivityCreateRequest, opts ...CallOption) (*OrgDeviceActivityResponse, error) {
+	c := s.client
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid org device activity request: %w", err)
+	}
+
+	var response OrgDeviceActivityResponse
+	if err := c.doJSONRequest(ctx, http.MethodPost, orgDeviceActivitiesURL, nil, opts, request, &response, http.StatusCreated); err != nil {
+		if tooMany, ok := asTooManyDevices(err, len(request.Data.Relationships.Devices.Data)); ok {
+			return nil, tooMany
+		}
+		return nil, err
+	}
+	response.CorrelationID = request.CorrelationID
+
+	return &response, nil
+}
+
+// Get gets organization device activity information.
+func (s *ActivitiesService) Get(ctx context.Context, orgDeviceActivityID string, options *GetOrgDeviceActivityOptions, opts ...CallOption) (*OrgDeviceActivityResponse, error) {
+	c := s.client
+	escapedID, err := validateAndEscapeID("org device activity ID", orgDeviceActivityID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	if options != nil {
+		setFieldsQuery(query, "fields[orgDeviceActivities]", options.Fields)
+		if err := setIncludeQuery(query, orgDeviceActivityIncludes, options.Include); err != nil {
+			return nil, err
+		}
+	}
+
+	var response OrgDeviceActivityResponse
+	if err := c.doJSONRequest(ctx, http.MethodGet, joinPath(orgDeviceActivitiesURL, escapedID), query, opts, nil, &response, http.StatusOK); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// Wait polls the org-device activity until it finishes or ctx is done, and
+// returns the final activity. A COMPLETED activity returns a nil error; a
+// FAILED or STOPPED activity is returned together with an error. A nil
+// options uses the defaults described on [WaitOptions].
+func (s *ActivitiesService) Wait(ctx context.Context, orgDeviceActivityID string, options *WaitOptions) (*OrgDeviceActivityResponse, error) {
+	w := &activityWaiter{
+		client: s.client,
+		sleep:  sleepContext,
+		jitter: fullJitter,
+	}
+	return w.wait(ctx, orgDeviceActivityID, options)
+}
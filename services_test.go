@@ -0,0 +1,663 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/oauth2"
+)
+
+// serviceRequest is the shape of a request seen by a test server.
+type serviceRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Body   string
+	Header string
+}
+
+// newServiceRecordingServer answers every request with a minimal JSON:API document
+// and records the shape of each request.
+func newServiceRecordingServer(t *testing.T) (*httptest.Server, func() []serviceRequest) {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		requests []serviceRequest
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, serviceRequest{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.RawQuery,
+			Body:   string(body),
+			Header: r.Header.Get("X-Call"),
+		})
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"data":{"id":"activity-1","type":"orgDeviceActivities","attributes":{"status":"COMPLETED"}}}`)
+		case r.URL.Path == "/v1/orgDeviceActivities/activity-1":
+			fmt.Fprint(w, `{"data":{"id":"activity-1","type":"orgDeviceActivities","attributes":{"status":"COMPLETED"}}}`)
+		case r.URL.Path == "/v1/orgDevices/device-1" || r.URL.Path == "/v1/orgDevices/device-1/assignedServer":
+			fmt.Fprint(w, `{"data":{"id":"device-1","type":"orgDevices"}}`)
+		case r.URL.Path == "/v1/orgDevices/device-1/relationships/assignedServer":
+			fmt.Fprint(w, `{"data":{"id":"mdm-1","type":"mdmServers"}}`)
+		default:
+			fmt.Fprint(w, `{"data":[]}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []serviceRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]serviceRequest(nil), requests...)
+	}
+}
+
+func TestServices_WrapperParity(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	call := WithHeader("X-Call", "1")
+	activity := newOrgDeviceActivityCreateRequest(OrgDeviceActivityTypeAssignDevices, "mdm-1", "device-1")
+
+	tests := map[string]struct {
+		flat    func(context.Context, *Client) error
+		service func(context.Context, *Client) error
+	}{
+		"success: org devices list": {
+			flat: func(ctx context.Context, c *Client) error {
+				_, err := c.GetOrgDevices(ctx, &GetOrgDevicesOptions{Fields: []string{"serialNumber"}, Limit: 5, Color: "RED"}, call)
+				return err
+			},
+			service: func(ctx context.Context, c *Client) error {
+				_, err := c.OrgDevices().List(ctx, &GetOrgDevicesOptions{Fields: []string{"serialNumber"}, Limit: 5, Color: "RED"}, call)
+				return err
+			},
+		},
+		"success: org devices get": {
+			flat: func(ctx context.Context, c *Client) error {
+				_, err := c.GetOrgDevice(ctx, "device-1", &GetOrgDeviceOptions{Fields: []string{"partNumber"}}, call)
+				return err
+			},
+			service: func(ctx context.Context, c *Client) error {
+				_, err := c.OrgDevices().Get(ctx, "device-1", &GetOrgDeviceOptions{Fields: []string{"partNumber"}}, call)
+				return err
+			},
+		},
+		"success: org devices get conditional": {
+			flat: func(ctx context.Context, c *Client) error {
+				_, _, _, err := c.GetOrgDeviceConditional(ctx, "device-1", `"v1"`, nil, call)
+				return err
+			},
+			service: func(ctx context.Context, c *Client) error {
+				_, _, _, err := c.OrgDevices().GetConditional(ctx, "device-1", `"v1"`, nil, call)
+				return err
+			},
+		},
+		"success: org devices get reader": {
+			flat: func(ctx context.Context, c *Client) error {
+				rc, err := c.GetOrgDeviceReader(ctx, "device-1")
+				if err != nil {
+					return err
+				}
+				return rc.Close()
+			},
+			service: func(ctx context.Context, c *Client) error {
+				rc, err := c.OrgDevices().GetReader(ctx, "device-1")
+				if err != nil {
+					return err
+				}
+				return rc.Close()
+			},
+		},
+		"success: org devices apple care coverage": {
+			flat: func(ctx context.Context, c *Client) error {
+				_, err := c.GetOrgDeviceAppleCareCoverage(ctx, "device-1", &GetOrgDeviceAppleCareCoverageOptions{Limit: 2}, call)
+				return err
+			},
+			service: func(ctx context.Context, c *Client) error {
+				_, err := c.OrgDevices().AppleCareCoverage(ctx, "device-1", &GetOrgDeviceAppleCareCoverageOptions{Limit: 2}, call)
+				return err
+			},
+		},
+		"success: org devices assigned server linkage": {
+			flat: func(ctx context.Context, c *Client) error {
+				_, err := c.GetOrgDeviceAssignedServerLinkage(ctx, "device-1", call)
+				return err
+			},
+			service: func(ctx context.Context, c *Client) error {
+				_, err := c.OrgDevices().AssignedServerLinkage(ctx, "device-1", call)
+				return err
+			},
+		},
+		"success: org devices assigned server": {
+			flat: func(ctx context.Context, c *Client) error {
+				_, err := c.GetOrgDeviceAssignedServer(ctx, "device-1", &GetOrgDeviceAssignedServerOptions{Fields: []string{"serverName"}}, call)
+				return err
+			},
+			service: func(ctx context.Context, c *Client) error {
+				_, err := c.OrgDevices().AssignedServer(ctx, "device-1", &GetOrgDeviceAssignedServerOptions{Fields: []string{"serverName"}}, call)
+				return err
+			},
+		},
+		"success: mdm servers list": {
+			flat: func(ctx context.Context, c *Client) error {
+				_, err := c.GetMDMServers(ctx, &GetMDMServersOptions{Limit: 10}, call)
+				return err
+			},
+			service: func(ctx context.Context, c *Client) error {
+				_, err := c.MDMServers().List(ctx, &GetMDMServersOptions{Limit: 10}, call)
+				return err
+			},
+		},
+		"success: mdm servers device linkages": {
+			flat: func(ctx context.Context, c *Client) error {
+				_, err := c.GetMDMServerDeviceLinkages(ctx, "mdm-1", &GetMDMServerDeviceLinkagesOptions{Limit: 3, Sort: "-id"}, call)
+				return err
+			},
+			service: func(ctx context.Context, c *Client) error {
+				_, err := c.MDMServers().DeviceLinkages(ctx, "mdm-1", &GetMDMServerDeviceLinkagesOptions{Limit: 3, Sort: "-id"}, call)
+				return err
+			},
+		},
+		"success: activities create": {
+			flat: func(ctx context.Context, c *Client) error {
+				_, err := c.CreateOrgDeviceActivity(ctx, activity, call)
+				return err
+			},
+			service: func(ctx context.Context, c *Client) error {
+				_, err := c.Activities().Create(ctx, activity, call)
+				return err
+			},
+		},
+		"success: activities get": {
+			flat: func(ctx context.Context, c *Client) error {
+				_, err := c.GetOrgDeviceActivity(ctx, "activity-1", &GetOrgDeviceActivityOptions{Include: []string{"devices"}}, call)
+				return err
+			},
+			service: func(ctx context.Context, c *Client) error {
+				_, err := c.Activities().Get(ctx, "activity-1", &GetOrgDeviceActivityOptions{Include: []string{"devices"}}, call)
+				return err
+			},
+		},
+		"success: activities wait": {
+			flat: func(ctx context.Context, c *Client) error {
+				_, err := c.WaitForOrgDeviceActivity(ctx, "activity-1", nil)
+				return err
+			},
+			service: func(ctx context.Context, c *Client) error {
+				_, err := c.Activities().Wait(ctx, "activity-1", nil)
+				return err
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server, requests := newServiceRecordingServer(t)
+			client := testClientForServer(t, server)
+
+			if err := tt.flat(ctx, client); err != nil {
+				t.Fatalf("flat method returned error: %v", err)
+			}
+			flat := requests()
+			if err := tt.service(ctx, client); err != nil {
+				t.Fatalf("service method returned error: %v", err)
+			}
+			service := requests()[len(flat):]
+
+			if len(flat) == 0 {
+				t.Fatal("flat method sent no request")
+			}
+			if diff := cmp.Diff(flat, service); diff != "" {
+				t.Fatalf("request shape mismatch (-flat +service):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMDMServersService_Get(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	pages := map[string]string{
+		"":  `{"data":[{"id":"mdm-1","type":"mdmServers","attributes":{"serverName":"One"}}],"links":{"next":"/v1/mdmServers?page=2"}}`,
+		"2": `{"data":[{"id":"mdm-2","type":"mdmServers","attributes":{"serverName":"Two"}}]}`,
+	}
+
+	tests := map[string]struct {
+		id           string
+		wantName     string
+		wantNotFound bool
+		wantErr      bool
+	}{
+		"success: server on first page": {
+			id:       "mdm-1",
+			wantName: "One",
+		},
+		"success: server on later page": {
+			id:       "mdm-2",
+			wantName: "Two",
+		},
+		"error: unknown server": {
+			id:           "mdm-3",
+			wantNotFound: true,
+			wantErr:      true,
+		},
+		"error: empty id": {
+			id:      " ",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, ok := pages[r.URL.Query().Get("page")]
+				if r.URL.Path != "/v1/mdmServers" || !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, body)
+			}))
+			t.Cleanup(server.Close)
+			client := testClientForServer(t, server)
+
+			got, err := client.MDMServers().Get(ctx, tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Get error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantNotFound, errors.Is(err, ErrMDMServerNotFound)); diff != "" {
+				t.Fatalf("not found mismatch (-want +got):\n%s", diff)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.wantName, got.ServerName()); diff != "" {
+				t.Fatalf("server name mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMDMServersService_Devices(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		id        string
+		stopAfter int
+		wantIDs   []string
+		wantErr   bool
+	}{
+		"success: all pages": {
+			id:      "mdm-1",
+			wantIDs: []string{"device-1", "device-2", "device-3"},
+		},
+		"success: early break": {
+			id:        "mdm-1",
+			stopAfter: 1,
+			wantIDs:   []string{"device-1"},
+		},
+		"error: empty id": {
+			id:      "",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Query().Get("page") {
+				case "":
+					if got := r.URL.Query().Get("limit"); got != "1000" {
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+					fmt.Fprint(w, `{"data":[{"id":"device-1","type":"orgDevices"},{"id":"device-2","type":"orgDevices"}],"links":{"next":"/v1/mdmServers/mdm-1/relationships/devices?page=2"}}`)
+				case "2":
+					fmt.Fprint(w, `{"data":[{"id":"device-3","type":"orgDevices"}]}`)
+				}
+			}))
+			t.Cleanup(server.Close)
+			client := testClientForServer(t, server)
+
+			var gotIDs []string
+			var gotErr error
+			for linkage, err := range client.MDMServers().Devices(ctx, tt.id) {
+				if err != nil {
+					gotErr = err
+					break
+				}
+				gotIDs = append(gotIDs, linkage.ID)
+				if len(gotIDs) == tt.stopAfter {
+					break
+				}
+			}
+			if (gotErr != nil) != tt.wantErr {
+				t.Fatalf("Devices error = %v, wantErr %t", gotErr, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantIDs, gotIDs); diff != "" {
+				t.Fatalf("device IDs mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_ContinueDeviceLinkages(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	const linkagesPath = "/v1/mdmServers/server-1/relationships/devices"
+	linkages := func(next string, ids ...string) string {
+		data := make([]string, len(ids))
+		for i, id := range ids {
+			data[i] = fmt.Sprintf(`{"id":%q,"type":"orgDevices"}`, id)
+		}
+		return fmt.Sprintf(`{"data":[%s],"links":{"self":%q,"next":%q}}`, strings.Join(data, ","), linkagesPath, next)
+	}
+	inline := []MDMServerRelationshipsDevicesData{{ID: "device-1", Type: "orgDevices"}, {ID: "device-2", Type: "orgDevices"}}
+
+	tests := map[string]struct {
+		basePath     string
+		rel          *MDMServerRelationshipsDevices
+		pages        map[string]string
+		wantHasMore  bool
+		wantIDs      []string
+		wantRequests []string
+		wantErr      string
+	}{
+		"success: inline data plus two more pages": {
+			rel: &MDMServerRelationshipsDevices{
+				Data:  inline,
+				Links: &RelationshipLinks{Self: linkagesPath},
+				Meta:  &PagingInformation{Paging: PagingInformationPaging{Limit: 2, NextCursor: "cursor-1", Total: 6}},
+			},
+			pages: map[string]string{
+				linkagesPath + "?cursor=cursor-1&limit=1000": linkages(linkagesPath+"?cursor=cursor-2&limit=1000", "device-3", "device-4"),
+				linkagesPath + "?cursor=cursor-2&limit=1000": linkages("", "device-5", "device-6"),
+			},
+			wantHasMore:  true,
+			wantIDs:      []string{"device-3", "device-4", "device-5", "device-6"},
+			wantRequests: []string{linkagesPath + "?cursor=cursor-1&limit=1000", linkagesPath + "?cursor=cursor-2&limit=1000"},
+		},
+		"success: without a cursor the inline data is skipped": {
+			rel: &MDMServerRelationshipsDevices{
+				Data:  inline,
+				Links: &RelationshipLinks{Self: linkagesPath},
+				Meta:  &PagingInformation{Paging: PagingInformationPaging{Limit: 2, Total: 4}},
+			},
+			pages: map[string]string{
+				linkagesPath + "?limit=1000":          linkages(linkagesPath+"?cursor=c&limit=1000", "device-1", "device-2", "device-3"),
+				linkagesPath + "?cursor=c&limit=1000": linkages("", "device-4"),
+			},
+			wantHasMore:  true,
+			wantIDs:      []string{"device-3", "device-4"},
+			wantRequests: []string{linkagesPath + "?limit=1000", linkagesPath + "?cursor=c&limit=1000"},
+		},
+		"success: relationship without links": {
+			rel: &MDMServerRelationshipsDevices{
+				Data: inline,
+				Meta: &PagingInformation{Paging: PagingInformationPaging{NextCursor: "cursor-1", Total: 6}},
+			},
+		},
+		"success: all linkages inline": {
+			rel: &MDMServerRelationshipsDevices{
+				Data:  inline,
+				Links: &RelationshipLinks{Self: linkagesPath},
+				Meta:  &PagingInformation{Paging: PagingInformationPaging{Limit: 2, Total: 2}},
+			},
+		},
+		"success: nil relationship": {},
+		"success: relative related link under a prefixed base URL": {
+			basePath: "/gateway/abm/",
+			rel: &MDMServerRelationshipsDevices{
+				Data:  inline,
+				Links: &RelationshipLinks{Related: "/v1/mdmServers/server-1/devices"},
+				Meta:  &PagingInformation{Paging: PagingInformationPaging{Limit: 2, NextCursor: "cursor-1", Total: 3}},
+			},
+			pages: map[string]string{
+				"/gateway/abm/v1/mdmServers/server-1/devices?cursor=cursor-1&limit=1000": linkages("", "device-3"),
+			},
+			wantHasMore:  true,
+			wantIDs:      []string{"device-3"},
+			wantRequests: []string{"/gateway/abm/v1/mdmServers/server-1/devices?cursor=cursor-1&limit=1000"},
+		},
+		"error: API error": {
+			rel: &MDMServerRelationshipsDevices{
+				Data:  inline,
+				Links: &RelationshipLinks{Self: linkagesPath},
+				Meta:  &PagingInformation{Paging: PagingInformationPaging{NextCursor: "expired", Total: 6}},
+			},
+			wantHasMore:  true,
+			wantRequests: []string{linkagesPath + "?cursor=expired&limit=1000"},
+			wantErr:      "abm api error: status=404",
+		},
+		"error: link escapes base URL": {
+			rel: &MDMServerRelationshipsDevices{
+				Data:  inline,
+				Links: &RelationshipLinks{Self: "https://elsewhere.example/v1/mdmServers/server-1/relationships/devices"},
+				Meta:  &PagingInformation{Paging: PagingInformationPaging{NextCursor: "cursor-1", Total: 6}},
+			},
+			wantHasMore: true,
+			wantErr:     "escapes base URL",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			var (
+				mu       sync.Mutex
+				requests []string
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				key := r.URL.Path + "?" + r.URL.RawQuery
+				mu.Lock()
+				requests = append(requests, key)
+				mu.Unlock()
+
+				body, ok := tt.pages[key]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, body)
+			}))
+			t.Cleanup(server.Close)
+
+			tokenSource := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token"})
+			client, err := NewClientWithBaseURL(server.Client(), tokenSource, server.URL+tt.basePath)
+			if err != nil {
+				t.Fatalf("NewClientWithBaseURL: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.wantHasMore, tt.rel.HasMore()); diff != "" {
+				t.Fatalf("HasMore mismatch (-want +got):\n%s", diff)
+			}
+
+			var (
+				ids    []string
+				gotErr error
+			)
+			for linkage, err := range client.ContinueDeviceLinkages(ctx, tt.rel) {
+				if err != nil {
+					gotErr = err
+					break
+				}
+				ids = append(ids, linkage.ID)
+			}
+			if (gotErr != nil) != (tt.wantErr != "") || (gotErr != nil && !strings.Contains(gotErr.Error(), tt.wantErr)) {
+				t.Fatalf("ContinueDeviceLinkages error = %v, want %q", gotErr, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantIDs, ids); diff != "" {
+				t.Fatalf("linkage IDs mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantRequests, requests); diff != "" {
+				t.Fatalf("requests mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOrgDevicesService_Raw(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	// Unknown members, unusual spacing, and an escaped NUL must all survive.
+	const deviceBody = `{"data": {"id":"device-1","type":"orgDevices","attributes":{"serialNumber":"SN\u0000","futureField":{"nested":[1,2]}}},
+  "links":{"self":"/v1/orgDevices/device-1"}}` + "\n"
+	const devicesBody = `{"data":[{"id":"device-1","type":"orgDevices","unknown":true}],"links":{"self":"/v1/orgDevices"},"meta":{"paging":{"limit":1,"total":9}}}`
+
+	tests := map[string]struct {
+		opts    []ClientOption
+		status  int
+		call    func(ctx context.Context, client *Client) (string, []byte, error)
+		body    string
+		wantID  string
+		wantErr bool
+	}{
+		"success: GetRaw": {
+			body: deviceBody,
+			call: func(ctx context.Context, client *Client) (string, []byte, error) {
+				response, raw, err := client.OrgDevices().GetRaw(ctx, "device-1", nil)
+				if err != nil {
+					return "", raw, err
+				}
+				return response.Data.ID, raw, nil
+			},
+			wantID: "device-1",
+		},
+		"success: GetRaw keeps the body as sent when sanitizing": {
+			opts: []ClientOption{WithSanitizeStrings()},
+			body: deviceBody,
+			call: func(ctx context.Context, client *Client) (string, []byte, error) {
+				response, raw, err := client.OrgDevices().GetRaw(ctx, "device-1", nil)
+				if err != nil {
+					return "", raw, err
+				}
+				return response.Data.Attributes.SerialNumber, raw, nil
+			},
+			wantID: "SN",
+		},
+		"success: ListRaw": {
+			body: devicesBody,
+			call: func(ctx context.Context, client *Client) (string, []byte, error) {
+				response, raw, err := client.OrgDevices().ListRaw(ctx, &GetOrgDevicesOptions{Limit: 1})
+				if err != nil {
+					return "", raw, err
+				}
+				return response.Data[0].ID, raw, nil
+			},
+			wantID: "device-1",
+		},
+		"error: API error returns no raw body": {
+			status: http.StatusNotFound,
+			body:   `{"errors":[{"code":"NOT_FOUND","detail":"device not found","status":"404","title":"Not Found"}]}`,
+			call: func(ctx context.Context, client *Client) (string, []byte, error) {
+				_, raw, err := client.OrgDevices().GetRaw(ctx, "device-1", nil)
+				return "", raw, err
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+				fmt.Fprint(w, tt.body)
+			}))
+			t.Cleanup(server.Close)
+
+			id, raw, err := tt.call(ctx, testClientForServer(t, server, tt.opts...))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if raw != nil {
+					t.Fatalf("raw body = %q, want nil", raw)
+				}
+				return
+			}
+			if diff := cmp.Diff(tt.body, string(raw)); diff != "" {
+				t.Fatalf("raw body mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantID, id); diff != "" {
+				t.Fatalf("typed result mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-json-experiment/json"
+)
+
+// WithSnapshotCache keeps the complete device list fetched by
+// [Client.FetchOrgDevices] as gzip-compressed JSON in dir, and serves later
+// fetches with the same fields and filters from it, without any network
+// request, while the snapshot is younger than maxAge. This suits command-line
+// tools on unreliable networks. Use [ForceRefresh] to bypass a fresh snapshot.
+//
+// Snapshots are keyed by the client identity given with
+// [WithSnapshotCacheIdentity], the base URL, fields, and filters, and replaced
+// atomically. The directory must exist, maxAge must be positive, and the
+// identity must be set.
+func WithSnapshotCache(dir string, maxAge time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.snapshotCacheDir = dir
+		o.snapshotCacheMaxAge = maxAge
+	}
+}
+
+// WithSnapshotCacheIdentity sets the identity, such as the API account's
+// client ID, that keys the snapshots of [WithSnapshotCache], so that clients
+// for different organizations or credentials sharing a cache directory never
+// serve each other's device lists. [NewClientFromConfig] uses the client ID
+// unless this option is given.
+func WithSnapshotCacheIdentity(identity string) ClientOption {
+	return func(o *clientOptions) {
+		o.snapshotCacheIdentity = identity
+	}
+}
+
+// FetchedOrgDevices is the complete organization device list returned by
+// [Client.FetchOrgDevices].
+type FetchedOrgDevices struct {
+	Devices []OrgDevice
+
+	// FetchedAt is when the devices were fetched from the API.
+	FetchedAt time.Time
+
+	// Stale reports whether the devices were served from the snapshot cache
+	// set up with [WithSnapshotCache] instead of being fetched now.
+	Stale bool
+}
+
+// FetchOrgDevices returns all organization devices matching options,
+// following pagination until all pages are consumed.
+//
+// With [WithSnapshotCache], a fresh enough snapshot for the same fields and
+// filters is returned instead, with Stale set and bounded by [WithMaxItems]
+// like a crawl, and a fetched device list replaces the snapshot. When writing the snapshot fails, the fetched devices
+// are returned together with the error. A crawl bounded with [WithMaxItems]
+// that stops early returns the devices collected so far with
+// [ErrMaxItemsReached] and is not cached.
+func (c *Client) FetchOrgDevices(ctx context.Context, options *GetOrgDevicesOptions, opts ...CrawlOption) (*FetchedOrgDevices, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var fields []string
+	var limit int
+	if options != nil {
+		fields = options.Fields
+		limit = options.Limit
+	}
+	query, err := buildFieldsAndLimitQuery("fields[orgDevices]", fields, c.crawlLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	if err := setOrgDevicesFilterQuery(query, options); err != nil {
+		return nil, err
+	}
+
+	crawl := newCrawlOptions(opts)
+	cache := c.snapshotCache
+	key := c.snapshotKey(query)
+	if cache != nil && !crawl.forceRefresh {
+		if cached, ok := cache.load(key); ok {
+			if c.stableOrdering {
+				SortOrgDevices(cached.Devices)
+			}
+			cached.Devices, err = appendPage(nil, cached.Devices, crawl.maxItems)
+			return cached, err
+		}
+	}
+
+	now := time.Now
+	if cache != nil {
+		now = cache.now
+	}
+	result := &FetchedOrgDevices{FetchedAt: now()}
+	for page, err := range crawlPages(ctx, c, orgDevicesPath, query, func(r *OrgDevicesResponse) string { return r.Links.Next }) {
+		if err != nil {
+			return nil, err
+		}
+		result.Devices, err = appendPage(result.Devices, page.Data, crawl.maxItems)
+		if err != nil {
+			return result, err
+		}
+	}
+	if c.stableOrdering {
+		SortOrgDevices(result.Devices)
+	}
+
+	if cache != nil {
+		if err := cache.store(key, result); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// snapshotKey identifies the device list requested with query for the
+// cache's identity. The page size does not change the list and is left out.
+func (c *Client) snapshotKey(query url.Values) string {
+	keyed := url.Values{}
+	for name, values := range query {
+		if name != "limit" {
+			keyed[name] = values
+		}
+	}
+
+	var identity string
+	if c.snapshotCache != nil {
+		identity = c.snapshotCache.identity
+	}
+
+	return identity + " " + c.baseURL.JoinPath(orgDevicesPath).String() + "?" + keyed.Encode()
+}
+
+// snapshotCache stores device list snapshots in a directory, see [WithSnapshotCache].
+type snapshotCache struct {
+	dir      string
+	maxAge   time.Duration
+	identity string
+	now      func() time.Time
+}
+
+// snapshotCacheEntry is the content of a snapshot cache file.
+type snapshotCacheEntry struct {
+	Key       string      `json:"key"`
+	FetchedAt time.Time   `json:"fetchedAt"`
+	Devices   []OrgDevice `json:"devices"`
+}
+
+// path returns the file holding the snapshot for key.
+func (s *snapshotCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, "orgdevices-"+hex.EncodeToString(sum[:16])+".json.gz")
+}
+
+// load returns the snapshot for key when one exists and is younger than
+// maxAge. A missing, unreadable, or corrupted file is a miss, so the caller
+// falls back to the network and replaces it.
+func (s *snapshotCache) load(key string) (*FetchedOrgDevices, bool) {
+	f, err := os.Open(s.path(key))
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, false
+	}
+	var entry snapshotCacheEntry
+	if err := json.UnmarshalRead(zr, &entry); err != nil {
+		return nil, false
+	}
+	if entry.Key != key || s.now().Sub(entry.FetchedAt) > s.maxAge {
+		return nil, false
+	}
+
+	return &FetchedOrgDevices{Devices: entry.Devices, FetchedAt: entry.FetchedAt, Stale: true}, true
+}
+
+// store replaces the snapshot for key with result. The file is written to a
+// temporary file first and renamed into place, so readers never see a
+// partially written snapshot.
+func (s *snapshotCache) store(key string, result *FetchedOrgDevices) error {
+	path := s.path(key)
+	tmp, err := os.CreateTemp(s.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create snapshot cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	zw := gzip.NewWriter(tmp)
+	entry := snapshotCacheEntry{Key: key, FetchedAt: result.FetchedAt, Devices: result.Devices}
+	err = errors.Join(json.MarshalWrite(zw, entry), zw.Close())
+	if err = errors.Join(err, tmp.Close()); err != nil {
+		return fmt.Errorf("write snapshot cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replace snapshot cache: %w", err)
+	}
+
+	return nil
+}
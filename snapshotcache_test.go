@@ -0,0 +1,288 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/oauth2"
+)
+
+// newCountingFleetServer serves pageCount pages of pageSize org devices and
+// counts the requests it receives.
+func newCountingFleetServer(t *testing.T, pageCount, pageSize int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	requests := new(atomic.Int32)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		pageNumber := 1
+		if page := r.URL.Query().Get("page"); page != "" {
+			pageNumber, _ = strconv.Atoi(page)
+		}
+		nextLink := ""
+		if pageNumber < pageCount {
+			nextLink = fmt.Sprintf("/v1/orgDevices?page=%d", pageNumber+1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(buildOrgDevicesPageJSON(pageNumber, pageSize, nextLink))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, requests
+}
+
+func TestClient_FetchOrgDevicesSnapshotCache(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	const (
+		pageCount = 3
+		pageSize  = 4
+		maxAge    = time.Hour
+	)
+	serials := &GetOrgDevicesOptions{Fields: []string{"serialNumber"}}
+	colors := &GetOrgDevicesOptions{Fields: []string{"serialNumber"}, Color: "RED"}
+
+	type fetch struct {
+		// identity is the client's snapshot cache identity; empty means "org-a".
+		identity    string
+		options     *GetOrgDevicesOptions
+		opts        []CrawlOption
+		advance     time.Duration
+		corrupt     bool
+		wantStale   bool
+		wantFetch   bool
+		wantErr     error
+		wantDevices int
+	}
+
+	tests := map[string]struct {
+		fetches []fetch
+	}{
+		"success: miss then hit": {
+			fetches: []fetch{
+				{options: serials, wantFetch: true},
+				{options: serials, advance: maxAge - time.Minute, wantStale: true},
+			},
+		},
+		"success: expired snapshot is refetched": {
+			fetches: []fetch{
+				{options: serials, wantFetch: true},
+				{options: serials, advance: maxAge + time.Minute, wantFetch: true},
+				{options: serials, wantStale: true},
+			},
+		},
+		"success: options are cached separately": {
+			fetches: []fetch{
+				{options: serials, wantFetch: true},
+				{options: colors, wantFetch: true},
+				{options: &GetOrgDevicesOptions{Fields: []string{"serialNumber"}, Limit: 2}, wantStale: true},
+				{options: colors, wantStale: true},
+			},
+		},
+		"success: force refresh bypasses fresh snapshot": {
+			fetches: []fetch{
+				{options: serials, wantFetch: true},
+				{options: serials, opts: []CrawlOption{ForceRefresh()}, wantFetch: true},
+			},
+		},
+		"success: identities are cached separately": {
+			fetches: []fetch{
+				{options: serials, wantFetch: true},
+				{identity: "org-b", options: serials, wantFetch: true},
+				{options: serials, wantStale: true},
+				{identity: "org-b", options: serials, wantStale: true},
+			},
+		},
+		"success: max items bounds a cache hit": {
+			fetches: []fetch{
+				{options: serials, wantFetch: true},
+				{options: serials, opts: []CrawlOption{WithMaxItems(5)}, wantStale: true, wantErr: ErrMaxItemsReached, wantDevices: 5},
+				{options: serials, opts: []CrawlOption{WithMaxItems(pageCount * pageSize)}, wantStale: true},
+			},
+		},
+		"success: corrupted snapshot falls back to network": {
+			fetches: []fetch{
+				{options: serials, wantFetch: true},
+				{options: serials, corrupt: true, wantFetch: true},
+				{options: serials, wantStale: true},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server, requests := newCountingFleetServer(t, pageCount, pageSize)
+			dir := t.TempDir()
+			now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+			clients := map[string]*Client{}
+			clientFor := func(identity string) *Client {
+				if identity == "" {
+					identity = "org-a"
+				}
+				client, ok := clients[identity]
+				if !ok {
+					client = testClientForServer(t, server, WithSnapshotCache(dir, maxAge), WithSnapshotCacheIdentity(identity))
+					client.snapshotCache.now = func() time.Time { return now }
+					clients[identity] = client
+				}
+				return client
+			}
+
+			var first *FetchedOrgDevices
+			for i, f := range tt.fetches {
+				now = now.Add(f.advance)
+				if f.corrupt {
+					files, err := filepath.Glob(filepath.Join(dir, "orgdevices-*.json.gz"))
+					if err != nil || len(files) != 1 {
+						t.Fatalf("cache files = %v, %v; want one", files, err)
+					}
+					if err := os.WriteFile(files[0], []byte("not gzip"), 0o600); err != nil {
+						t.Fatalf("corrupt cache file: %v", err)
+					}
+				}
+
+				before := requests.Load()
+				got, err := clientFor(f.identity).FetchOrgDevices(ctx, f.options, f.opts...)
+				if !errors.Is(err, f.wantErr) {
+					t.Fatalf("fetch %d: FetchOrgDevices error = %v, want %v", i, err, f.wantErr)
+				}
+				if diff := cmp.Diff(f.wantStale, got.Stale); diff != "" {
+					t.Fatalf("fetch %d: stale mismatch (-want +got):\n%s", i, diff)
+				}
+				if diff := cmp.Diff(f.wantFetch, requests.Load() > before); diff != "" {
+					t.Fatalf("fetch %d: network use mismatch (-want +got):\n%s", i, diff)
+				}
+				wantDevices := f.wantDevices
+				if wantDevices == 0 {
+					wantDevices = pageCount * pageSize
+				}
+				if diff := cmp.Diff(wantDevices, len(got.Devices)); diff != "" {
+					t.Fatalf("fetch %d: device count mismatch (-want +got):\n%s", i, diff)
+				}
+				if first == nil {
+					first = got
+					continue
+				}
+				if diff := cmp.Diff(first.Devices[:wantDevices], got.Devices); diff != "" {
+					t.Fatalf("fetch %d: devices mismatch (-want +got):\n%s", i, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestSnapshotCache_AtomicWrite(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	dir := t.TempDir()
+	cache := &snapshotCache{dir: dir, maxAge: time.Hour, now: time.Now}
+	const key = "https://example.com/v1/orgDevices?"
+	devices := make([]OrgDevice, 500)
+	for i := range devices {
+		devices[i] = OrgDevice{ID: fmt.Sprintf("device-%d", i), Type: "orgDevices", Attributes: &OrgDeviceAttributes{SerialNumber: fmt.Sprintf("SN%06d", i)}}
+	}
+
+	if err := cache.store(key, &FetchedOrgDevices{Devices: devices, FetchedAt: time.Now()}); err != nil {
+		t.Fatalf("store returned error: %v", err)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		done    = make(chan struct{})
+		partial atomic.Int32
+	)
+	wg.Go(func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			got, ok := cache.load(key)
+			if !ok || len(got.Devices) != len(devices) {
+				partial.Add(1)
+			}
+		}
+	})
+	for range 20 {
+		if err := cache.store(key, &FetchedOrgDevices{Devices: devices, FetchedAt: time.Now()}); err != nil {
+			t.Errorf("store returned error: %v", err)
+		}
+	}
+	close(done)
+	wg.Wait()
+
+	if got := partial.Load(); got != 0 {
+		t.Fatalf("reader saw %d missing or partial snapshots", got)
+	}
+	leftovers, err := filepath.Glob(filepath.Join(dir, "*.tmp"))
+	if err != nil {
+		t.Fatalf("glob: %v", err)
+	}
+	if len(leftovers) != 0 {
+		t.Fatalf("temporary files left behind: %v", leftovers)
+	}
+}
+
+func TestWithSnapshotCache_InvalidMaxAge(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tokenSource := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token"})
+	_, err := NewClient(nil, tokenSource, WithSnapshotCache(t.TempDir(), 0))
+	if diff := cmp.Diff("snapshot cache max age must be positive: 0s", fmt.Sprint(err)); diff != "" {
+		t.Fatalf("error mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWithSnapshotCache_MissingIdentity(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tokenSource := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token"})
+	_, err := NewClient(nil, tokenSource, WithSnapshotCache(t.TempDir(), time.Hour))
+	if diff := cmp.Diff("snapshot cache identity is required", fmt.Sprint(err)); diff != "" {
+		t.Fatalf("error mismatch (-want +got):\n%s", diff)
+	}
+}
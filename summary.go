@@ -0,0 +1,259 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// summaryConcurrency bounds the number of MDM servers whose devices
+// [Client.GetMDMServerSummaries] counts at the same time.
+const summaryConcurrency = 4
+
+// MDMServerSummary describes an MDM server and the number of devices assigned to it.
+type MDMServerSummary struct {
+	ID              string
+	Name            string
+	ServerType      MDMServerType
+	CreatedDateTime time.Time
+	DeviceCount     int
+
+	// Err is set when the server's devices could not be counted, in which
+	// case DeviceCount is zero.
+	Err error
+}
+
+// GetMDMServerSummaries lists every MDM server with the number of devices
+// assigned to it, in the order the API returns the servers, or by name with
+// [WithStableOrdering].
+//
+// Device counts come from the meta.paging.total of a single one-item page of
+// each server's device linkages. When the API omits the total the linkages
+// are paged through and counted instead. Servers are counted concurrently; a
+// server whose count fails is still included, with its Err set.
+//
+// The total reported by the API may lag behind activities that completed
+// moments earlier, so a count can be briefly stale. Use
+// [MDMServersService.DeviceLinkages] when the exact set of devices matters.
+func (c *Client) GetMDMServerSummaries(ctx context.Context) ([]MDMServerSummary, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	servers, err := c.listMDMServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]MDMServerSummary, len(servers))
+	for i, server := range servers {
+		summaries[i].ID = server.ID
+		if attrs := server.Attributes; attrs != nil {
+			summaries[i].Name = attrs.ServerName
+			summaries[i].ServerType = attrs.ServerType
+			summaries[i].CreatedDateTime = attrs.CreatedDateTime
+		}
+	}
+	if c.stableOrdering {
+		slices.SortStableFunc(summaries, compareMDMServerSummaries)
+	}
+
+	// A failed count is reported in the summary and does not stop the others.
+	err = forEachConcurrent(ctx, len(summaries), summaryConcurrency, func(ctx context.Context, i int) error {
+		summaries[i].DeviceCount, summaries[i].Err = c.countMDMServerDevices(ctx, summaries[i].ID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
+
+// MDMServerDeviceCounts returns the number of devices assigned to each MDM
+// server, keyed by server ID.
+//
+// Counts are taken like [Client.GetMDMServerSummaries] does: from the
+// meta.paging.total of a single one-item page of the server's device
+// linkages, and by paging through the linkages only when the API omits the
+// total. Servers are counted at most concurrency at a time; zero or negative
+// means 4. The first failed count cancels the remaining ones and its error is
+// returned.
+func (c *Client) MDMServerDeviceCounts(ctx context.Context, concurrency int) (map[string]int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if concurrency <= 0 {
+		concurrency = summaryConcurrency
+	}
+
+	serverIDs, err := c.listMDMServerIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceCounts := make([]int, len(serverIDs))
+	err = forEachConcurrent(ctx, len(serverIDs), concurrency, func(ctx context.Context, i int) error {
+		count, err := c.countMDMServerDevices(ctx, serverIDs[i])
+		if err != nil {
+			return fmt.Errorf("mdm server %q: %w", serverIDs[i], err)
+		}
+		deviceCounts[i] = count
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(serverIDs))
+	for i, id := range serverIDs {
+		counts[id] = deviceCounts[i]
+	}
+
+	return counts, nil
+}
+
+// listMDMServers returns every MDM server, in the order the API returns them.
+func (c *Client) listMDMServers(ctx context.Context) ([]MDMServer, error) {
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(maxPageLimit))
+
+	var servers []MDMServer
+	for page, err := range crawlPages(ctx, c, mdmServersPath, query, func(r *MDMServersResponse) string { return r.Links.Next }) {
+		if err != nil {
+			return nil, err
+		}
+		servers = append(servers, page.Data...)
+	}
+
+	return servers, nil
+}
+
+// listMDMServerIDs returns the ID of every MDM server, in the order the API
+// returns them.
+func (c *Client) listMDMServerIDs(ctx context.Context) ([]string, error) {
+	servers, err := c.listMDMServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(servers))
+	for i, server := range servers {
+		ids[i] = server.ID
+	}
+
+	return ids, nil
+}
+
+// countMDMServerDevices returns the number of devices assigned to the MDM server.
+func (c *Client) countMDMServerDevices(ctx context.Context, mdmServerID string) (int, error) {
+	first, err := c.MDMServers().DeviceLinkages(ctx, mdmServerID, &GetMDMServerDeviceLinkagesOptions{Limit: 1})
+	if err != nil {
+		return 0, err
+	}
+	if first.Meta != nil && first.Meta.Paging.Total > 0 {
+		return first.Meta.Paging.Total, nil
+	}
+	if len(first.Data) == 0 && first.Links.Next == "" {
+		return 0, nil
+	}
+
+	count := 0
+	for _, err := range c.MDMServers().Devices(ctx, mdmServerID) {
+		if err != nil {
+			return 0, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// OrgDeviceCountCrawled returns the number of organization devices matching
+// options by crawling every page and counting the devices without retaining
+// them. Use it when the server does not populate the paging total in the
+// response metadata. Pages are requested at the maximum size unless
+// options.Limit is set.
+func (c *Client) OrgDeviceCountCrawled(ctx context.Context, options *GetOrgDevicesOptions) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	var fields []string
+	limit := maxPageLimit
+	if options != nil {
+		fields = options.Fields
+		if options.Limit != 0 {
+			limit = options.Limit
+		}
+	}
+	query, err := buildFieldsAndLimitQuery("fields[orgDevices]", fields, limit)
+	if err != nil {
+		return 0, err
+	}
+	if err := setOrgDevicesFilterQuery(query, options); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for page, err := range crawlPages(ctx, c, orgDevicesPath, query, func(r *OrgDevicesResponse) string { return r.Links.Next }) {
+		if err != nil {
+			return 0, err
+		}
+		count += len(page.Data)
+	}
+
+	return count, nil
+}
+
+// GetOrgDevicesWithCount gets a page of organization devices like
+// [OrgDevicesService.List], and makes sure its meta.paging.total holds the
+// number of devices matching options. It makes two requests: a one-item page
+// to read the total, then the page described by options. When the second
+// page carries its own total, that more recent total is kept.
+func (c *Client) GetOrgDevicesWithCount(ctx context.Context, options *GetOrgDevicesOptions) (*OrgDevicesResponse, error) {
+	var countOptions GetOrgDevicesOptions
+	if options != nil {
+		countOptions = *options
+	}
+	limit := countOptions.Limit
+	countOptions.Limit = 1
+	countResponse, err := c.OrgDevices().List(ctx, &countOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.OrgDevices().List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	if countResponse.Meta != nil && countResponse.Meta.Paging.Total != 0 {
+		if response.Meta == nil {
+			response.Meta = &PagingInformation{Paging: PagingInformationPaging{Limit: limit}}
+		}
+		if response.Meta.Paging.Total == 0 {
+			response.Meta.Paging.Total = countResponse.Meta.Paging.Total
+		}
+	}
+
+	return response, nil
+}
@@ -0,0 +1,421 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-json-experiment/json"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestClient_GetMDMServerSummaries(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	created := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	// Devices per server; "srv-total" reports meta.paging.total, the others do not.
+	devices := map[string]int{"srv-total": 1500, "srv-counted": 5, "srv-empty": 0}
+
+	var mu sync.Mutex
+	linkageRequests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/v1/mdmServers" {
+			// Serve the server list over two pages.
+			if r.URL.Query().Get("cursor") == "" {
+				fmt.Fprintf(w, `{"data":[`+
+					`{"id":"srv-total","type":"mdmServers","attributes":{"serverName":"Jamf","serverType":"MDM","createdDateTime":%q}},`+
+					`{"id":"srv-counted","type":"mdmServers","attributes":{"serverName":"Kandji","serverType":"MDM"}}`+
+					`],"links":{"self":"/v1/mdmServers","next":"/v1/mdmServers?cursor=2"}}`, created.Format(time.RFC3339))
+				return
+			}
+			fmt.Fprint(w, `{"data":[`+
+				`{"id":"srv-broken","type":"mdmServers","attributes":{"serverName":"Broken","serverType":"MDM"}},`+
+				`{"id":"srv-empty","type":"mdmServers","attributes":{"serverName":"Empty","serverType":"APPLE_CONFIGURATOR"}}`+
+				`],"links":{"self":"/v1/mdmServers?cursor=2"}}`)
+			return
+		}
+
+		serverID, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/v1/mdmServers/"), "/relationships/devices")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		linkageRequests[serverID]++
+		mu.Unlock()
+
+		if serverID == "srv-broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"errors":[{"status":"500","code":"INTERNAL_ERROR","title":"boom","detail":"boom"}]}`)
+			return
+		}
+
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+		end := min(offset+limit, devices[serverID])
+		response := MDMServerDevicesLinkagesResponse{Links: PagedDocumentLinks{Self: r.URL.String()}}
+		for i := offset; i < end; i++ {
+			response.Data = append(response.Data, MDMServerDevicesLinkageData{ID: fmt.Sprintf("%s-device-%d", serverID, i), Type: "orgDevices"})
+		}
+		if end < devices[serverID] {
+			response.Links.Next = fmt.Sprintf("%s?limit=%d&cursor=%d", r.URL.Path, limit, end)
+		}
+		if serverID == "srv-total" {
+			response.Meta = &PagingInformation{Paging: PagingInformationPaging{Limit: limit, Total: devices[serverID]}}
+		}
+		json.MarshalWrite(w, response)
+	}))
+	t.Cleanup(server.Close)
+	client := testClientForServer(t, server)
+
+	got, err := client.GetMDMServerSummaries(ctx)
+	if err != nil {
+		t.Fatalf("GetMDMServerSummaries returned error: %v", err)
+	}
+
+	want := []MDMServerSummary{
+		{ID: "srv-total", Name: "Jamf", ServerType: "MDM", CreatedDateTime: created, DeviceCount: 1500},
+		{ID: "srv-counted", Name: "Kandji", ServerType: "MDM", DeviceCount: 5},
+		{ID: "srv-broken", Name: "Broken", ServerType: "MDM"},
+		{ID: "srv-empty", Name: "Empty", ServerType: "APPLE_CONFIGURATOR"},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(MDMServerSummary{}, "Err")); diff != "" {
+		t.Fatalf("summaries mismatch (-want +got):\n%s", diff)
+	}
+	for _, summary := range got {
+		if (summary.Err != nil) != (summary.ID == "srv-broken") {
+			t.Fatalf("summary %s has Err = %v", summary.ID, summary.Err)
+		}
+	}
+
+	// srv-total: one limit=1 request. srv-counted: the limit=1 probe plus
+	// one full page. srv-empty: the probe alone shows there are no devices.
+	wantRequests := map[string]int{"srv-total": 1, "srv-counted": 2, "srv-broken": 1, "srv-empty": 1}
+	if diff := cmp.Diff(wantRequests, linkageRequests); diff != "" {
+		t.Fatalf("linkage request count mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestClient_OrgDeviceCountCrawled(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		pageCount int
+		pageSize  int
+		options   *GetOrgDevicesOptions
+		want      int
+		wantErr   bool
+	}{
+		"success: multiple pages": {
+			pageCount: 4,
+			pageSize:  25,
+			want:      100,
+		},
+		"success: single empty page": {
+			pageCount: 1,
+			pageSize:  0,
+			want:      0,
+		},
+		"success: explicit limit": {
+			pageCount: 3,
+			pageSize:  10,
+			options:   &GetOrgDevicesOptions{Limit: 10},
+			want:      30,
+		},
+		"error: limit too large": {
+			pageCount: 1,
+			pageSize:  1,
+			options:   &GetOrgDevicesOptions{Limit: maxPageLimit + 1},
+			wantErr:   true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server := newOrgDevicesFleetServer(t, tt.pageCount, tt.pageSize)
+			client := testClientForServer(t, server)
+
+			got, err := client.OrgDeviceCountCrawled(ctx, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("OrgDeviceCountCrawled error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("count mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_GetOrgDevicesWithCount(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		options      *GetOrgDevicesOptions
+		pageMeta     string
+		wantQueries  []string
+		wantIDs      []string
+		wantMeta     *PagingInformation
+		wantErr      bool
+		failRequests bool
+	}{
+		"success: total from the count request": {
+			options:     &GetOrgDevicesOptions{Limit: 3},
+			wantQueries: []string{"limit=1", "limit=3"},
+			wantIDs:     []string{"page-0", "page-1", "page-2"},
+			wantMeta:    &PagingInformation{Paging: PagingInformationPaging{Limit: 3, Total: 42}},
+		},
+		"success: filters apply to both requests": {
+			options:     &GetOrgDevicesOptions{Limit: 2, Color: "BLACK"},
+			wantQueries: []string{"filter%5Bcolor%5D=BLACK&limit=1", "filter%5Bcolor%5D=BLACK&limit=2"},
+			wantIDs:     []string{"page-0", "page-1"},
+			wantMeta:    &PagingInformation{Paging: PagingInformationPaging{Limit: 2, Total: 42}},
+		},
+		"success: page total is kept": {
+			options:     &GetOrgDevicesOptions{Limit: 2},
+			pageMeta:    `,"meta":{"paging":{"limit":2,"total":43}}`,
+			wantQueries: []string{"limit=1", "limit=2"},
+			wantIDs:     []string{"page-0", "page-1"},
+			wantMeta:    &PagingInformation{Paging: PagingInformationPaging{Limit: 2, Total: 43}},
+		},
+		"success: nil options": {
+			wantQueries: []string{"limit=1", ""},
+			wantIDs:     []string{"page-0"},
+			wantMeta:    &PagingInformation{Paging: PagingInformationPaging{Total: 42}},
+		},
+		"error: count request fails": {
+			options:      &GetOrgDevicesOptions{Limit: 3},
+			failRequests: true,
+			wantQueries:  []string{"limit=1"},
+			wantErr:      true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			var (
+				mu      sync.Mutex
+				queries []string
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				queries = append(queries, r.URL.RawQuery)
+				mu.Unlock()
+
+				if tt.failRequests {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+				if limit == 1 {
+					fmt.Fprint(w, `{"data":[{"id":"count-0","type":"orgDevices"}],"links":{"self":"/v1/orgDevices"},"meta":{"paging":{"limit":1,"total":42}}}`)
+					return
+				}
+				items := make([]string, max(limit, 1))
+				for i := range items {
+					items[i] = fmt.Sprintf(`{"id":"page-%d","type":"orgDevices"}`, i)
+				}
+				fmt.Fprintf(w, `{"data":[%s],"links":{"self":"/v1/orgDevices"}%s}`, strings.Join(items, ","), tt.pageMeta)
+			}))
+			t.Cleanup(server.Close)
+
+			client := testClientForServer(t, server)
+			got, err := client.GetOrgDevicesWithCount(ctx, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetOrgDevicesWithCount error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantQueries, queries); diff != "" {
+				t.Fatalf("request queries mismatch (-want +got):\n%s", diff)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var ids []string
+			for _, device := range got.Data {
+				ids = append(ids, device.ID)
+			}
+			if diff := cmp.Diff(tt.wantIDs, ids); diff != "" {
+				t.Fatalf("device IDs mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantMeta, got.Meta); diff != "" {
+				t.Fatalf("meta mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_MDMServerDeviceCounts(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	const serverCount = 24
+
+	// newServer serves serverCount MDM servers, where server i has i*7
+	// devices and even servers report meta.paging.total. The broken server,
+	// if any, fails its linkage requests.
+	newServer := func(t *testing.T, broken string) (*httptest.Server, func() map[string]int) {
+		t.Helper()
+
+		var mu sync.Mutex
+		linkageRequests := make(map[string]int)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+
+			if r.URL.Path == "/v1/mdmServers" {
+				var response MDMServersResponse
+				for i := range serverCount {
+					response.Data = append(response.Data, MDMServer{ID: "srv-" + strconv.Itoa(i), Type: "mdmServers"})
+				}
+				json.MarshalWrite(w, response)
+				return
+			}
+
+			serverID, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/v1/mdmServers/"), "/relationships/devices")
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			mu.Lock()
+			linkageRequests[serverID]++
+			mu.Unlock()
+
+			if serverID == broken {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"errors":[{"status":"400","code":"PARAMETER_ERROR.INVALID","title":"bad","detail":"bad"}]}`)
+				return
+			}
+
+			n, _ := strconv.Atoi(strings.TrimPrefix(serverID, "srv-"))
+			devices := n * 7
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			offset, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+			end := min(offset+limit, devices)
+			response := MDMServerDevicesLinkagesResponse{Links: PagedDocumentLinks{Self: r.URL.String()}}
+			for i := offset; i < end; i++ {
+				response.Data = append(response.Data, MDMServerDevicesLinkageData{ID: fmt.Sprintf("%s-device-%d", serverID, i), Type: "orgDevices"})
+			}
+			if end < devices {
+				response.Links.Next = fmt.Sprintf("%s?limit=%d&cursor=%d", r.URL.Path, limit, end)
+			}
+			if n%2 == 0 {
+				response.Meta = &PagingInformation{Paging: PagingInformationPaging{Limit: limit, Total: devices}}
+			}
+			json.MarshalWrite(w, response)
+		}))
+		t.Cleanup(server.Close)
+
+		return server, func() map[string]int {
+			mu.Lock()
+			defer mu.Unlock()
+			return maps.Clone(linkageRequests)
+		}
+	}
+
+	tests := map[string]struct {
+		concurrency int
+		broken      string
+		wantErr     string
+	}{
+		"success: default concurrency": {
+			concurrency: 0,
+		},
+		"success: high concurrency": {
+			concurrency: 16,
+		},
+		"error: failed count": {
+			concurrency: 4,
+			broken:      "srv-5",
+			wantErr:     `mdm server "srv-5"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server, linkageRequests := newServer(t, tt.broken)
+			client := testClientForServer(t, server)
+
+			got, err := client.MDMServerDeviceCounts(ctx, tt.concurrency)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("MDMServerDeviceCounts error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MDMServerDeviceCounts returned error: %v", err)
+			}
+
+			want := make(map[string]int, serverCount)
+			wantRequests := make(map[string]int, serverCount)
+			for i := range serverCount {
+				id := "srv-" + strconv.Itoa(i)
+				want[id] = i * 7
+				// The one-item page answers for even servers, by its total or,
+				// for the empty srv-0, by having no next link; odd servers
+				// also need one full page.
+				wantRequests[id] = 2
+				if i%2 == 0 {
+					wantRequests[id] = 1
+				}
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Fatalf("counts mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(wantRequests, linkageRequests()); diff != "" {
+				t.Fatalf("linkage request count mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
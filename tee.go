@@ -0,0 +1,66 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+)
+
+// ResponseTee receives a copy of the body of an API response, exactly as it
+// was received (after any Content-Encoding such as gzip is undone) and before
+// it is decoded, for example to persist responses for lineage. endpoint is
+// the request path below the client's base URL, such as "v1/orgDevices", and
+// raw is the tee's own copy, which it may retain. A tee may be called
+// concurrently when the client is used from several goroutines.
+type ResponseTee func(endpoint string, raw []byte)
+
+// WithResponseTee sets a tee called once for every successful HTTP response
+// of the client's JSON requests, including every page of a crawl and every
+// attempt of a retried request that got a response. Responses with an error
+// status are left out unless [WithResponseTeeErrors] is also given. Bodies
+// streamed to the caller, as by [OrgDevicesService.GetReader], are not teed.
+func WithResponseTee(tee ResponseTee) ClientOption {
+	return func(o *clientOptions) {
+		o.responseTee = tee
+	}
+}
+
+// WithResponseTeeErrors makes the tee set with [WithResponseTee] also receive
+// the bodies of responses with an error status.
+func WithResponseTeeErrors() ClientOption {
+	return func(o *clientOptions) {
+		o.responseTeeErrors = true
+	}
+}
+
+// teeResponse passes a copy of payload, the body of a response from
+// endpoint, to the client's response tee, if any. ok reports whether the
+// response had a successful status.
+func (c *Client) teeResponse(endpoint string, ok bool, payload []byte) {
+	if c.responseTee == nil || (!ok && !c.responseTeeErrors) {
+		return
+	}
+
+	c.responseTee(endpoint, bytes.Clone(payload))
+}
+
+// endpoint returns the path of u below the client's base URL.
+func (c *Client) endpoint(u *url.URL) string {
+	return strings.TrimPrefix(u.Path, c.baseURL.Path)
+}
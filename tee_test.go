@@ -0,0 +1,152 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// teeRecord is a call of a [ResponseTee] recorded by a test.
+type teeRecord struct {
+	Endpoint string
+	Raw      string
+}
+
+func TestWithResponseTee(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	const (
+		deviceBody = `{"data": {"id":"device-1","type":"orgDevices","unknownMember":{"b":2,"a":1}},"links":{"self":"/v1/orgDevices/device-1"}}` + "\n"
+		page1      = `{"data":[{"id":"device-1","type":"orgDevices","attributes":{"partNumber":"P1"}}],"links":{"self":"/v1/orgDevices","next":"/v1/orgDevices?cursor=2"}}`
+		page2      = `{"data":[{"id":"device-2","type":"orgDevices","attributes":{"partNumber":"P2"}}],"links":{"self":"/v1/orgDevices","next":"/v1/orgDevices?cursor=3"}}`
+		page3      = `{"data":[{"id":"device-3","type":"orgDevices","attributes":{"partNumber":"P3"}}],"links":{"self":"/v1/orgDevices"}}`
+		errorBody  = `{"errors":[{"code":"NOT_FOUND","detail":"device not found","status":"404","title":"Not Found"}]}`
+	)
+	pages := []teeRecord{{"v1/orgDevices", page1}, {"v1/orgDevices", page2}, {"v1/orgDevices", page3}}
+
+	tests := map[string]struct {
+		opts []ClientOption
+		call func(t *testing.T, client *Client)
+		want []teeRecord
+	}{
+		"success: gzip-encoded response is teed decoded": {
+			call: func(t *testing.T, client *Client) {
+				if _, err := client.OrgDevices().Get(t.Context(), "gzip", nil); err != nil {
+					t.Fatalf("Get: %v", err)
+				}
+			},
+			want: []teeRecord{{"v1/orgDevices/gzip", deviceBody}},
+		},
+		"success: once per page of a crawl": {
+			call: func(t *testing.T, client *Client) {
+				if _, err := client.FetchOrgDevices(t.Context(), nil); err != nil {
+					t.Fatalf("FetchOrgDevices: %v", err)
+				}
+			},
+			want: pages,
+		},
+		"success: once per page of FetchOrgDevicePartNumbers": {
+			call: func(t *testing.T, client *Client) {
+				if _, err := client.FetchOrgDevicePartNumbers(t.Context()); err != nil {
+					t.Fatalf("FetchOrgDevicePartNumbers: %v", err)
+				}
+			},
+			want: pages,
+		},
+		"success: error status is not teed by default": {
+			call: func(t *testing.T, client *Client) {
+				if _, err := client.OrgDevices().Get(t.Context(), "missing", nil); err == nil {
+					t.Fatal("Get: want error")
+				}
+			},
+		},
+		"success: error status is teed when asked": {
+			opts: []ClientOption{WithResponseTeeErrors()},
+			call: func(t *testing.T, client *Client) {
+				if _, err := client.OrgDevices().Get(t.Context(), "missing", nil); err == nil {
+					t.Fatal("Get: want error")
+				}
+			},
+			want: []teeRecord{{"v1/orgDevices/missing", errorBody}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.URL.Path == "/v1/orgDevices/gzip":
+					if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+						t.Errorf("Accept-Encoding = %q, want gzip", r.Header.Get("Accept-Encoding"))
+					}
+					w.Header().Set("Content-Encoding", "gzip")
+					gz := gzip.NewWriter(w)
+					fmt.Fprint(gz, deviceBody)
+					gz.Close()
+				case r.URL.Path == "/v1/orgDevices/missing":
+					w.WriteHeader(http.StatusNotFound)
+					fmt.Fprint(w, errorBody)
+				case r.URL.Query().Get("cursor") == "2":
+					fmt.Fprint(w, page2)
+				case r.URL.Query().Get("cursor") == "3":
+					fmt.Fprint(w, page3)
+				default:
+					fmt.Fprint(w, page1)
+				}
+			}))
+			t.Cleanup(server.Close)
+
+			var (
+				mu  sync.Mutex
+				got []teeRecord
+			)
+			tee := func(endpoint string, raw []byte) {
+				mu.Lock()
+				got = append(got, teeRecord{Endpoint: endpoint, Raw: string(raw)})
+				mu.Unlock()
+
+				// The tee owns raw; scribbling over it must not affect decoding.
+				for i := range raw {
+					raw[i] = 0
+				}
+			}
+			client := testClientForServer(t, server, append(tt.opts, WithResponseTee(tee))...)
+
+			tt.call(t, client)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("teed responses mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+// requestIDHeaders lists the response headers that may carry the request ID
+// Apple support asks for, in order of preference.
+var requestIDHeaders = []string{"X-Request-Id", "X-Apple-Request-Uuid"}
+
+// responseRequestID returns the request ID from the headers of resp, or "".
+func responseRequestID(resp *http.Response) string {
+	for _, key := range requestIDHeaders {
+		if id := resp.Header.Get(key); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// Attempt describes a single HTTP attempt recorded in an [AttemptTrace].
+type Attempt struct {
+	Method string `json:"method"`
+
+	// URL is the request URL, with any password redacted.
+	URL string `json:"url"`
+
+	Start time.Time `json:"start"`
+
+	// Duration is encoded as a duration string such as "1.5ms".
+	Duration time.Duration `json:"duration"`
+
+	// StatusCode is the response status, or zero when no response arrived.
+	StatusCode int `json:"statusCode,omitzero"`
+
+	// Error describes why the attempt failed, such as a transport error or
+	// an error status. It is empty for a successful attempt.
+	Error string `json:"error,omitzero"`
+
+	// RetryReason is why the failure was considered transient, such as
+	// "retryable status". It is empty for a successful attempt and for a
+	// failure that is never retried. A transient failure is not retried once
+	// the retries are used up.
+	RetryReason string `json:"retryReason,omitzero"`
+
+	// RequestID is the request ID response header, if any.
+	RequestID string `json:"requestId,omitzero"`
+}
+
+// attemptJSON is the JSON form of [Attempt], with the duration encoded as a
+// duration string.
+type attemptJSON struct {
+	Method      string       `json:"method"`
+	URL         string       `json:"url"`
+	Start       time.Time    `json:"start"`
+	Duration    jsonDuration `json:"duration"`
+	StatusCode  int          `json:"statusCode,omitzero"`
+	Error       string       `json:"error,omitzero"`
+	RetryReason string       `json:"retryReason,omitzero"`
+	RequestID   string       `json:"requestId,omitzero"`
+}
+
+// MarshalJSONTo implements [json.MarshalerTo].
+func (a Attempt) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return json.MarshalEncode(enc, attemptJSON{
+		Method:      a.Method,
+		URL:         a.URL,
+		Start:       a.Start,
+		Duration:    jsonDuration(a.Duration),
+		StatusCode:  a.StatusCode,
+		Error:       a.Error,
+		RetryReason: a.RetryReason,
+		RequestID:   a.RequestID,
+	})
+}
+
+// UnmarshalJSONFrom implements [json.UnmarshalerFrom].
+func (a *Attempt) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	var v attemptJSON
+	if err := json.UnmarshalDecode(dec, &v); err != nil {
+		return err
+	}
+
+	*a = Attempt{
+		Method:      v.Method,
+		URL:         v.URL,
+		Start:       v.Start,
+		Duration:    time.Duration(v.Duration),
+		StatusCode:  v.StatusCode,
+		Error:       v.Error,
+		RetryReason: v.RetryReason,
+		RequestID:   v.RequestID,
+	}
+	return nil
+}
+
+// AttemptTrace records every HTTP attempt of client calls made with a
+// context returned by [WithAttemptTrace], including retries and the pages of
+// a crawl, for example to attach to a support request. Only the data in
+// [Attempt] is kept: no headers other than the request ID, no tokens, and no
+// bodies. The zero value is ready to use and safe for concurrent use.
+type AttemptTrace struct {
+	mu       sync.Mutex
+	attempts []Attempt
+}
+
+type attemptTraceKey struct{}
+
+// WithAttemptTrace returns a copy of ctx that makes client calls made with it
+// record their HTTP attempts in trace. Calls made without it record nothing.
+func WithAttemptTrace(ctx context.Context, trace *AttemptTrace) context.Context {
+	return context.WithValue(ctx, attemptTraceKey{}, trace)
+}
+
+// attemptTraceFrom returns the trace set on ctx with [WithAttemptTrace], or nil.
+func attemptTraceFrom(ctx context.Context) *AttemptTrace {
+	trace, _ := ctx.Value(attemptTraceKey{}).(*AttemptTrace)
+	return trace
+}
+
+// Attempts returns the recorded attempts in the order they started.
+func (t *AttemptTrace) Attempts() []Attempt {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return slices.Clone(t.attempts)
+}
+
+// WriteJSON writes the recorded attempts to w as an indented JSON array.
+func (t *AttemptTrace) WriteJSON(w io.Writer) error {
+	attempts := t.Attempts()
+	if attempts == nil {
+		attempts = []Attempt{}
+	}
+	if err := json.MarshalWrite(w, attempts, json.Deterministic(true)); err != nil {
+		return fmt.Errorf("write attempt trace: %w", err)
+	}
+
+	return nil
+}
+
+// record appends an attempt started at start. resp is nil when no response
+// arrived.
+func (t *AttemptTrace) record(method, requestURL string, start time.Time, resp *http.Response, err error, retryReason string) {
+	attempt := Attempt{
+		Method:      method,
+		URL:         redactURL(requestURL),
+		Start:       start,
+		Duration:    time.Since(start),
+		RetryReason: retryReason,
+	}
+	if resp != nil {
+		attempt.StatusCode = resp.StatusCode
+		attempt.RequestID = responseRequestID(resp)
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.attempts = append(t.attempts, attempt)
+}
+
+// redactURL returns rawURL with any password replaced, as [url.URL.Redacted] does.
+func redactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	return parsed.Redacted()
+}
@@ -0,0 +1,191 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-json-experiment/json"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestWithAttemptTrace(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		crawl func(*Client, *AttemptTrace) error
+		want  []Attempt
+	}{
+		"success: retried paginated crawl": {
+			crawl: func(c *Client, trace *AttemptTrace) error {
+				_, err := c.OrgDeviceCountCrawled(WithAttemptTrace(ctx, trace), nil)
+				return err
+			},
+			want: []Attempt{
+				{Method: http.MethodGet, URL: "/v1/orgDevices?limit=1000", StatusCode: http.StatusServiceUnavailable, Error: "abm api error: status=503 (request id: req-1)", RetryReason: retryReasonStatus, RequestID: "req-1"},
+				{Method: http.MethodGet, URL: "/v1/orgDevices?limit=1000", StatusCode: http.StatusOK, RequestID: "req-2"},
+				{Method: http.MethodGet, URL: "/v1/orgDevices?page=2", StatusCode: http.StatusOK, RequestID: "req-3"},
+			},
+		},
+		"success: retried part numbers crawl": {
+			crawl: func(c *Client, trace *AttemptTrace) error {
+				_, err := c.FetchOrgDevicePartNumbers(WithAttemptTrace(ctx, trace))
+				return err
+			},
+			want: []Attempt{
+				{Method: http.MethodGet, URL: "/v1/orgDevices?limit=100", StatusCode: http.StatusServiceUnavailable, Error: "abm api error: status=503 (request id: req-1)", RetryReason: retryReasonStatus, RequestID: "req-1"},
+				{Method: http.MethodGet, URL: "/v1/orgDevices?limit=100", StatusCode: http.StatusOK, RequestID: "req-2"},
+				{Method: http.MethodGet, URL: "/v1/orgDevices?page=2", StatusCode: http.StatusOK, RequestID: "req-3"},
+			},
+		},
+		"success: untraced call records nothing": {
+			crawl: func(c *Client, trace *AttemptTrace) error {
+				_, err := c.OrgDeviceCountCrawled(ctx, nil)
+				return err
+			},
+			want: nil,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := requests.Add(1)
+				w.Header().Set("X-Request-Id", fmt.Sprintf("req-%d", n))
+				if n == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if r.URL.Query().Get("page") == "" {
+					w.Write(buildOrgDevicesPageJSON(1, 2, "/v1/orgDevices?page=2"))
+					return
+				}
+				w.Write(buildOrgDevicesPageJSON(2, 2, ""))
+			}))
+			t.Cleanup(server.Close)
+			client := testClientForServer(t, server, WithRetryPolicy(RetryPolicy{MaxRetries: 2}))
+
+			trace := new(AttemptTrace)
+			_ = tt.crawl(client, trace)
+
+			got := trace.Attempts()
+			for i := range got {
+				if got[i].Start.IsZero() || got[i].Duration < 0 {
+					t.Errorf("attempt %d has no timing: %+v", i, got[i])
+				}
+				got[i].URL = strings.TrimPrefix(got[i].URL, server.URL)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(Attempt{}, "Start", "Duration")); diff != "" {
+				t.Fatalf("attempts mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAttemptTrace_WriteJSON(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-1")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"errors":[{"code":"PARAMETER_ERROR.INVALID","detail":"secret body detail"}]}`)
+	}))
+	t.Cleanup(server.Close)
+	client := testClientForServer(t, server)
+
+	trace := new(AttemptTrace)
+	if _, err := client.OrgDevices().Get(WithAttemptTrace(ctx, trace), "device-1", nil); err == nil {
+		t.Fatal("expected error")
+	}
+
+	var buf bytes.Buffer
+	if err := trace.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON returned error: %v", err)
+	}
+	for _, secret := range []string{"test-token", "Authorization", "errors"} {
+		if strings.Contains(buf.String(), secret) {
+			t.Errorf("trace JSON contains %q: %s", secret, buf.String())
+		}
+	}
+
+	var raw []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &raw); err != nil {
+		t.Fatalf("decode trace JSON: %v", err)
+	}
+	if len(raw) != 1 {
+		t.Fatalf("trace JSON has %d attempts, want 1: %s", len(raw), buf.String())
+	}
+	duration, ok := raw[0]["duration"].(string)
+	if !ok {
+		t.Fatalf("duration = %#v, want a duration string", raw[0]["duration"])
+	}
+	if diff := cmp.Diff(trace.Attempts()[0].Duration.String(), duration); diff != "" {
+		t.Fatalf("duration mismatch (-want +got):\n%s", diff)
+	}
+
+	var decoded []Attempt
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("decode trace JSON: %v", err)
+	}
+	if diff := cmp.Diff(trace.Attempts(), decoded); diff != "" {
+		t.Fatalf("round-tripped attempts mismatch (-want +got):\n%s", diff)
+	}
+
+	var empty bytes.Buffer
+	if err := new(AttemptTrace).WriteJSON(&empty); err != nil {
+		t.Fatalf("WriteJSON returned error: %v", err)
+	}
+	if diff := cmp.Diff("[]", empty.String()); diff != "" {
+		t.Fatalf("empty trace mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestAttemptTraceFrom_NoAllocs(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if attemptTraceFrom(ctx) != nil {
+			t.Fatal("unexpected trace")
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("attemptTraceFrom allocated %v times without a trace", allocs)
+	}
+}
@@ -17,7 +17,11 @@
 package abm
 
 import (
+	"fmt"
+	"net/url"
 	"time"
+
+	"github.com/go-json-experiment/json"
 )
 
 // OrgDevicesResponse contains a list of organization device resources.
@@ -54,6 +58,18 @@ const (
 	ProductFamilyVision  OrgDeviceAttributesProductFamily = "Vision"
 )
 
+// String returns the product family as the API spells it, such as "iPhone".
+func (f OrgDeviceAttributesProductFamily) String() string {
+	return string(f)
+}
+
+// ParseProductFamily converts s, spelled as the API does, to a product family.
+// It is shorthand for [ParseOrgDeviceAttributesProductFamily] and returns an
+// [*UnknownValueError] when s is not a declared product family.
+func ParseProductFamily(s string) (OrgDeviceAttributesProductFamily, error) {
+	return ParseOrgDeviceAttributesProductFamily(s)
+}
+
 // OrgDeviceAttributesPurchaseSourceType is the purchase source type of an organization device.
 type OrgDeviceAttributesPurchaseSourceType string
 
@@ -63,6 +79,19 @@ const (
 	PurchaseSourceTypeManuallyAdded OrgDeviceAttributesPurchaseSourceType = "MANUALLY_ADDED"
 )
 
+// String returns the purchase source type as the API spells it, such as "APPLE".
+func (t OrgDeviceAttributesPurchaseSourceType) String() string {
+	return string(t)
+}
+
+// ParsePurchaseSourceType converts s, spelled as the API does, to a purchase
+// source type. It is shorthand for [ParseOrgDeviceAttributesPurchaseSourceType]
+// and returns an [*UnknownValueError] when s is not a declared purchase source
+// type.
+func ParsePurchaseSourceType(s string) (OrgDeviceAttributesPurchaseSourceType, error) {
+	return ParseOrgDeviceAttributesPurchaseSourceType(s)
+}
+
 // OrgDeviceAttributesStatus is the assignment status of an organization device.
 type OrgDeviceAttributesStatus string
 
@@ -71,6 +100,11 @@ const (
 	StatusUnAssigned OrgDeviceAttributesStatus = "UNASSIGNED"
 )
 
+// String returns the status as the API spells it, such as "ASSIGNED".
+func (s OrgDeviceAttributesStatus) String() string {
+	return string(s)
+}
+
 // OrgDeviceAttributes contains attributes for an organization device resource.
 type OrgDeviceAttributes struct {
 	AddedToOrgDateTime      time.Time                             `json:"addedToOrgDateTime,omitzero"`
@@ -102,9 +136,22 @@ type OrgDeviceRelationships struct {
 	AppleCareCoverage *OrgDeviceRelationshipsAppleCareCoverage `json:"appleCareCoverage,omitzero"`
 }
 
+// AssignedServerID returns the ID of the MDM server the device is assigned
+// to, or "" if the relationship carries no linkage data. The relationship
+// links name the device, not the server, so the ID is only known when the
+// response includes the linkage; otherwise use
+// [OrgDevicesService.AssignedServerLinkage].
+func (r *OrgDeviceRelationships) AssignedServerID() string {
+	if r == nil || r.AssignedServer == nil || r.AssignedServer.Data == nil {
+		return ""
+	}
+	return r.AssignedServer.Data.ID
+}
+
 // OrgDeviceRelationshipsAssignedServer describes assigned-server relationship links.
 type OrgDeviceRelationshipsAssignedServer struct {
-	Links *RelationshipLinks `json:"links,omitzero"`
+	Links *RelationshipLinks                  `json:"links,omitzero"`
+	Data  *OrgDeviceAssignedServerLinkageData `json:"data,omitzero"`
 }
 
 // OrgDeviceRelationshipsAppleCareCoverage describes apple-care relationship links.
@@ -147,12 +194,40 @@ type MDMServer struct {
 	Type          string                  `json:"type"`
 }
 
+// ServerName returns the server's name, or "" when s or its attributes are nil.
+func (s *MDMServer) ServerName() string {
+	if s == nil || s.Attributes == nil {
+		return ""
+	}
+
+	return s.Attributes.ServerName
+}
+
+// ServerType returns the server's type, or "" when s or its attributes are nil.
+func (s *MDMServer) ServerType() MDMServerType {
+	if s == nil || s.Attributes == nil {
+		return ""
+	}
+
+	return s.Attributes.ServerType
+}
+
+// MDMServerType is the type of an MDM server. Values this package does not
+// declare are decoded and kept as is.
+type MDMServerType string
+
+const (
+	ServerTypeMDM                     MDMServerType = "MDM"
+	ServerTypeAppleConfigurator       MDMServerType = "APPLE_CONFIGURATOR"
+	ServerTypeAppleBusinessEssentials MDMServerType = "APPLE_BUSINESS_ESSENTIALS"
+)
+
 // MDMServerAttributes are fields describing an MDM server.
 type MDMServerAttributes struct {
-	CreatedDateTime time.Time `json:"createdDateTime,omitzero"`
-	ServerName      string    `json:"serverName,omitzero"`
-	ServerType      string    `json:"serverType,omitzero"`
-	UpdatedDateTime time.Time `json:"updatedDateTime,omitzero"`
+	CreatedDateTime time.Time     `json:"createdDateTime,omitzero"`
+	ServerName      string        `json:"serverName,omitzero"`
+	ServerType      MDMServerType `json:"serverType,omitzero"`
+	UpdatedDateTime time.Time     `json:"updatedDateTime,omitzero"`
 }
 
 // MDMServerRelationships contains relationship resources for an MDM server.
@@ -167,6 +242,17 @@ type MDMServerRelationshipsDevices struct {
 	Meta  *PagingInformation                  `json:"meta,omitzero"`
 }
 
+// HasMore reports whether the relationship holds fewer linkages than the
+// server has, according to its meta.paging, and has a link to fetch the rest
+// from with [Client.ContinueDeviceLinkages].
+func (r *MDMServerRelationshipsDevices) HasMore() bool {
+	if r == nil || r.Links == nil || (r.Links.Self == "" && r.Links.Related == "") || r.Meta == nil {
+		return false
+	}
+
+	return r.Meta.Paging.NextCursor != "" || r.Meta.Paging.Total > len(r.Data)
+}
+
 // MDMServerRelationshipsDevicesData is an org-device linkage in an MDM-server relationship.
 type MDMServerRelationshipsDevicesData struct {
 	ID   string `json:"id"`
@@ -188,8 +274,82 @@ type MDMServerDevicesLinkageData struct {
 
 // OrgDeviceActivityResponse contains a single org-device activity resource.
 type OrgDeviceActivityResponse struct {
-	Data  OrgDeviceActivity `json:"data"`
-	Links DocumentLinks     `json:"links"`
+	Data     OrgDeviceActivity           `json:"data"`
+	Included []OrgDeviceActivityIncluded `json:"included,omitempty"`
+	Links    DocumentLinks               `json:"links"`
+
+	// CorrelationID echoes the CorrelationID of the OrgDeviceActivityCreateRequest
+	// that created the activity. It is set locally by [ActivitiesService.Create]
+	// and is never returned by the API.
+	CorrelationID string `json:"-"`
+}
+
+// IncludedOrgDevices returns the organization devices embedded in the response.
+func (r *OrgDeviceActivityResponse) IncludedOrgDevices() []OrgDevice {
+	var devices []OrgDevice
+	for _, included := range r.Included {
+		if included.OrgDevice != nil {
+			devices = append(devices, *included.OrgDevice)
+		}
+	}
+
+	return devices
+}
+
+// IncludedMDMServers returns the MDM servers embedded in the response.
+func (r *OrgDeviceActivityResponse) IncludedMDMServers() []MDMServer {
+	var servers []MDMServer
+	for _, included := range r.Included {
+		if included.MDMServer != nil {
+			servers = append(servers, *included.MDMServer)
+		}
+	}
+
+	return servers
+}
+
+// OrgDeviceActivityIncluded is a related resource embedded in an org-device activity response.
+// Depending on Type, either OrgDevice or MDMServer is set; both are nil for unknown types.
+type OrgDeviceActivityIncluded struct {
+	Type      string
+	OrgDevice *OrgDevice
+	MDMServer *MDMServer
+}
+
+// UnmarshalJSON decodes an included resource based on its "type" member.
+func (i *OrgDeviceActivityIncluded) UnmarshalJSON(data []byte) error {
+	var identifier struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &identifier); err != nil {
+		return fmt.Errorf("decode included resource type: %w", err)
+	}
+
+	*i = OrgDeviceActivityIncluded{Type: identifier.Type}
+	switch identifier.Type {
+	case orgDevicesResourceType:
+		i.OrgDevice = new(OrgDevice)
+		return json.Unmarshal(data, i.OrgDevice)
+	case mdmServersResourceType:
+		i.MDMServer = new(MDMServer)
+		return json.Unmarshal(data, i.MDMServer)
+	default:
+		return nil
+	}
+}
+
+// MarshalJSON encodes the included resource as its underlying JSON:API resource object.
+func (i OrgDeviceActivityIncluded) MarshalJSON() ([]byte, error) {
+	switch {
+	case i.OrgDevice != nil:
+		return json.Marshal(i.OrgDevice)
+	case i.MDMServer != nil:
+		return json.Marshal(i.MDMServer)
+	default:
+		return json.Marshal(struct {
+			Type string `json:"type"`
+		}{Type: i.Type})
+	}
 }
 
 // OrgDeviceActivity is an activity resource for assigning or unassigning devices.
@@ -202,13 +362,35 @@ type OrgDeviceActivity struct {
 
 // OrgDeviceActivityAttributes are fields describing an org-device activity.
 type OrgDeviceActivityAttributes struct {
-	CompletedDateTime time.Time `json:"completedDateTime,omitzero"`
-	CreatedDateTime   time.Time `json:"createdDateTime,omitzero"`
-	DownloadURL       string    `json:"downloadUrl,omitzero"`
-	Status            string    `json:"status,omitzero"`
-	SubStatus         string    `json:"subStatus,omitzero"`
+	CompletedDateTime time.Time               `json:"completedDateTime,omitzero"`
+	CreatedDateTime   time.Time               `json:"createdDateTime,omitzero"`
+	DownloadURL       string                  `json:"downloadUrl,omitzero"`
+	Status            OrgDeviceActivityStatus `json:"status,omitzero"`
+	SubStatus         string                  `json:"subStatus,omitzero"`
+
+	// History lists the status transitions of the activity, oldest first,
+	// when the API includes them.
+	History []OrgDeviceActivityHistoryEntry `json:"history,omitempty"`
 }
 
+// OrgDeviceActivityHistoryEntry is a status transition of an org-device activity.
+type OrgDeviceActivityHistoryEntry struct {
+	Status      OrgDeviceActivityStatus `json:"status,omitzero"`
+	Timestamp   time.Time               `json:"timestamp,omitzero"`
+	Description string                  `json:"description,omitzero"`
+}
+
+// OrgDeviceActivityStatus is the status of an org-device activity.
+type OrgDeviceActivityStatus string
+
+// Org-device activity status values.
+const (
+	OrgDeviceActivityStatusInProgress OrgDeviceActivityStatus = "IN_PROGRESS"
+	OrgDeviceActivityStatusCompleted  OrgDeviceActivityStatus = "COMPLETED"
+	OrgDeviceActivityStatusFailed     OrgDeviceActivityStatus = "FAILED"
+	OrgDeviceActivityStatusStopped    OrgDeviceActivityStatus = "STOPPED"
+)
+
 // OrgDeviceActivityType is the type of an org-device activity.
 type OrgDeviceActivityType string
 
@@ -217,9 +399,24 @@ const (
 	OrgDeviceActivityTypeUnassignDevices OrgDeviceActivityType = "UNASSIGN_DEVICES"
 )
 
+// String returns the activity type as the API spells it, such as "ASSIGN_DEVICES".
+func (t OrgDeviceActivityType) String() string {
+	return string(t)
+}
+
 // OrgDeviceActivityCreateRequest is the request payload for creating org-device activities.
 type OrgDeviceActivityCreateRequest struct {
 	Data OrgDeviceActivityCreateRequestData `json:"data"`
+
+	// CorrelationID is a caller-chosen value, such as an internal job ID, that
+	// is echoed on the response so it can be joined with the server-assigned
+	// activity ID. It is kept locally and is not sent to the API.
+	CorrelationID string `json:"-"`
+
+	// AllowOversized sends requests with more than [MaxDevicesPerActivity]
+	// devices instead of rejecting them locally, for when Apple raises the
+	// limit before this package does. It is not sent to the API.
+	AllowOversized bool `json:"-"`
 }
 
 // OrgDeviceActivityCreateRequestData is the data section of activity creation requests.
@@ -294,6 +491,11 @@ const (
 	AppleCareCoverageStatusInactive AppleCareCoverageStatus = "INACTIVE"
 )
 
+// String returns the status as the API spells it, such as "ACTIVE".
+func (s AppleCareCoverageStatus) String() string {
+	return string(s)
+}
+
 // AppleCareCoverageAttributes contains AppleCare coverage attributes.
 type AppleCareCoverageAttributes struct {
 	AgreementNumber        string                       `json:"agreementNumber,omitzero"`
@@ -331,6 +533,52 @@ type PagedDocumentLinks struct {
 	Self  string `json:"self"`
 }
 
+// SelfURL parses the self link. It returns nil and no error when the link is empty.
+func (l DocumentLinks) SelfURL() (*url.URL, error) {
+	return parseLink("self", l.Self)
+}
+
+// SelfURL parses the self link. It returns nil and no error when the link is empty.
+func (l ResourceLinks) SelfURL() (*url.URL, error) {
+	return parseLink("self", l.Self)
+}
+
+// SelfURL parses the self link. It returns nil and no error when the link is empty.
+func (l PagedDocumentLinks) SelfURL() (*url.URL, error) {
+	return parseLink("self", l.Self)
+}
+
+// FirstURL parses the first-page link. It returns nil and no error when the link is empty.
+func (l PagedDocumentLinks) FirstURL() (*url.URL, error) {
+	return parseLink("first", l.First)
+}
+
+// NextURL parses the next-page link. It returns nil and no error when the
+// link is empty. A relative link is returned as is; resolve it against
+// [PagedDocumentLinks.SelfURL] or the API base URL before following it.
+func (l PagedDocumentLinks) NextURL() (*url.URL, error) {
+	return parseLink("next", l.Next)
+}
+
+// HasNext reports whether there is a next page.
+func (l PagedDocumentLinks) HasNext() bool {
+	return l.Next != ""
+}
+
+// parseLink parses the named link, returning nil for an empty link.
+func parseLink(name, link string) (*url.URL, error) {
+	if link == "" {
+		return nil, nil
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s link: %w", name, err)
+	}
+
+	return u, nil
+}
+
 // PagingInformation contains pagination metadata.
 type PagingInformation struct {
 	Paging PagingInformationPaging `json:"paging"`
@@ -0,0 +1,490 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-json-experiment/json"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLinks_SelfURL(t *testing.T) {
+	tests := map[string]struct {
+		self        string
+		want        *url.URL
+		wantErrText string
+	}{
+		"success: absolute url": {
+			self: "https://api-business.apple.com/v1/orgDevices?limit=100",
+			want: &url.URL{Scheme: "https", Host: "api-business.apple.com", Path: "/v1/orgDevices", RawQuery: "limit=100"},
+		},
+		"success: relative url": {
+			self: "/v1/mdmServers/server-1",
+			want: &url.URL{Path: "/v1/mdmServers/server-1"},
+		},
+		"success: empty link": {
+			self: "",
+			want: nil,
+		},
+		"error: invalid url": {
+			self:        "https://[::1/v1/orgDevices",
+			wantErrText: "parse self link",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			accessors := map[string]func() (*url.URL, error){
+				"DocumentLinks":      DocumentLinks{Self: tt.self}.SelfURL,
+				"ResourceLinks":      ResourceLinks{Self: tt.self}.SelfURL,
+				"PagedDocumentLinks": PagedDocumentLinks{Self: tt.self}.SelfURL,
+			}
+			for typeName, selfURL := range accessors {
+				got, err := selfURL()
+				if tt.wantErrText != "" {
+					if err == nil || !strings.Contains(err.Error(), tt.wantErrText) {
+						t.Fatalf("%s.SelfURL error = %v, want containing %q", typeName, err, tt.wantErrText)
+					}
+					if got != nil {
+						t.Fatalf("%s.SelfURL = %v, want nil on error", typeName, got)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("%s.SelfURL returned error: %v", typeName, err)
+				}
+				if diff := cmp.Diff(tt.want, got); diff != "" {
+					t.Fatalf("%s.SelfURL mismatch (-want +got):\n%s", typeName, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestPagedDocumentLinks_FirstURLAndNextURL(t *testing.T) {
+	tests := map[string]struct {
+		link        string
+		want        *url.URL
+		wantErrText string
+	}{
+		"success: absolute url": {
+			link: "https://api-business.apple.com/v1/orgDevices?cursor=abc",
+			want: &url.URL{Scheme: "https", Host: "api-business.apple.com", Path: "/v1/orgDevices", RawQuery: "cursor=abc"},
+		},
+		"success: relative url": {
+			link: "/v1/orgDevices?cursor=abc",
+			want: &url.URL{Path: "/v1/orgDevices", RawQuery: "cursor=abc"},
+		},
+		"success: empty link": {
+			link: "",
+			want: nil,
+		},
+		"error: invalid url": {
+			link:        "https://[::1/v1/orgDevices",
+			wantErrText: "link",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			accessors := map[string]func() (*url.URL, error){
+				"FirstURL": PagedDocumentLinks{First: tt.link}.FirstURL,
+				"NextURL":  PagedDocumentLinks{Next: tt.link}.NextURL,
+			}
+			for method, parse := range accessors {
+				got, err := parse()
+				if tt.wantErrText != "" {
+					if err == nil || !strings.Contains(err.Error(), tt.wantErrText) {
+						t.Fatalf("%s error = %v, want containing %q", method, err, tt.wantErrText)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("%s returned error: %v", method, err)
+				}
+				if diff := cmp.Diff(tt.want, got); diff != "" {
+					t.Fatalf("%s mismatch (-want +got):\n%s", method, diff)
+				}
+			}
+
+			if diff := cmp.Diff(tt.link != "", PagedDocumentLinks{Next: tt.link}.HasNext()); diff != "" {
+				t.Fatalf("HasNext mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMDMServer_Accessors(t *testing.T) {
+	tests := map[string]struct {
+		server         *MDMServer
+		wantServerName string
+		wantServerType MDMServerType
+	}{
+		"success: attributes set": {
+			server:         &MDMServer{Attributes: &MDMServerAttributes{ServerName: "Jamf Pro", ServerType: "MDM"}},
+			wantServerName: "Jamf Pro",
+			wantServerType: "MDM",
+		},
+		"success: nil attributes": {
+			server: &MDMServer{ID: "server-1"},
+		},
+		"success: nil server": {
+			server: nil,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.wantServerName, tt.server.ServerName()); diff != "" {
+				t.Fatalf("ServerName mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantServerType, tt.server.ServerType()); diff != "" {
+				t.Fatalf("ServerType mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMDMServerAttributes_ServerTypeDecoding(t *testing.T) {
+	tests := map[string]struct {
+		payload   string
+		want      MDMServerType
+		wantKnown bool
+	}{
+		"success: known type": {
+			payload:   `{"serverName":"Jamf Pro","serverType":"MDM"}`,
+			want:      ServerTypeMDM,
+			wantKnown: true,
+		},
+		"success: unknown type is kept": {
+			payload: `{"serverName":"Future","serverType":"SOMETHING_NEW"}`,
+			want:    "SOMETHING_NEW",
+		},
+		"success: missing type": {
+			payload: `{"serverName":"Bare"}`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			var attributes MDMServerAttributes
+			if err := json.Unmarshal([]byte(tt.payload), &attributes); err != nil {
+				t.Fatalf("decode attributes: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, attributes.ServerType); diff != "" {
+				t.Fatalf("ServerType mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantKnown, attributes.ServerType.IsKnown()); diff != "" {
+				t.Fatalf("IsKnown mismatch (-want +got):\n%s", diff)
+			}
+
+			encoded, err := json.Marshal(attributes)
+			if err != nil {
+				t.Fatalf("encode attributes: %v", err)
+			}
+			if diff := cmp.Diff(tt.payload, string(encoded)); diff != "" {
+				t.Fatalf("round trip mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseProductFamily(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		family OrgDeviceAttributesProductFamily
+		want   string
+	}{
+		"success: iPhone":  {family: ProductFamilyIPhone, want: "iPhone"},
+		"success: iPad":    {family: ProductFamilyIPad, want: "iPad"},
+		"success: Mac":     {family: ProductFamilyMac, want: "Mac"},
+		"success: AppleTV": {family: ProductFamilyAppleTV, want: "AppleTV"},
+		"success: Watch":   {family: ProductFamilyWatch, want: "Watch"},
+		"success: Vision":  {family: ProductFamilyVision, want: "Vision"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, tt.family.String()); diff != "" {
+				t.Fatalf("String mismatch (-want +got):\n%s", diff)
+			}
+			got, err := ParseProductFamily(tt.family.String())
+			if err != nil {
+				t.Fatalf("ParseProductFamily(%q) returned error: %v", tt.family.String(), err)
+			}
+			if diff := cmp.Diff(tt.family, got); diff != "" {
+				t.Fatalf("round trip mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if diff := cmp.Diff(len(OrgDeviceAttributesProductFamilyValues()), len(tests)); diff != "" {
+		t.Fatalf("product families not all covered (-want +got):\n%s", diff)
+	}
+
+	for _, s := range []string{"Toaster", "iphone", ""} {
+		got, err := ParseProductFamily(s)
+		var unknown *UnknownValueError
+		if !errors.As(err, &unknown) {
+			t.Fatalf("ParseProductFamily(%q) error = %v, want *UnknownValueError", s, err)
+		}
+		if got != "" {
+			t.Fatalf("ParseProductFamily(%q) = %q, want empty", s, got)
+		}
+	}
+}
+
+func TestParsePurchaseSourceType(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		source OrgDeviceAttributesPurchaseSourceType
+		want   string
+	}{
+		"success: APPLE":          {source: PurchaseSourceTypeApple, want: "APPLE"},
+		"success: RESELLER":       {source: PurchaseSourceTypeReseller, want: "RESELLER"},
+		"success: MANUALLY_ADDED": {source: PurchaseSourceTypeManuallyAdded, want: "MANUALLY_ADDED"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, tt.source.String()); diff != "" {
+				t.Fatalf("String mismatch (-want +got):\n%s", diff)
+			}
+			got, err := ParsePurchaseSourceType(tt.source.String())
+			if err != nil {
+				t.Fatalf("ParsePurchaseSourceType(%q) returned error: %v", tt.source.String(), err)
+			}
+			if diff := cmp.Diff(tt.source, got); diff != "" {
+				t.Fatalf("round trip mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if diff := cmp.Diff(len(OrgDeviceAttributesPurchaseSourceTypeValues()), len(tests)); diff != "" {
+		t.Fatalf("purchase source types not all covered (-want +got):\n%s", diff)
+	}
+
+	for _, s := range []string{"STORE", "apple", ""} {
+		got, err := ParsePurchaseSourceType(s)
+		var unknown *UnknownValueError
+		if !errors.As(err, &unknown) {
+			t.Fatalf("ParsePurchaseSourceType(%q) error = %v, want *UnknownValueError", s, err)
+		}
+		if got != "" {
+			t.Fatalf("ParsePurchaseSourceType(%q) = %q, want empty", s, got)
+		}
+	}
+}
+
+func TestParseOrgDeviceActivityType(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		activityType OrgDeviceActivityType
+		want         string
+	}{
+		"success: ASSIGN_DEVICES":   {activityType: OrgDeviceActivityTypeAssignDevices, want: "ASSIGN_DEVICES"},
+		"success: UNASSIGN_DEVICES": {activityType: OrgDeviceActivityTypeUnassignDevices, want: "UNASSIGN_DEVICES"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, tt.activityType.String()); diff != "" {
+				t.Fatalf("String mismatch (-want +got):\n%s", diff)
+			}
+			got, err := ParseOrgDeviceActivityType(tt.activityType.String())
+			if err != nil {
+				t.Fatalf("ParseOrgDeviceActivityType(%q) returned error: %v", tt.activityType.String(), err)
+			}
+			if diff := cmp.Diff(tt.activityType, got); diff != "" {
+				t.Fatalf("round trip mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if diff := cmp.Diff(len(OrgDeviceActivityTypeValues()), len(tests)); diff != "" {
+		t.Fatalf("activity types not all covered (-want +got):\n%s", diff)
+	}
+
+	for _, s := range []string{"DELETE_DEVICES", "assign_devices", ""} {
+		got, err := ParseOrgDeviceActivityType(s)
+		var unknown *UnknownValueError
+		if !errors.As(err, &unknown) {
+			t.Fatalf("ParseOrgDeviceActivityType(%q) error = %v, want *UnknownValueError", s, err)
+		}
+		if got != "" {
+			t.Fatalf("ParseOrgDeviceActivityType(%q) = %q, want empty", s, got)
+		}
+	}
+}
+
+func TestParseOrgDeviceAttributesStatus(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		status OrgDeviceAttributesStatus
+		want   string
+	}{
+		"success: ASSIGNED":   {status: StatusAssigned, want: "ASSIGNED"},
+		"success: UNASSIGNED": {status: StatusUnAssigned, want: "UNASSIGNED"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, tt.status.String()); diff != "" {
+				t.Fatalf("String mismatch (-want +got):\n%s", diff)
+			}
+			got, err := ParseOrgDeviceAttributesStatus(tt.status.String())
+			if err != nil {
+				t.Fatalf("ParseOrgDeviceAttributesStatus(%q) returned error: %v", tt.status.String(), err)
+			}
+			if diff := cmp.Diff(tt.status, got); diff != "" {
+				t.Fatalf("round trip mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if diff := cmp.Diff(len(OrgDeviceAttributesStatusValues()), len(tests)); diff != "" {
+		t.Fatalf("statuses not all covered (-want +got):\n%s", diff)
+	}
+
+	for _, s := range []string{"RELEASED", "assigned", ""} {
+		got, err := ParseOrgDeviceAttributesStatus(s)
+		var unknown *UnknownValueError
+		if !errors.As(err, &unknown) {
+			t.Fatalf("ParseOrgDeviceAttributesStatus(%q) error = %v, want *UnknownValueError", s, err)
+		}
+		if got != "" {
+			t.Fatalf("ParseOrgDeviceAttributesStatus(%q) = %q, want empty", s, got)
+		}
+	}
+}
+
+func TestParseAppleCareCoverageStatus(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	tests := map[string]struct {
+		input   string
+		want    AppleCareCoverageStatus
+		wantErr bool
+	}{
+		"success: active": {
+			input: AppleCareCoverageStatusActive.String(),
+			want:  AppleCareCoverageStatusActive,
+		},
+		"success: inactive": {
+			input: AppleCareCoverageStatusInactive.String(),
+			want:  AppleCareCoverageStatusInactive,
+		},
+		"error: wrong case": {
+			input:   "active",
+			wantErr: true,
+		},
+		"error: unknown": {
+			input:   "EXPIRED",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			got, err := ParseAppleCareCoverageStatus(tt.input)
+			if tt.wantErr {
+				var unknown *UnknownValueError
+				if !errors.As(err, &unknown) {
+					t.Fatalf("ParseAppleCareCoverageStatus(%q) error = %v, want *UnknownValueError", tt.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAppleCareCoverageStatus(%q) returned error: %v", tt.input, err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("status mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.input, got.String()); diff != "" {
+				t.Fatalf("String mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
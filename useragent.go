@@ -0,0 +1,79 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+// modulePath is the path of this module, used to find its version in the
+// build information.
+const modulePath = "github.com/zchee/abm"
+
+// defaultUserAgent returns the User-Agent sent by default, "abm-go/<version>",
+// where version is this module's version in the build, or "devel" when the
+// build does not record it.
+var defaultUserAgent = sync.OnceValue(func() string {
+	version := "devel"
+	if info, ok := debug.ReadBuildInfo(); ok {
+		module := &info.Main
+		for _, dep := range info.Deps {
+			if dep.Path == modulePath {
+				module = dep
+				break
+			}
+		}
+		if module.Path == modulePath && module.Version != "" && module.Version != "(devel)" {
+			version = module.Version
+		}
+	}
+
+	return "abm-go/" + version
+})
+
+// WithUserAgent replaces the default User-Agent, "abm-go/<version>", of every
+// request with ua. Use [WithUserAgentSuffix] to keep the default and add an
+// application identifier to it instead.
+func WithUserAgent(ua string) ClientOption {
+	return func(o *clientOptions) {
+		o.userAgent = ua
+	}
+}
+
+// WithUserAgentSuffix appends s, separated by a space, to the User-Agent of
+// every request, which is the default "abm-go/<version>" or the one set with
+// [WithUserAgent]. It lets a tool embedding the client identify itself
+// without hiding the client.
+func WithUserAgentSuffix(s string) ClientOption {
+	return func(o *clientOptions) {
+		o.userAgentSuffix = s
+	}
+}
+
+// resolvedUserAgent returns the User-Agent configured by o.
+func (o *clientOptions) resolvedUserAgent() string {
+	ua := o.userAgent
+	if ua == "" {
+		ua = defaultUserAgent()
+	}
+	if o.userAgentSuffix != "" {
+		ua += " " + o.userAgentSuffix
+	}
+
+	return ua
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClient_UserAgent(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	if !strings.HasPrefix(defaultUserAgent(), "abm-go/") {
+		t.Fatalf("default User-Agent %q does not start with abm-go/", defaultUserAgent())
+	}
+
+	tests := map[string]struct {
+		opts []ClientOption
+		want string
+	}{
+		"success: default": {
+			opts: nil,
+			want: defaultUserAgent(),
+		},
+		"success: override": {
+			opts: []ClientOption{WithUserAgent("inventory-sync/2.1")},
+			want: "inventory-sync/2.1",
+		},
+		"success: suffix": {
+			opts: []ClientOption{WithUserAgentSuffix("inventory-sync/2.1")},
+			want: defaultUserAgent() + " inventory-sync/2.1",
+		},
+		"success: override and suffix": {
+			opts: []ClientOption{WithUserAgentSuffix("inventory-sync/2.1"), WithUserAgent("fleet-tool/1.0")},
+			want: "fleet-tool/1.0 inventory-sync/2.1",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			userAgents := make(chan string, 2)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				userAgents <- r.Header.Get("User-Agent")
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"data":[],"links":{"self":"https://api-business.apple.com/v1/orgDevices"}}`)
+			}))
+			t.Cleanup(server.Close)
+			client := testClientForServer(t, server, tt.opts...)
+
+			if _, err := client.OrgDevices().List(ctx, nil); err != nil {
+				t.Fatalf("List returned error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, <-userAgents); diff != "" {
+				t.Fatalf("List User-Agent mismatch (-want +got):\n%s", diff)
+			}
+
+			if _, err := client.FetchOrgDevicePartNumbers(ctx); err != nil {
+				t.Fatalf("FetchOrgDevicePartNumbers returned error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, <-userAgents); diff != "" {
+				t.Fatalf("FetchOrgDevicePartNumbers User-Agent mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
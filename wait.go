@@ -0,0 +1,137 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	defaultWaitInitialInterval = time.Second
+	defaultWaitMaxInterval     = 30 * time.Second
+	defaultWaitMultiplier      = 2
+)
+
+// WaitOptions controls how [ActivitiesService.Wait] polls an activity.
+//
+// Polling starts at InitialInterval and the interval grows by Multiplier after
+// every poll, up to MaxInterval. Each wait is drawn uniformly from zero to the
+// current interval ("full jitter") so that many waiters do not poll in step.
+// When the activity's SubStatus changes the interval resets to InitialInterval,
+// since progress suggests the activity may finish soon.
+type WaitOptions struct {
+	// InitialInterval is the first polling interval. Zero means one second.
+	InitialInterval time.Duration
+
+	// MaxInterval caps the polling interval. Zero means 30 seconds.
+	MaxInterval time.Duration
+
+	// Multiplier is the factor the interval grows by after each poll. Values
+	// below 1 mean 2.
+	Multiplier float64
+
+	// OnPoll, if set, is called after every poll with the latest activity
+	// snapshot and the interval until the next poll. next is zero when the
+	// activity has finished.
+	OnPoll func(activity *OrgDeviceActivityResponse, next time.Duration)
+}
+
+// activityWaiter polls an org-device activity. Its sleep and jitter functions
+// are replaced in tests.
+type activityWaiter struct {
+	client *Client
+	sleep  func(ctx context.Context, d time.Duration) error
+	jitter func(d time.Duration) time.Duration
+}
+
+func (w *activityWaiter) wait(ctx context.Context, orgDeviceActivityID string, options *WaitOptions) (*OrgDeviceActivityResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var opts WaitOptions
+	if options != nil {
+		opts = *options
+	}
+	if opts.InitialInterval <= 0 {
+		opts.InitialInterval = defaultWaitInitialInterval
+	}
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = defaultWaitMaxInterval
+	}
+	opts.MaxInterval = max(opts.MaxInterval, opts.InitialInterval)
+	if opts.Multiplier < 1 {
+		opts.Multiplier = defaultWaitMultiplier
+	}
+
+	interval := opts.InitialInterval
+	var lastSubStatus string
+	for polls := 0; ; polls++ {
+		activity, err := w.client.Activities().Get(ctx, orgDeviceActivityID, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var (
+			status    OrgDeviceActivityStatus
+			subStatus string
+		)
+		if attrs := activity.Data.Attributes; attrs != nil {
+			status, subStatus = attrs.Status, attrs.SubStatus
+		}
+
+		switch status {
+		case OrgDeviceActivityStatusCompleted:
+			if opts.OnPoll != nil {
+				opts.OnPoll(activity, 0)
+			}
+			return activity, nil
+		case OrgDeviceActivityStatusFailed, OrgDeviceActivityStatusStopped:
+			if opts.OnPoll != nil {
+				opts.OnPoll(activity, 0)
+			}
+			return activity, fmt.Errorf("org device activity %s finished with status %s (subStatus %q)", orgDeviceActivityID, status, subStatus)
+		}
+
+		if polls > 0 && subStatus != lastSubStatus {
+			interval = opts.InitialInterval
+		}
+		lastSubStatus = subStatus
+
+		next := w.jitter(interval)
+		if opts.OnPoll != nil {
+			opts.OnPoll(activity, next)
+		}
+		if err := w.sleep(ctx, next); err != nil {
+			return nil, err
+		}
+
+		interval = min(time.Duration(float64(interval)*opts.Multiplier), opts.MaxInterval)
+	}
+}
+
+// fullJitter returns a uniformly random duration in [0, d].
+func fullJitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+
+	return rand.N(d + 1)
+}
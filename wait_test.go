@@ -0,0 +1,210 @@
+// Copyright 2026 The abm Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package abm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// newActivityServer serves the given (status, subStatus) pairs in order for
+// GET /v1/orgDeviceActivities/{id}, repeating the last one.
+func newActivityServer(t *testing.T, states [][2]string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/orgDeviceActivities/activity-1" {
+			http.NotFound(w, r)
+			return
+		}
+		state := states[min(int(polls.Add(1))-1, len(states)-1)]
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"data":{"id":"activity-1","type":"orgDeviceActivities","attributes":{"status":%q,"subStatus":%q}},"links":{"self":"https://api-business.apple.com/v1/orgDeviceActivities/activity-1"}}`, state[0], state[1])
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &polls
+}
+
+func TestClient_WaitForOrgDeviceActivity(t *testing.T) {
+	tests := map[string]struct {
+		states      [][2]string
+		options     *WaitOptions
+		wantSleeps  []time.Duration
+		wantStatus  OrgDeviceActivityStatus
+		wantPolls   int32
+		wantErrText string
+	}{
+		"success: interval grows exponentially up to the max": {
+			states: [][2]string{
+				{"IN_PROGRESS", "SUBMITTED"},
+				{"IN_PROGRESS", "SUBMITTED"},
+				{"IN_PROGRESS", "SUBMITTED"},
+				{"IN_PROGRESS", "SUBMITTED"},
+				{"IN_PROGRESS", "SUBMITTED"},
+				{"COMPLETED", "COMPLETED_WITH_SUCCESS"},
+			},
+			options:    &WaitOptions{InitialInterval: time.Second, MaxInterval: 5 * time.Second, Multiplier: 2},
+			wantSleeps: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second},
+			wantStatus: OrgDeviceActivityStatusCompleted,
+			wantPolls:  6,
+		},
+		"success: interval resets when subStatus changes": {
+			states: [][2]string{
+				{"IN_PROGRESS", "SUBMITTED"},
+				{"IN_PROGRESS", "SUBMITTED"},
+				{"IN_PROGRESS", "SUBMITTED"},
+				{"IN_PROGRESS", "PROCESSING"},
+				{"IN_PROGRESS", "PROCESSING"},
+				{"COMPLETED", "COMPLETED_WITH_SUCCESS"},
+			},
+			options:    &WaitOptions{InitialInterval: time.Second, MaxInterval: time.Minute, Multiplier: 3},
+			wantSleeps: []time.Duration{time.Second, 3 * time.Second, 9 * time.Second, time.Second, 3 * time.Second},
+			wantStatus: OrgDeviceActivityStatusCompleted,
+			wantPolls:  6,
+		},
+		"success: zero options use the defaults": {
+			states: [][2]string{
+				{"IN_PROGRESS", ""},
+				{"IN_PROGRESS", ""},
+				{"COMPLETED", ""},
+			},
+			wantSleeps: []time.Duration{time.Second, 2 * time.Second},
+			wantStatus: OrgDeviceActivityStatusCompleted,
+			wantPolls:  3,
+		},
+		"success: already completed activity is not slept on": {
+			states:     [][2]string{{"COMPLETED", "COMPLETED_WITH_SUCCESS"}},
+			wantStatus: OrgDeviceActivityStatusCompleted,
+			wantPolls:  1,
+		},
+		"error: failed activity": {
+			states: [][2]string{
+				{"IN_PROGRESS", "SUBMITTED"},
+				{"FAILED", "COMPLETED_WITH_FAILURE"},
+			},
+			wantSleeps:  []time.Duration{time.Second},
+			wantStatus:  OrgDeviceActivityStatusFailed,
+			wantPolls:   2,
+			wantErrText: `finished with status FAILED (subStatus "COMPLETED_WITH_FAILURE")`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("context error: %v", err)
+			}
+
+			server, polls := newActivityServer(t, tt.states)
+			client := testClientForServer(t, server)
+
+			var sleeps, planned []time.Duration
+			var lastStatus OrgDeviceActivityStatus
+			var options WaitOptions
+			if tt.options != nil {
+				options = *tt.options
+			}
+			options.OnPoll = func(activity *OrgDeviceActivityResponse, next time.Duration) {
+				lastStatus = activity.Data.Attributes.Status
+				if next > 0 {
+					planned = append(planned, next)
+				}
+			}
+
+			w := &activityWaiter{
+				client: client,
+				sleep: func(_ context.Context, d time.Duration) error {
+					sleeps = append(sleeps, d)
+					return nil
+				},
+				jitter: func(d time.Duration) time.Duration { return d },
+			}
+			got, err := w.wait(ctx, "activity-1", &options)
+			if tt.wantErrText != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrText) {
+					t.Fatalf("wait error = %v, want containing %q", err, tt.wantErrText)
+				}
+			} else if err != nil {
+				t.Fatalf("wait returned error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.wantStatus, got.Data.Attributes.Status); diff != "" {
+				t.Fatalf("final status mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantStatus, lastStatus); diff != "" {
+				t.Fatalf("OnPoll final status mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantSleeps, sleeps); diff != "" {
+				t.Fatalf("sleep sequence mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantSleeps, planned); diff != "" {
+				t.Fatalf("OnPoll planned interval mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantPolls, polls.Load()); diff != "" {
+				t.Fatalf("poll count mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_WaitForOrgDeviceActivityContextCanceled(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	server, _ := newActivityServer(t, [][2]string{{"IN_PROGRESS", "SUBMITTED"}})
+	client := testClientForServer(t, server)
+
+	ctx, cancel := context.WithCancel(ctx)
+	_, err := client.WaitForOrgDeviceActivity(ctx, "activity-1", &WaitOptions{
+		InitialInterval: time.Millisecond,
+		OnPoll: func(*OrgDeviceActivityResponse, time.Duration) {
+			cancel()
+		},
+	})
+	if !isContextError(err) {
+		t.Fatalf("WaitForOrgDeviceActivity error = %v, want context cancellation", err)
+	}
+}
+
+func TestFullJitter(t *testing.T) {
+	ctx := t.Context()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context error: %v", err)
+	}
+
+	if got := fullJitter(0); got != 0 {
+		t.Fatalf("fullJitter(0) = %v, want 0", got)
+	}
+	for range 100 {
+		if got := fullJitter(time.Second); got < 0 || got > time.Second {
+			t.Fatalf("fullJitter(1s) = %v, want within [0, 1s]", got)
+		}
+	}
+}